@@ -81,7 +81,7 @@ func DBGetUserByID(ctx context.Context, userID int64) (*dtos.UserModel, error) {
 		}
 		query = `
 			SELECT 
-				id, name, 
+				id, name, email, phone_number, push_token, locale,
 				created_at, updated_at, deleted_at
 			FROM users_tab
 			WHERE 
@@ -108,10 +108,13 @@ func DBGetLoanRequestByID(ctx context.Context, loanID int64) (*dtos.LoanRequestM
 		}
 		query = `
 			SELECT 
-				id, user_id,
-				loan_amount, principal_paid_amount, interest_paid_amount,
+				id, user_id, product_id, partner_id,
+				loan_amount, disbursed_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
 			    disbursement_time, tenure_value, tenure_unit, 
-			    status, annual_interest_rate,
+			    status, annual_interest_rate, amortization_method,
+			    grace_period_type, grace_period_value, balloon_amount,
+			    effective_apr, total_cost_of_credit,
 				created_at, updated_at, deleted_at
 			FROM loan_requests_tab
 			WHERE 
@@ -128,6 +131,39 @@ func DBGetLoanRequestByID(ctx context.Context, loanID int64) (*dtos.LoanRequestM
 	return &loanRequestModel, nil
 }
 
+func DBGetLoanRequestsByStatuses(ctx context.Context, statuses []constants.LoanStatus, lastID int64, limit int) ([]dtos.LoanRequestModel, error) {
+	var (
+		loanRequestModels []dtos.LoanRequestModel
+		err               error
+	)
+
+	query, args, err := sqlx.In(`
+			SELECT 
+				id, user_id, product_id, partner_id,
+				loan_amount, disbursed_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+			    disbursement_time, tenure_value, tenure_unit, 
+			    status, annual_interest_rate, amortization_method,
+			    grace_period_type, grace_period_value, balloon_amount,
+			    effective_apr, total_cost_of_credit,
+				created_at, updated_at, deleted_at
+			FROM loan_requests_tab
+			WHERE 
+			    status IN (?)
+			    AND id > ?
+			  	AND deleted_at = 0
+			ORDER BY id
+			LIMIT ?`, statuses, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = getDatabase().SelectContext(ctx, &loanRequestModels, query, args...); err != nil {
+		return nil, err
+	}
+	return loanRequestModels, nil
+}
+
 func DBGetLoanRequestByIDAndUserIDForUpdate(ctx context.Context, tx *sqlx.Tx, loanID, userID int64) (*dtos.LoanRequestModel, error) {
 	var (
 		loanRequestModel dtos.LoanRequestModel
@@ -139,10 +175,13 @@ func DBGetLoanRequestByIDAndUserIDForUpdate(ctx context.Context, tx *sqlx.Tx, lo
 		}
 		query = `
 			SELECT 
-				id, user_id,
-				loan_amount, principal_paid_amount, interest_paid_amount,
+				id, user_id, product_id, partner_id,
+				loan_amount, disbursed_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
 				disbursement_time, tenure_value, tenure_unit, 
-			    status, annual_interest_rate,
+			    status, annual_interest_rate, amortization_method,
+			    grace_period_type, grace_period_value, balloon_amount,
+			    effective_apr, total_cost_of_credit,
 				created_at, updated_at, deleted_at
 			FROM loan_requests_tab
 			WHERE 
@@ -161,6 +200,40 @@ func DBGetLoanRequestByIDAndUserIDForUpdate(ctx context.Context, tx *sqlx.Tx, lo
 	return &loanRequestModel, nil
 }
 
+func DBGetLoanRequestByIDForUpdate(ctx context.Context, tx *sqlx.Tx, loanID int64) (*dtos.LoanRequestModel, error) {
+	var (
+		loanRequestModel dtos.LoanRequestModel
+		err              error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, user_id, product_id, partner_id,
+				loan_amount, disbursed_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				disbursement_time, tenure_value, tenure_unit, 
+			    status, annual_interest_rate, amortization_method,
+			    grace_period_type, grace_period_value, balloon_amount,
+			    effective_apr, total_cost_of_credit,
+				created_at, updated_at, deleted_at
+			FROM loan_requests_tab
+			WHERE 
+			    id = ? 
+			  	AND deleted_at = 0
+			LIMIT 1
+			FOR UPDATE`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(loanRequestModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &loanRequestModel, nil
+}
+
 func DBInsertLoanRequest(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRequestModel) (int64, error) {
 	var (
 		loanID int64
@@ -170,12 +243,18 @@ func DBInsertLoanRequest(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanReque
 		now   = time.Now().UnixMilli()
 		query = `INSERT INTO 
 			loan_requests_tab 
-			(user_id, 
-			 loan_amount, principal_paid_amount,interest_paid_amount, 
+			(user_id, product_id, partner_id,
+			 loan_amount, disbursed_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
 			 disbursement_time, tenure_value, tenure_unit, 
-			 status, annual_interest_rate,
+			 status, annual_interest_rate, amortization_method,
+			 grace_period_type, grace_period_value, balloon_amount,
+			 effective_apr, total_cost_of_credit,
 			 created_at, updated_at, deleted_at) VALUES 
-			(?,
+			(?, ?, ?,
+			 ?, ?,
+			 ?, ?, ?, ?,
+			 ?, ?, ?,
 			 ?, ?, ?,
 			 ?, ?, ?,
 			 ?, ?,
@@ -184,17 +263,23 @@ func DBInsertLoanRequest(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanReque
 
 	if tx != nil {
 		res, err = tx.ExecContext(ctx, query,
-			model.UserID,
-			model.LoanAmount, model.PrincipalPaidAmount, model.InterestPaidAmount,
+			model.UserID, model.ProductID, model.PartnerID,
+			model.LoanAmount, model.DisbursedAmount,
+			model.PrincipalPaidAmount, model.InterestPaidAmount, model.PenaltyPaidAmount, model.FeePaidAmount,
 			model.DisbursementTime, model.TenureValue, model.TenureUnit,
-			model.Status, model.AnnualInterestRate,
+			model.Status, model.AnnualInterestRate, model.AmortizationMethod,
+			model.GracePeriodType, model.GracePeriodValue, model.BalloonAmount,
+			model.EffectiveAPR, model.TotalCostOfCredit,
 			now, now, 0)
 	} else {
 		res, err = getDatabase().ExecContext(ctx, query,
-			model.UserID,
-			model.LoanAmount, model.PrincipalPaidAmount, model.InterestPaidAmount,
+			model.UserID, model.ProductID, model.PartnerID,
+			model.LoanAmount, model.DisbursedAmount,
+			model.PrincipalPaidAmount, model.InterestPaidAmount, model.PenaltyPaidAmount, model.FeePaidAmount,
 			model.DisbursementTime, model.TenureValue, model.TenureUnit,
-			model.Status, model.AnnualInterestRate,
+			model.Status, model.AnnualInterestRate, model.AmortizationMethod,
+			model.GracePeriodType, model.GracePeriodValue, model.BalloonAmount,
+			model.EffectiveAPR, model.TotalCostOfCredit,
 			now, now, 0)
 	}
 
@@ -218,19 +303,19 @@ func DBInsertPayment(ctx context.Context, tx *sqlx.Tx, model *dtos.PaymentModel)
 		now   = time.Now().UnixMilli()
 		query = `INSERT INTO 
 			payments_tab 
-			(user_id, amount, 
+			(user_id, loan_id, amount, 
 			 created_at, updated_at, deleted_at) VALUES 
-			(?, ?,
+			(?, ?, ?,
 			 ?, ?, ?)`
 	)
 
 	if tx != nil {
 		res, err = tx.ExecContext(ctx, query,
-			model.UserID, model.Amount,
+			model.UserID, model.LoanID, model.Amount,
 			now, now, 0)
 	} else {
 		res, err = getDatabase().ExecContext(ctx, query,
-			model.UserID, model.Amount,
+			model.UserID, model.LoanID, model.Amount,
 			now, now, 0)
 	}
 
@@ -245,6 +330,36 @@ func DBInsertPayment(ctx context.Context, tx *sqlx.Tx, model *dtos.PaymentModel)
 	return paymentID, nil
 }
 
+// DBGetPaymentAmountByLoanID sums the loan's payments made within [fromTime, toTime)
+func DBGetPaymentAmountByLoanID(ctx context.Context, loanID, fromTime, toTime int64) (decimal.Decimal, error) {
+	var (
+		amount decimal.NullDecimal
+		err    error
+
+		args = []interface{}{
+			loanID,
+			fromTime,
+			toTime,
+		}
+		query = `
+			SELECT SUM(amount)
+			FROM payments_tab
+			WHERE 
+			    loan_id = ?
+			  	AND created_at >= ?
+			  	AND created_at < ?
+			  	AND deleted_at = 0`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, args...).Scan(&amount); err != nil {
+		return decimal.Zero, err
+	}
+	if !amount.Valid {
+		return decimal.Zero, nil
+	}
+	return amount.Decimal, nil
+}
+
 func DBBatchInsertBillings(ctx context.Context, tx *sqlx.Tx, models []dtos.BillingModel) error {
 	var (
 		err error
@@ -254,22 +369,25 @@ func DBBatchInsertBillings(ctx context.Context, tx *sqlx.Tx, models []dtos.Billi
 	)
 
 	queryTemplate := `INSERT INTO billings_tab 
-		(billing_id, loan_id, payment_id, recurring_index,
-		principal_amount, interest_amount, total_amount,
-		due_time, payment_completed_at, status,
+		(billing_id, loan_id, payment_id, recurring_index, restructure_id,
+		principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+		principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+		capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
 		created_at, updated_at, deleted_at) VALUES %s`
 	insertPlaceholder := `(
+		?, ?, ?, ?, ?,
+		?, ?, ?, ?, ?,
 		?, ?, ?, ?,
-		?, ?, ?,
-		?, ?, ?,
+		?, ?, ?, ?, ?,
 	    ?, ?, ?)`
 
 	for _, model := range models {
 		placeholders = append(placeholders, insertPlaceholder)
 		args = append(args,
-			model.BillingID, model.LoanID, model.PaymentID, model.RecurringIndex,
-			model.PrincipalAmount, model.InterestAmount, model.TotalAmount,
-			model.DueTime, model.PaymentCompletedAt, model.Status,
+			model.BillingID, model.LoanID, model.PaymentID, model.RecurringIndex, model.RestructureID,
+			model.PrincipalAmount, model.InterestAmount, model.PenaltyAmount, model.FeeAmount, model.TotalAmount,
+			model.PrincipalPaidAmount, model.InterestPaidAmount, model.PenaltyPaidAmount, model.FeePaidAmount,
+			model.CapitalisedPenaltyAmount, model.InterestAccruedAmount, model.DueTime, model.PaymentCompletedAt, model.Status,
 			model.CreatedAt, model.UpdatedAt, model.DeletedAt,
 		)
 	}
@@ -282,6 +400,133 @@ func DBBatchInsertBillings(ctx context.Context, tx *sqlx.Tx, models []dtos.Billi
 	return err
 }
 
+func DBBatchInsertLoanFees(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanFeeModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO loan_fees_tab 
+		(loan_id, fee_type,
+		calculation_type, value, amount, charge_type,
+		created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?,
+		?, ?, ?, ?,
+		?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.LoanID, model.FeeType,
+			model.CalculationType, model.Value, model.Amount, model.ChargeType,
+			model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetLoanFeesByLoanID(ctx context.Context, loanID int64) ([]dtos.LoanFeeModel, error) {
+	var (
+		feeModels []dtos.LoanFeeModel
+		err       error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, loan_id, fee_type,
+				calculation_type, value, amount, charge_type,
+				created_at
+			FROM loan_fees_tab
+			WHERE 
+			    loan_id = ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &feeModels, query, args...); err != nil {
+		return nil, err
+	}
+	return feeModels, nil
+}
+
+func DBBatchInsertLoanRequestInstallments(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanRequestInstallmentModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO loan_request_installments_tab 
+		(loan_id, recurring_index,
+		due_time, principal_amount, created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?,
+		?, ?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.LoanID, model.RecurringIndex,
+			model.DueTime, model.PrincipalAmount, model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetLoanRequestInstallmentsByLoanID(ctx context.Context, loanID int64) ([]dtos.LoanRequestInstallmentModel, error) {
+	var (
+		installmentModels []dtos.LoanRequestInstallmentModel
+		err               error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, loan_id, recurring_index,
+				due_time, principal_amount, created_at
+			FROM loan_request_installments_tab
+			WHERE 
+			    loan_id = ?
+			ORDER BY recurring_index`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &installmentModels, query, args...); err != nil {
+		return nil, err
+	}
+	return installmentModels, nil
+}
+
+// DBDeleteLoanRequestInstallmentsByLoanID removes the custom installments of a loan request before they are replaced
+func DBDeleteLoanRequestInstallmentsByLoanID(ctx context.Context, tx *sqlx.Tx, loanID int64) error {
+	var err error
+
+	query := `DELETE FROM loan_request_installments_tab WHERE loan_id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, loanID)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, loanID)
+	}
+	return err
+}
+
 func DBBatchInsertLoanRequestHistories(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanRequestHistory) error {
 	var (
 		err error
@@ -292,8 +537,10 @@ func DBBatchInsertLoanRequestHistories(ctx context.Context, tx *sqlx.Tx, models
 
 	queryTemplate := `INSERT INTO loan_request_histories_tab 
 		(loan_id, principal_paid_amount, 
-		interest_paid_amount, status, created_at) VALUES %s`
+		interest_paid_amount, status, 
+		actor, reason, created_at) VALUES %s`
 	insertPlaceholder := `(
+		?, ?,
 		?, ?,
 		?, ?, ?)`
 
@@ -304,6 +551,8 @@ func DBBatchInsertLoanRequestHistories(ctx context.Context, tx *sqlx.Tx, models
 			model.PrincipalPaidAmount,
 			model.InterestPaidAmount,
 			model.Status,
+			model.Actor,
+			model.Reason,
 			model.CreatedAt,
 		)
 	}
@@ -325,13 +574,14 @@ func DBBatchInsertBillingHistories(ctx context.Context, tx *sqlx.Tx, models []dt
 	)
 
 	queryTemplate := `INSERT INTO billing_histories_tab 
-		(billing_id, payment_completed_at, status, created_at) VALUES %s`
-	insertPlaceholder := `(?, ?, ?, ?)`
+		(billing_id, due_time, payment_completed_at, status, created_at) VALUES %s`
+	insertPlaceholder := `(?, ?, ?, ?, ?)`
 
 	for _, model := range models {
 		placeholders = append(placeholders, insertPlaceholder)
 		args = append(args,
 			model.BillingID,
+			model.DueTime,
 			model.PaymentCompletedAt,
 			model.Status,
 			model.CreatedAt,
@@ -354,19 +604,21 @@ func DBGetOverdueBillings(ctx context.Context, loanID int64) ([]dtos.BillingMode
 		args = []interface{}{
 			loanID,
 			constants.PaymentStatus_Pending,
+			constants.PaymentStatus_PartiallyPaid,
 			time.Now().UnixMilli(),
 		}
 		query = `
 			SELECT 
 				id, billing_id,
-			    loan_id, payment_id, recurring_index,
-				principal_amount, interest_amount, total_amount,
-				due_time, payment_completed_at, status,
+			    loan_id, payment_id, recurring_index, restructure_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
 				created_at, updated_at, deleted_at
 			FROM billings_tab
 			WHERE 
 			  	loan_id = ?
-			  	AND status = ? 
+			  	AND status IN (?, ?)
 			  	AND due_time < ?`
 	)
 
@@ -376,28 +628,120 @@ func DBGetOverdueBillings(ctx context.Context, loanID int64) ([]dtos.BillingMode
 	return billingModels, nil
 }
 
-func DBGetPendingBillingsWithDueTimeByLoanIdForUpdate(ctx context.Context, tx *sqlx.Tx, loanID, dueTime int64) ([]dtos.BillingModel, error) {
+func DBGetBillingsByLoanID(ctx context.Context, loanID int64) ([]dtos.BillingModel, error) {
+	var (
+		billingModels []dtos.BillingModel
+		err           error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, billing_id,
+			    loan_id, payment_id, recurring_index, restructure_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
+				created_at, updated_at, deleted_at
+			FROM billings_tab
+			WHERE 
+			  	loan_id = ?
+			  	AND deleted_at = 0
+			ORDER BY recurring_index`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &billingModels, query, args...); err != nil {
+		return nil, err
+	}
+	return billingModels, nil
+}
+
+func DBGetNearestOpenBillingDueTime(ctx context.Context, tx *sqlx.Tx, loanID, dueTime int64) (int64, error) {
+	var (
+		nearestDueTime sql.NullInt64
+		err            error
+
+		args = []interface{}{
+			loanID,
+			constants.PaymentStatus_Pending,
+			constants.PaymentStatus_PartiallyPaid,
+			dueTime,
+		}
+		query = `
+			SELECT MIN(due_time)
+			FROM billings_tab
+			WHERE 
+			    loan_id = ?
+			  	AND status IN (?, ?)
+			  	AND due_time >= ?
+			  	AND deleted_at = 0`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&nearestDueTime); err != nil {
+		return 0, err
+	}
+	if !nearestDueTime.Valid {
+		return 0, constants.ErrRecordNotFound
+	}
+	return nearestDueTime.Int64, nil
+}
+
+func DBCountOpenBillingsByLoanID(ctx context.Context, tx *sqlx.Tx, loanID int64) (int64, error) {
+	var (
+		count int64
+		err   error
+
+		args = []interface{}{
+			loanID,
+			constants.PaymentStatus_Pending,
+			constants.PaymentStatus_PartiallyPaid,
+		}
+		query = `
+			SELECT COUNT(1)
+			FROM billings_tab
+			WHERE 
+			  	loan_id = ?
+			  	AND status IN (?, ?)
+			  	AND deleted_at = 0`
+	)
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&count)
+	} else {
+		err = getDatabase().QueryRowContext(ctx, query, args...).Scan(&count)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx context.Context, tx *sqlx.Tx, loanID, dueTime int64) ([]dtos.BillingModel, error) {
 	var (
 		models []dtos.BillingModel
 
 		args = []interface{}{
 			loanID,
 			constants.PaymentStatus_Pending,
+			constants.PaymentStatus_PartiallyPaid,
 			dueTime,
 		}
 		query = `
 			SELECT 
 				id, billing_id,
-				loan_id, payment_id, recurring_index,
-				principal_amount, interest_amount, total_amount,
-				due_time, payment_completed_at, status,
+				loan_id, payment_id, recurring_index, restructure_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
 				created_at, updated_at, deleted_at
 			FROM billings_tab
 			WHERE 
 			    loan_id = ?
-			  	AND status = ? 
+			  	AND status IN (?, ?)
 			  	AND due_time <= ?
 			  	AND deleted_at = 0
+			ORDER BY due_time, recurring_index
 			FOR UPDATE`
 	)
 
@@ -424,58 +768,206 @@ func DBGetPendingBillingsWithDueTimeByLoanIdForUpdate(ctx context.Context, tx *s
 	return models, nil
 }
 
-func DBBulkPayBillingsByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64, paymentID int64) error {
+func DBGetBillingsByBillingIDsForUpdate(ctx context.Context, tx *sqlx.Tx, loanID int64, billingIDs []string) ([]dtos.BillingModel, error) {
 	var (
-		err error
+		models []dtos.BillingModel
+		err    error
+	)
+
+	query, args, err := sqlx.In(`
+			SELECT 
+				id, billing_id,
+				loan_id, payment_id, recurring_index, restructure_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
+				created_at, updated_at, deleted_at
+			FROM billings_tab
+			WHERE 
+			    loan_id = ?
+			  	AND billing_id IN (?)
+			  	AND deleted_at = 0
+			ORDER BY due_time, recurring_index
+			FOR UPDATE`, loanID, billingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// DBGetBillingsByLoanIDForUpdate returns the billings of every status, including the cancelled ones of the previous schedules
+func DBGetBillingsByLoanIDForUpdate(ctx context.Context, tx *sqlx.Tx, loanID int64) ([]dtos.BillingModel, error) {
+	var (
+		models []dtos.BillingModel
+		err    error
 
-		now = time.Now().UnixMilli()
+		query = `
+			SELECT 
+				id, billing_id,
+				loan_id, payment_id, recurring_index, restructure_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount, total_amount,
+				principal_paid_amount, interest_paid_amount, penalty_paid_amount, fee_paid_amount,
+				capitalised_penalty_amount, interest_accrued_amount, due_time, payment_completed_at, status,
+				created_at, updated_at, deleted_at
+			FROM billings_tab
+			WHERE 
+			    loan_id = ?
+			  	AND deleted_at = 0
+			ORDER BY due_time, recurring_index
+			FOR UPDATE`
 	)
 
+	if err = tx.SelectContext(ctx, &models, query, loanID); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func DBUpdateBillingPaymentByID(ctx context.Context, tx *sqlx.Tx, model *dtos.BillingModel) error {
+	var err error
+
 	query := `UPDATE billings_tab 
-		SET payment_id = :payment_id,
-			payment_completed_at = :now,
-			status = :status,
-		    updated_at = :now
-		WHERE id IN(:ids)`
-	arg := map[string]interface{}{
-		"payment_id": paymentID,
-		"now":        now,
-		"status":     constants.PaymentStatus_Completed,
-		"ids":        ids,
-	}
-
-	query, args, err := sqlx.Named(query, arg)
-	if err != nil {
-		return err
+		SET payment_id = ?,
+			principal_paid_amount = ?,
+			interest_paid_amount = ?,
+			penalty_paid_amount = ?,
+			fee_paid_amount = ?,
+			payment_completed_at = ?,
+			status = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.PaymentID,
+		model.PrincipalPaidAmount,
+		model.InterestPaidAmount,
+		model.PenaltyPaidAmount,
+		model.FeePaidAmount,
+		model.PaymentCompletedAt,
+		model.Status,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
 	}
+	return err
+}
 
-	query, args, err = sqlx.In(query, args...)
+func DBBulkUpdateBillingStatusByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64, status constants.PaymentStatus) error {
+	var err error
+
+	query, args, err := sqlx.In(`UPDATE billings_tab 
+		SET status = ?,
+		    updated_at = ?
+		WHERE id IN (?)`, status, time.Now().UnixMilli(), ids)
 	if err != nil {
 		return err
 	}
 
-	query = tx.Rebind(query)
 	if tx != nil {
 		_, err = tx.ExecContext(ctx, query, args...)
 	} else {
-		_, err = getDatabase().ExecContext(ctx, query, args)
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBUpdateBillingScheduleByID also resets the billing events, they are emitted again following the new due time
+func DBUpdateBillingScheduleByID(ctx context.Context, tx *sqlx.Tx, model *dtos.BillingModel) error {
+	var err error
+
+	query := `UPDATE billings_tab 
+		SET interest_amount = ?,
+			penalty_amount = ?,
+			total_amount = ?,
+			due_time = ?,
+			due_event_at = 0,
+			overdue_event_at = 0,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.InterestAmount,
+		model.PenaltyAmount,
+		model.TotalAmount,
+		model.DueTime,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateBillingInterestAccruedByID(ctx context.Context, tx *sqlx.Tx, id int64, interestAccruedAmount decimal.Decimal) error {
+	var err error
+
+	query := `UPDATE billings_tab 
+		SET interest_accrued_amount = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		interestAccruedAmount,
+		time.Now().UnixMilli(),
+		id,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateBillingPenaltyByID(ctx context.Context, tx *sqlx.Tx, id int64, penaltyAmount, totalAmount decimal.Decimal) error {
+	var err error
+
+	query := `UPDATE billings_tab 
+		SET penalty_amount = ?,
+			total_amount = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		penaltyAmount,
+		totalAmount,
+		time.Now().UnixMilli(),
+		id,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
 	}
 	return err
 }
 
-func DBUpdateLoanRequestPaymentByID(ctx context.Context, tx *sqlx.Tx, loanID int64, principalPaid, interestPaid decimal.Decimal, status constants.LoanStatus) error {
+func DBUpdateLoanRequestPaymentByID(ctx context.Context, tx *sqlx.Tx, loanID int64, principalPaid, interestPaid, penaltyPaid, feePaid decimal.Decimal) error {
 	var err error
 
 	query := `UPDATE loan_requests_tab 
 		SET principal_paid_amount = principal_paid_amount + ?,
 			interest_paid_amount = interest_paid_amount + ?,
-			status = ?,
+			penalty_paid_amount = penalty_paid_amount + ?,
+			fee_paid_amount = fee_paid_amount + ?,
 		    updated_at = ?
 		WHERE id = ?`
 	args := []interface{}{
 		principalPaid,
 		interestPaid,
-		status,
+		penaltyPaid,
+		feePaid,
 		time.Now().UnixMilli(),
 		loanID,
 	}
@@ -487,3 +979,99 @@ func DBUpdateLoanRequestPaymentByID(ctx context.Context, tx *sqlx.Tx, loanID int
 	}
 	return err
 }
+
+func DBUpdateLoanRequestStatusByID(ctx context.Context, tx *sqlx.Tx, loanID int64, status constants.LoanStatus) error {
+	var err error
+
+	query := `UPDATE loan_requests_tab 
+		SET status = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		status,
+		time.Now().UnixMilli(),
+		loanID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateLoanRequestDisbursementByID(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRequestModel) error {
+	var err error
+
+	query := `UPDATE loan_requests_tab 
+		SET disbursement_time = ?,
+			effective_apr = ?,
+			total_cost_of_credit = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.DisbursementTime,
+		model.EffectiveAPR,
+		model.TotalCostOfCredit,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateLoanRequestCreditCostByID(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRequestModel) error {
+	var err error
+
+	query := `UPDATE loan_requests_tab 
+		SET effective_apr = ?,
+			total_cost_of_credit = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.EffectiveAPR,
+		model.TotalCostOfCredit,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateLoanRequestTermsByID(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRequestModel) error {
+	var err error
+
+	query := `UPDATE loan_requests_tab 
+		SET tenure_value = ?,
+			tenure_unit = ?,
+			annual_interest_rate = ?,
+			amortization_method = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.TenureValue,
+		model.TenureUnit,
+		model.AnnualInterestRate,
+		model.AmortizationMethod,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
@@ -0,0 +1,374 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+func DBInsertCollectionCase(ctx context.Context, tx *sqlx.Tx, model *dtos.CollectionCaseModel) (int64, error) {
+	var (
+		caseID int64
+		res    sql.Result
+		err    error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			collection_cases_tab 
+			(loan_id, user_id, status, bucket, days_past_due,
+			 assignee_type, assignee_id, close_reason, opened_at, closed_at,
+			 created_at, updated_at) VALUES 
+			(?, ?, ?, ?, ?,
+			 ?, ?, ?, ?, ?,
+			 ?, ?)`
+		args = []interface{}{
+			model.LoanID, model.UserID, model.Status, model.Bucket, model.DaysPastDue,
+			model.AssigneeType, model.AssigneeID, model.CloseReason, model.OpenedAt, model.ClosedAt,
+			now, now,
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	caseID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return caseID, nil
+}
+
+func DBGetCollectionCaseByID(ctx context.Context, caseID int64) (*dtos.CollectionCaseModel, error) {
+	var (
+		caseModel dtos.CollectionCaseModel
+		err       error
+
+		query = `
+			SELECT 
+				id, loan_id, user_id, status, bucket, days_past_due,
+				assignee_type, assignee_id, close_reason, opened_at, closed_at,
+				created_at, updated_at
+			FROM collection_cases_tab
+			WHERE id = ?
+			LIMIT 1`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, caseID).Scan(caseModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &caseModel, nil
+}
+
+func DBGetCollectionCaseByIDForUpdate(ctx context.Context, tx *sqlx.Tx, caseID int64) (*dtos.CollectionCaseModel, error) {
+	var (
+		caseModel dtos.CollectionCaseModel
+		err       error
+
+		query = `
+			SELECT 
+				id, loan_id, user_id, status, bucket, days_past_due,
+				assignee_type, assignee_id, close_reason, opened_at, closed_at,
+				created_at, updated_at
+			FROM collection_cases_tab
+			WHERE id = ?
+			LIMIT 1
+			FOR UPDATE`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, caseID).Scan(caseModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &caseModel, nil
+}
+
+// DBGetOpenCollectionCaseByLoanIDForUpdate returns the loan's open case, a loan has at most one open case at a time
+func DBGetOpenCollectionCaseByLoanIDForUpdate(ctx context.Context, tx *sqlx.Tx, loanID int64) (*dtos.CollectionCaseModel, error) {
+	var (
+		caseModel dtos.CollectionCaseModel
+		err       error
+
+		args = []interface{}{
+			loanID,
+			constants.CollectionCaseStatus_Open,
+		}
+		query = `
+			SELECT 
+				id, loan_id, user_id, status, bucket, days_past_due,
+				assignee_type, assignee_id, close_reason, opened_at, closed_at,
+				created_at, updated_at
+			FROM collection_cases_tab
+			WHERE 
+			    loan_id = ?
+			  	AND status = ?
+			LIMIT 1
+			FOR UPDATE`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(caseModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &caseModel, nil
+}
+
+func DBUpdateCollectionCaseBucketByID(ctx context.Context, tx *sqlx.Tx, caseID int64, bucket constants.CollectionBucket, daysPastDue int) error {
+	var err error
+
+	query := `UPDATE collection_cases_tab 
+		SET bucket = ?,
+			days_past_due = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		bucket,
+		daysPastDue,
+		time.Now().UnixMilli(),
+		caseID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBUpdateCollectionCaseAssigneeByID(ctx context.Context, tx *sqlx.Tx, caseID int64, assigneeType constants.CollectionAssigneeType, assigneeID int64) error {
+	var err error
+
+	query := `UPDATE collection_cases_tab 
+		SET assignee_type = ?,
+			assignee_id = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		assigneeType,
+		assigneeID,
+		time.Now().UnixMilli(),
+		caseID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBCloseCollectionCaseByID(ctx context.Context, tx *sqlx.Tx, caseID int64, reason constants.CollectionCaseCloseReason) error {
+	var (
+		err error
+
+		now = time.Now().UnixMilli()
+	)
+
+	query := `UPDATE collection_cases_tab 
+		SET status = ?,
+			close_reason = ?,
+			closed_at = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		constants.CollectionCaseStatus_Closed,
+		reason,
+		now,
+		now,
+		caseID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBBatchInsertCollectionActivities(ctx context.Context, tx *sqlx.Tx, models []dtos.CollectionActivityModel) error {
+	var (
+		err error
+
+		now          = time.Now().UnixMilli()
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO collection_activities_tab 
+		(case_id, loan_id, activity_type, actor, notes, activity_time,
+		created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?, ?, ?, ?,
+		?)`
+
+	for _, model := range models {
+		activityTime := model.ActivityTime
+		if activityTime == 0 {
+			activityTime = now
+		}
+
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.CaseID, model.LoanID, model.ActivityType, model.Actor, model.Notes, activityTime,
+			now,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetCollectionActivitiesByCaseID(ctx context.Context, caseID int64) ([]dtos.CollectionActivityModel, error) {
+	var (
+		activityModels []dtos.CollectionActivityModel
+		err            error
+
+		query = `
+			SELECT 
+				id, case_id, loan_id, activity_type, actor, notes, activity_time,
+				created_at
+			FROM collection_activities_tab
+			WHERE case_id = ?
+			ORDER BY activity_time, id`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &activityModels, query, caseID); err != nil {
+		return nil, err
+	}
+	return activityModels, nil
+}
+
+func DBInsertPromiseToPay(ctx context.Context, tx *sqlx.Tx, model *dtos.PromiseToPayModel) (int64, error) {
+	var (
+		promiseID int64
+		res       sql.Result
+		err       error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			promises_to_pay_tab 
+			(case_id, loan_id, promised_amount, promised_due_time, paid_amount, status, actor,
+			 created_at, updated_at) VALUES 
+			(?, ?, ?, ?, ?, ?, ?,
+			 ?, ?)`
+		args = []interface{}{
+			model.CaseID, model.LoanID, model.PromisedAmount, model.PromisedDueTime, decimal.Zero, constants.PromiseToPayStatus_Pending, model.Actor,
+			now, now,
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	promiseID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return promiseID, nil
+}
+
+func DBGetPromisesToPayByCaseID(ctx context.Context, tx *sqlx.Tx, caseID int64) ([]dtos.PromiseToPayModel, error) {
+	var (
+		promiseModels []dtos.PromiseToPayModel
+		err           error
+
+		query = `
+			SELECT 
+				id, case_id, loan_id, promised_amount, promised_due_time, paid_amount, status, actor,
+				created_at, updated_at
+			FROM promises_to_pay_tab
+			WHERE case_id = ?
+			ORDER BY id`
+	)
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &promiseModels, query, caseID)
+	} else {
+		err = getDatabase().SelectContext(ctx, &promiseModels, query, caseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return promiseModels, nil
+}
+
+func DBGetPendingPromisesToPay(ctx context.Context, lastID int64, limit int) ([]dtos.PromiseToPayModel, error) {
+	var (
+		promiseModels []dtos.PromiseToPayModel
+		err           error
+
+		args = []interface{}{
+			constants.PromiseToPayStatus_Pending,
+			lastID,
+			limit,
+		}
+		query = `
+			SELECT 
+				id, case_id, loan_id, promised_amount, promised_due_time, paid_amount, status, actor,
+				created_at, updated_at
+			FROM promises_to_pay_tab
+			WHERE 
+			    status = ?
+			  	AND id > ?
+			ORDER BY id
+			LIMIT ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &promiseModels, query, args...); err != nil {
+		return nil, err
+	}
+	return promiseModels, nil
+}
+
+func DBUpdatePromiseToPayResultByID(ctx context.Context, tx *sqlx.Tx, promiseID int64, paidAmount decimal.Decimal, status constants.PromiseToPayStatus) error {
+	var err error
+
+	query := `UPDATE promises_to_pay_tab 
+		SET paid_amount = ?,
+			status = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		paidAmount,
+		status,
+		time.Now().UnixMilli(),
+		promiseID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DBInsertECLRun returns false without inserting when the reporting date is already stored
+func DBInsertECLRun(ctx context.Context, tx *sqlx.Tx, model *dtos.ECLRunModel) (bool, error) {
+	var (
+		affectedRows int64
+		err          error
+
+		query = `INSERT IGNORE INTO
+			ecl_runs_tab
+			(as_of_date, loan_count, total_exposure_amount, total_ecl_amount, created_at) VALUES
+			(?, ?, ?, ?, ?)`
+	)
+	model.CreatedAt = time.Now().UnixMilli()
+	args := []interface{}{
+		model.AsOfDate, model.LoanCount, model.TotalExposureAmount, model.TotalECLAmount, model.CreatedAt,
+	}
+
+	var res sql.Result
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	if affectedRows == 0 {
+		return false, nil
+	}
+
+	if model.ID, err = res.LastInsertId(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func DBBatchInsertECLRunLoans(ctx context.Context, tx *sqlx.Tx, models []dtos.ECLRunLoanModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO ecl_run_loans_tab
+		(run_id, loan_id, user_id, product_code,
+		days_past_due, is_restructured, stage,
+		exposure_amount, pd, lgd, ecl_amount) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?, ?,
+		?, ?, ?,
+		?, ?, ?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.RunID, model.LoanID, model.UserID, model.ProductCode,
+			model.DaysPastDue, model.IsRestructured, model.Stage,
+			model.ExposureAmount, model.PD, model.LGD, model.ECLAmount,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetECLRunByAsOfDate(ctx context.Context, asOfDate int64) (*dtos.ECLRunModel, error) {
+	var (
+		runModel dtos.ECLRunModel
+		err      error
+
+		query = `
+			SELECT
+				id, as_of_date, loan_count, total_exposure_amount, total_ecl_amount, created_at
+			FROM ecl_runs_tab
+			WHERE as_of_date = ?
+			LIMIT 1`
+	)
+
+	err = getDatabase().QueryRowContext(ctx, query, asOfDate).Scan(runModel.GetAll()...)
+	if err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &runModel, nil
+}
+
+func DBGetECLRunLoansByRunID(ctx context.Context, runID int64) ([]dtos.ECLRunLoanModel, error) {
+	var (
+		loanModels []dtos.ECLRunLoanModel
+		err        error
+
+		query = `
+			SELECT
+				id, run_id, loan_id, user_id, product_code,
+				days_past_due, is_restructured, stage,
+				exposure_amount, pd, lgd, ecl_amount
+			FROM ecl_run_loans_tab
+			WHERE run_id = ?
+			ORDER BY loan_id ASC`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &loanModels, query, runID); err != nil {
+		return nil, err
+	}
+	return loanModels, nil
+}
@@ -0,0 +1,42 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBBatchInsertInterestAccruals(ctx context.Context, tx *sqlx.Tx, models []dtos.InterestAccrualModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO interest_accruals_tab 
+		(loan_id, billing_id, accrual_date,
+		amount, source, created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?,
+		?, ?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.LoanID, model.BillingID, model.AccrualDate,
+			model.Amount, model.Source, model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
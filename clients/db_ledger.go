@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertJournalEntry(ctx context.Context, tx *sqlx.Tx, model *dtos.JournalEntryModel) (int64, error) {
+	var (
+		journalEntryID int64
+		res            sql.Result
+		err            error
+
+		query = `INSERT INTO 
+			journal_entries_tab 
+			(entry_type, loan_id, reference_id,
+			 description, created_at) VALUES 
+			(?, ?, ?,
+			 ?, ?)`
+		args = []interface{}{
+			model.EntryType, model.LoanID, model.ReferenceID,
+			model.Description, time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	journalEntryID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return journalEntryID, nil
+}
+
+func DBBatchInsertJournalLines(ctx context.Context, tx *sqlx.Tx, models []dtos.JournalLineModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO journal_lines_tab 
+		(journal_entry_id, loan_id, account,
+		debit_amount, credit_amount, created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?,
+		?, ?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.JournalEntryID, model.LoanID, model.Account,
+			model.DebitAmount, model.CreditAmount, model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+// DBGetLedgerAccountBalances sums up the journal lines per account, of every loan when loanID is 0
+func DBGetLedgerAccountBalances(ctx context.Context, loanID int64) ([]dtos.LedgerAccountBalanceModel, error) {
+	var (
+		balanceModels []dtos.LedgerAccountBalanceModel
+		err           error
+
+		args  []interface{}
+		query = `
+			SELECT 
+				account, SUM(debit_amount) AS debit_amount, SUM(credit_amount) AS credit_amount
+			FROM journal_lines_tab
+			%s
+			GROUP BY account
+			ORDER BY account`
+	)
+
+	where := ""
+	if loanID != 0 {
+		where = "WHERE loan_id = ?"
+		args = append(args, loanID)
+	}
+
+	if err = getDatabase().SelectContext(ctx, &balanceModels, fmt.Sprintf(query, where), args...); err != nil {
+		return nil, err
+	}
+	return balanceModels, nil
+}
+
+func DBGetUnbalancedJournalEntryIDs(ctx context.Context) ([]int64, error) {
+	var (
+		journalEntryIDs []int64
+		err             error
+
+		query = `
+			SELECT journal_entry_id
+			FROM journal_lines_tab
+			GROUP BY journal_entry_id
+			HAVING SUM(debit_amount) <> SUM(credit_amount)
+			ORDER BY journal_entry_id`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &journalEntryIDs, query); err != nil {
+		return nil, err
+	}
+	return journalEntryIDs, nil
+}
+
+// DBGetJournalLineSummariesByCreatedTime sums up the journal lines per entry type and account
+// of the journal entries created within [startTime, endTime)
+func DBGetJournalLineSummariesByCreatedTime(ctx context.Context, startTime, endTime int64) ([]dtos.JournalLineSummaryModel, error) {
+	var (
+		summaryModels []dtos.JournalLineSummaryModel
+		err           error
+
+		query = `
+			SELECT 
+				e.entry_type, l.account, SUM(l.debit_amount) AS debit_amount, SUM(l.credit_amount) AS credit_amount
+			FROM journal_entries_tab e
+			JOIN journal_lines_tab l ON l.journal_entry_id = e.id
+			WHERE e.created_at >= ? AND e.created_at < ?
+			GROUP BY e.entry_type, l.account
+			ORDER BY e.entry_type, l.account`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &summaryModels, query, startTime, endTime); err != nil {
+		return nil, err
+	}
+	return summaryModels, nil
+}
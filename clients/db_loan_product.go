@@ -0,0 +1,245 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBGetLoanProductByCode(ctx context.Context, productCode string) (*dtos.LoanProductModel, error) {
+	var (
+		loanProductModel dtos.LoanProductModel
+		err              error
+
+		args = []interface{}{
+			productCode,
+		}
+		query = `
+			SELECT 
+				id, product_code, name,
+				min_loan_amount, max_loan_amount,
+				allowed_tenure_units, min_tenure_value, max_tenure_value,
+				annual_interest_rate, amortization_method,
+				late_penalty_rate, late_penalty_grace_days,
+				delinquent_overdue_billings, status,
+				created_at, updated_at, deleted_at
+			FROM loan_products_tab
+			WHERE 
+			    product_code = ? 
+			  	AND deleted_at = 0
+			LIMIT 1`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, args...).Scan(loanProductModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &loanProductModel, nil
+}
+
+func DBGetLoanProductByID(ctx context.Context, productID int64) (*dtos.LoanProductModel, error) {
+	var (
+		loanProductModel dtos.LoanProductModel
+		err              error
+
+		args = []interface{}{
+			productID,
+		}
+		query = `
+			SELECT 
+				id, product_code, name,
+				min_loan_amount, max_loan_amount,
+				allowed_tenure_units, min_tenure_value, max_tenure_value,
+				annual_interest_rate, amortization_method,
+				late_penalty_rate, late_penalty_grace_days,
+				delinquent_overdue_billings, status,
+				created_at, updated_at, deleted_at
+			FROM loan_products_tab
+			WHERE 
+			    id = ? 
+			  	AND deleted_at = 0
+			LIMIT 1`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, args...).Scan(loanProductModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &loanProductModel, nil
+}
+
+func DBGetLoanProductRatesByProductID(ctx context.Context, productID int64) ([]dtos.LoanProductRateModel, error) {
+	var (
+		rateModels []dtos.LoanProductRateModel
+		err        error
+
+		args = []interface{}{
+			productID,
+		}
+		query = `
+			SELECT 
+				id, product_id, tenure_unit,
+				min_tenure_value, max_tenure_value, annual_interest_rate,
+				created_at
+			FROM loan_product_rates_tab
+			WHERE 
+			    product_id = ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &rateModels, query, args...); err != nil {
+		return nil, err
+	}
+	return rateModels, nil
+}
+
+func DBGetLoanProductFeesByProductID(ctx context.Context, productID int64) ([]dtos.LoanProductFeeModel, error) {
+	var (
+		feeModels []dtos.LoanProductFeeModel
+		err       error
+
+		args = []interface{}{
+			productID,
+		}
+		query = `
+			SELECT 
+				id, product_id, fee_type,
+				calculation_type, value, charge_type,
+				created_at
+			FROM loan_product_fees_tab
+			WHERE 
+			    product_id = ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &feeModels, query, args...); err != nil {
+		return nil, err
+	}
+	return feeModels, nil
+}
+
+func DBInsertLoanProduct(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanProductModel) (int64, error) {
+	var (
+		productID int64
+		res       sql.Result
+		err       error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			loan_products_tab 
+			(product_code, name,
+			 min_loan_amount, max_loan_amount,
+			 allowed_tenure_units, min_tenure_value, max_tenure_value,
+			 annual_interest_rate, amortization_method,
+			 late_penalty_rate, late_penalty_grace_days,
+			 delinquent_overdue_billings, status,
+			 created_at, updated_at, deleted_at) VALUES 
+			(?, ?,
+			 ?, ?,
+			 ?, ?, ?,
+			 ?, ?,
+			 ?, ?,
+			 ?, ?,
+			 ?, ?, ?)`
+		args = []interface{}{
+			model.ProductCode, model.Name,
+			model.MinLoanAmount, model.MaxLoanAmount,
+			model.AllowedTenureUnits, model.MinTenureValue, model.MaxTenureValue,
+			model.AnnualInterestRate, model.AmortizationMethod,
+			model.LatePenaltyRate, model.LatePenaltyGraceDays,
+			model.DelinquentOverdueBillings, model.Status,
+			now, now, 0,
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	productID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return productID, nil
+}
+
+func DBBatchInsertLoanProductRates(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanProductRateModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO loan_product_rates_tab 
+		(product_id, tenure_unit,
+		min_tenure_value, max_tenure_value, annual_interest_rate,
+		created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?,
+		?, ?, ?,
+		?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.ProductID, model.TenureUnit,
+			model.MinTenureValue, model.MaxTenureValue, model.AnnualInterestRate,
+			model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBBatchInsertLoanProductFees(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanProductFeeModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO loan_product_fees_tab 
+		(product_id, fee_type,
+		calculation_type, value, charge_type,
+		created_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?,
+		?, ?, ?,
+		?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.ProductID, model.FeeType,
+			model.CalculationType, model.Value, model.ChargeType,
+			model.CreatedAt,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
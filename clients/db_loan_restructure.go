@@ -0,0 +1,132 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertLoanRestructure(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRestructureModel) (int64, error) {
+	var (
+		restructureID int64
+		res           sql.Result
+		err           error
+
+		query = `INSERT INTO 
+			loan_restructures_tab 
+			(loan_id,
+			 previous_tenure_value, previous_tenure_unit, previous_annual_interest_rate, previous_amortization_method,
+			 tenure_value, tenure_unit, annual_interest_rate, amortization_method,
+			 outstanding_principal_amount, capitalised_arrears_amount,
+			 first_recurring_index, cancelled_billing_count,
+			 actor, reason, created_at) VALUES 
+			(?,
+			 ?, ?, ?, ?,
+			 ?, ?, ?, ?,
+			 ?, ?,
+			 ?, ?,
+			 ?, ?, ?)`
+		args = []interface{}{
+			model.LoanID,
+			model.PreviousTenureValue, model.PreviousTenureUnit, model.PreviousAnnualInterestRate, model.PreviousAmortizationMethod,
+			model.TenureValue, model.TenureUnit, model.AnnualInterestRate, model.AmortizationMethod,
+			model.OutstandingPrincipalAmount, model.CapitalisedArrearsAmount,
+			model.FirstRecurringIndex, model.CancelledBillingCount,
+			model.Actor, model.Reason, time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	restructureID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return restructureID, nil
+}
+
+func DBGetLoanRestructuresByLoanID(ctx context.Context, loanID int64) ([]dtos.LoanRestructureModel, error) {
+	var (
+		restructureModels []dtos.LoanRestructureModel
+		err               error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, loan_id,
+				previous_tenure_value, previous_tenure_unit, previous_annual_interest_rate, previous_amortization_method,
+				tenure_value, tenure_unit, annual_interest_rate, amortization_method,
+				outstanding_principal_amount, capitalised_arrears_amount,
+				first_recurring_index, cancelled_billing_count,
+				actor, reason, created_at
+			FROM loan_restructures_tab
+			WHERE 
+			    loan_id = ?
+			ORDER BY id`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &restructureModels, query, args...); err != nil {
+		return nil, err
+	}
+	return restructureModels, nil
+}
+
+func DBBatchInsertLoanRestructureBillings(ctx context.Context, tx *sqlx.Tx, models []dtos.LoanRestructureBillingModel) error {
+	var (
+		err error
+
+		now          = time.Now().UnixMilli()
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0, len(models)*4)
+	)
+
+	queryTemplate := `INSERT INTO loan_restructure_billings_tab 
+		(restructure_id, loan_id, billing_id, created_at) VALUES %s`
+
+	for _, model := range models {
+		placeholders = append(placeholders, `(?, ?, ?, ?)`)
+		args = append(args, model.RestructureID, model.LoanID, model.BillingID, now)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetLoanRestructureBillingsByLoanID(ctx context.Context, loanID int64) ([]dtos.LoanRestructureBillingModel, error) {
+	var (
+		models []dtos.LoanRestructureBillingModel
+		err    error
+
+		query = `
+			SELECT 
+				id, restructure_id, loan_id, billing_id, created_at
+			FROM loan_restructure_billings_tab
+			WHERE 
+			    loan_id = ?
+			ORDER BY id`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &models, query, loanID); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+func DBInsertLoanWriteOff(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanWriteOffModel) (int64, error) {
+	var (
+		writeOffID int64
+		res        sql.Result
+		err        error
+
+		query = `INSERT INTO 
+			loan_write_offs_tab 
+			(loan_id,
+			 principal_amount, interest_amount, penalty_amount, fee_amount,
+			 actor, reason, created_at) VALUES 
+			(?,
+			 ?, ?, ?, ?,
+			 ?, ?, ?)`
+		args = []interface{}{
+			model.LoanID,
+			model.PrincipalAmount, model.InterestAmount, model.PenaltyAmount, model.FeeAmount,
+			model.Actor, model.Reason, time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	writeOffID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return writeOffID, nil
+}
+
+func DBGetLoanWriteOffByLoanID(ctx context.Context, tx *sqlx.Tx, loanID int64) (*dtos.LoanWriteOffModel, error) {
+	var (
+		writeOffModel dtos.LoanWriteOffModel
+		err           error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT 
+				id, loan_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount,
+				actor, reason, created_at
+			FROM loan_write_offs_tab
+			WHERE 
+			    loan_id = ?
+			LIMIT 1`
+	)
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(writeOffModel.GetAll()...)
+	} else {
+		err = getDatabase().QueryRowContext(ctx, query, args...).Scan(writeOffModel.GetAll()...)
+	}
+	if err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &writeOffModel, nil
+}
+
+func DBGetLoanWriteOffsByCreatedTime(ctx context.Context, startTime, endTime, lastID int64, limit int) ([]dtos.LoanWriteOffModel, error) {
+	var (
+		writeOffModels []dtos.LoanWriteOffModel
+		err            error
+
+		args = []interface{}{
+			startTime,
+			endTime,
+			lastID,
+			limit,
+		}
+		query = `
+			SELECT 
+				id, loan_id,
+				principal_amount, interest_amount, penalty_amount, fee_amount,
+				actor, reason, created_at
+			FROM loan_write_offs_tab
+			WHERE 
+			    created_at >= ?
+			    AND created_at < ?
+			    AND id > ?
+			ORDER BY id
+			LIMIT ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &writeOffModels, query, args...); err != nil {
+		return nil, err
+	}
+	return writeOffModels, nil
+}
+
+func DBInsertLoanRecovery(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanRecoveryModel) error {
+	var (
+		err error
+
+		query = `INSERT INTO 
+			loan_recoveries_tab 
+			(loan_id, payment_id, amount, created_at) VALUES 
+			(?, ?, ?, ?)`
+		args = []interface{}{
+			model.LoanID, model.PaymentID, model.Amount, time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBGetLoanRecoveredAmount(ctx context.Context, tx *sqlx.Tx, loanID int64) (decimal.Decimal, error) {
+	var (
+		recoveredAmount decimal.NullDecimal
+		err             error
+
+		args = []interface{}{
+			loanID,
+		}
+		query = `
+			SELECT SUM(amount)
+			FROM loan_recoveries_tab
+			WHERE 
+			    loan_id = ?`
+	)
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&recoveredAmount)
+	} else {
+		err = getDatabase().QueryRowContext(ctx, query, args...).Scan(&recoveredAmount)
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !recoveredAmount.Valid {
+		return decimal.Zero, nil
+	}
+	return recoveredAmount.Decimal, nil
+}
@@ -0,0 +1,187 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DBGetOpenBillingsByDueTimeRange returns the open billings due within [dueTimeFrom, dueTimeTo) of the loans in the statuses
+func DBGetOpenBillingsByDueTimeRange(ctx context.Context, loanStatuses []constants.LoanStatus, dueTimeFrom, dueTimeTo, lastID int64, limit int) ([]dtos.BillingNotificationModel, error) {
+	var (
+		models []dtos.BillingNotificationModel
+		err    error
+	)
+
+	query, args, err := sqlx.In(`
+			SELECT 
+				b.id, b.billing_id, b.loan_id, b.recurring_index, b.due_time,
+				b.total_amount - b.principal_paid_amount - b.interest_paid_amount - b.penalty_paid_amount - b.fee_paid_amount AS unpaid_amount,
+				u.id AS user_id, u.name AS user_name, u.email, u.phone_number, u.push_token, u.locale
+			FROM billings_tab b
+			JOIN loan_requests_tab l ON l.id = b.loan_id
+			JOIN users_tab u ON u.id = l.user_id
+			WHERE 
+			    b.status IN (?)
+			  	AND b.due_time >= ?
+			  	AND b.due_time < ?
+			  	AND b.id > ?
+			  	AND b.deleted_at = 0
+			  	AND l.status IN (?)
+			ORDER BY b.id
+			LIMIT ?`,
+		[]constants.PaymentStatus{constants.PaymentStatus_Pending, constants.PaymentStatus_PartiallyPaid},
+		dueTimeFrom, dueTimeTo, lastID, loanStatuses, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = getDatabase().SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func DBGetNotificationsByBillingIDs(ctx context.Context, billingIDs []string, notificationType constants.NotificationType) ([]dtos.NotificationModel, error) {
+	var (
+		notificationModels []dtos.NotificationModel
+		err                error
+	)
+
+	query, args, err := sqlx.In(`
+			SELECT 
+				id, user_id, loan_id, billing_id, due_time, notification_type, channel, locale, recipient,
+				status, attempts, last_error, sent_at,
+				created_at, updated_at
+			FROM notifications_tab
+			WHERE 
+			    billing_id IN (?)
+			  	AND notification_type = ?`, billingIDs, notificationType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = getDatabase().SelectContext(ctx, &notificationModels, query, args...); err != nil {
+		return nil, err
+	}
+	return notificationModels, nil
+}
+
+// DBInsertNotification claims the notification as pending, it returns false when the notification is already recorded
+func DBInsertNotification(ctx context.Context, tx *sqlx.Tx, model *dtos.NotificationModel) (bool, error) {
+	var (
+		affectedRows int64
+		err          error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT IGNORE INTO 
+			notifications_tab 
+			(user_id, loan_id, billing_id, due_time, notification_type, channel, locale, recipient,
+			 status, attempts, last_error, sent_at,
+			 created_at, updated_at) VALUES 
+			(?, ?, ?, ?, ?, ?, ?, ?,
+			 ?, ?, ?, ?,
+			 ?, ?)`
+		args = []interface{}{
+			model.UserID, model.LoanID, model.BillingID, model.DueTime, model.NotificationType, model.Channel, model.Locale, model.Recipient,
+			constants.NotificationStatus_Pending, 0, "", 0,
+			now, now,
+		}
+	)
+
+	var res sql.Result
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	if affectedRows == 0 {
+		return false, nil
+	}
+
+	if model.ID, err = res.LastInsertId(); err != nil {
+		return false, err
+	}
+	model.Status = constants.NotificationStatus_Pending
+	return true, nil
+}
+
+// DBClaimNotificationForResendByID moves a failed notification, or a pending one last claimed before staleBefore, to pending again.
+// It returns false when another run claimed it first
+func DBClaimNotificationForResendByID(ctx context.Context, tx *sqlx.Tx, id, staleBefore int64) (bool, error) {
+	query := `UPDATE notifications_tab 
+		SET status = ?,
+		    updated_at = ?
+		WHERE id = ? AND (status = ? OR (status = ? AND updated_at < ?))`
+	args := []interface{}{
+		constants.NotificationStatus_Pending,
+		time.Now().UnixMilli(),
+		id,
+		constants.NotificationStatus_Failed,
+		constants.NotificationStatus_Pending,
+		staleBefore,
+	}
+
+	var (
+		res sql.Result
+		err error
+	)
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	affectedRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affectedRows > 0, nil
+}
+
+func DBUpdateNotificationResultByID(ctx context.Context, tx *sqlx.Tx, model *dtos.NotificationModel) error {
+	var err error
+
+	// follows the last_error column's length
+	if len(model.LastError) > 1024 {
+		model.LastError = model.LastError[:1024]
+	}
+
+	query := `UPDATE notifications_tab 
+		SET status = ?,
+			attempts = ?,
+			last_error = ?,
+			sent_at = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.Status,
+		model.Attempts,
+		model.LastError,
+		model.SentAt,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
@@ -0,0 +1,256 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBBatchInsertOutboxEvents(ctx context.Context, tx *sqlx.Tx, models []dtos.OutboxEventModel) error {
+	var (
+		err error
+
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT INTO outbox_events_tab 
+		(event_id, event_type, loan_id, payload,
+		status, attempts, last_error,
+		created_at, published_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?, ?,
+		?, ?, ?,
+		?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.EventID, model.EventType, model.LoanID, model.Payload,
+			constants.OutboxEventStatus_Pending, 0, "",
+			model.CreatedAt, 0,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+// DBGetPendingOutboxEventsForUpdate returns the pending events after lastID in insertion order, claimed or not.
+// Concurrent claims wait on the lock, so they see each other's claims
+func DBGetPendingOutboxEventsForUpdate(ctx context.Context, tx *sqlx.Tx, lastID int64, limit int) ([]dtos.OutboxEventModel, error) {
+	var (
+		models []dtos.OutboxEventModel
+		err    error
+
+		args = []interface{}{
+			constants.OutboxEventStatus_Pending,
+			lastID,
+			limit,
+		}
+		query = `
+			SELECT 
+				id, event_id, event_type, loan_id, payload,
+				status, attempts, last_error, claimed_by, claimed_until,
+				created_at, published_at
+			FROM outbox_events_tab
+			WHERE 
+			    status = ?
+			  	AND id > ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE`
+	)
+
+	if err = tx.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func DBUpdateOutboxEventClaimByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64, claimedBy string, claimedUntil int64) error {
+	var err error
+
+	query, args, err := sqlx.In(`UPDATE outbox_events_tab 
+		SET claimed_by = ?,
+			claimed_until = ?
+		WHERE id IN (?)`, claimedBy, claimedUntil, ids)
+	if err != nil {
+		return err
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBReleaseOutboxEventClaimByIDs lets the next run claim the events right away
+func DBReleaseOutboxEventClaimByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64, claimedBy string) error {
+	var err error
+
+	query, args, err := sqlx.In(`UPDATE outbox_events_tab 
+		SET claimed_until = 0
+		WHERE id IN (?) AND claimed_by = ?`, ids, claimedBy)
+	if err != nil {
+		return err
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBUpdateOutboxEventPublishedByID returns false when the event is no longer claimed by claimedBy, i.e. the claim expired
+// and another run took the event over
+func DBUpdateOutboxEventPublishedByID(ctx context.Context, tx *sqlx.Tx, id int64, claimedBy string) (bool, error) {
+	var (
+		affectedRows int64
+		err          error
+	)
+
+	query := `UPDATE outbox_events_tab 
+		SET status = ?,
+			attempts = attempts + 1,
+			last_error = '',
+		    published_at = ?
+		WHERE id = ? AND status = ? AND claimed_by = ?`
+	args := []interface{}{
+		constants.OutboxEventStatus_Published,
+		time.Now().UnixMilli(),
+		id,
+		constants.OutboxEventStatus_Pending,
+		claimedBy,
+	}
+
+	var res sql.Result
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	return affectedRows > 0, nil
+}
+
+// DBUpdateOutboxEventFailureByID records the failure and releases the claim, status is pending or dead lettered
+func DBUpdateOutboxEventFailureByID(ctx context.Context, tx *sqlx.Tx, id int64, claimedBy string, status constants.OutboxEventStatus, lastError string) error {
+	var err error
+
+	// follows the last_error column's length
+	if len(lastError) > 1024 {
+		lastError = lastError[:1024]
+	}
+
+	query := `UPDATE outbox_events_tab 
+		SET status = ?,
+			attempts = attempts + 1,
+			last_error = ?,
+			claimed_until = 0
+		WHERE id = ? AND claimed_by = ?`
+	args := []interface{}{
+		status,
+		lastError,
+		id,
+		claimedBy,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBGetOpenBillingsWithoutDueEvent returns the open billings due before dueTime whose billing.due event is not emitted yet
+func DBGetOpenBillingsWithoutDueEvent(ctx context.Context, dueTime, lastID int64, limit int) ([]dtos.BillingEventModel, error) {
+	return dbGetOpenBillingEvents(ctx, "b.due_event_at = 0", dueTime, lastID, limit)
+}
+
+// DBGetOpenBillingsWithoutOverdueEvent returns the open billings due before dueTime whose billing.overdue event is not emitted yet
+func DBGetOpenBillingsWithoutOverdueEvent(ctx context.Context, dueTime, lastID int64, limit int) ([]dtos.BillingEventModel, error) {
+	return dbGetOpenBillingEvents(ctx, "b.overdue_event_at = 0", dueTime, lastID, limit)
+}
+
+func dbGetOpenBillingEvents(ctx context.Context, eventCondition string, dueTime, lastID int64, limit int) ([]dtos.BillingEventModel, error) {
+	var (
+		models []dtos.BillingEventModel
+		err    error
+
+		args = []interface{}{
+			constants.PaymentStatus_Pending,
+			constants.PaymentStatus_PartiallyPaid,
+			dueTime,
+			lastID,
+			limit,
+		}
+		query = `
+			SELECT 
+				b.id, b.billing_id, b.loan_id, l.user_id, b.recurring_index, b.due_time,
+				b.total_amount - b.principal_paid_amount - b.interest_paid_amount - b.penalty_paid_amount - b.fee_paid_amount AS unpaid_amount
+			FROM billings_tab b
+			JOIN loan_requests_tab l ON l.id = b.loan_id
+			WHERE 
+			    b.status IN (?, ?)
+			  	AND b.due_time < ?
+			  	AND %s
+			  	AND b.id > ?
+			  	AND b.deleted_at = 0
+			ORDER BY b.id
+			LIMIT ?`
+	)
+
+	if err = getDatabase().SelectContext(ctx, &models, fmt.Sprintf(query, eventCondition), args...); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func DBUpdateBillingDueEventAtByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64) error {
+	return dbUpdateBillingEventAtByIDs(ctx, tx, "due_event_at", ids)
+}
+
+func DBUpdateBillingOverdueEventAtByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64) error {
+	return dbUpdateBillingEventAtByIDs(ctx, tx, "overdue_event_at", ids)
+}
+
+func dbUpdateBillingEventAtByIDs(ctx context.Context, tx *sqlx.Tx, column string, ids []int64) error {
+	var err error
+
+	query, args, err := sqlx.In(fmt.Sprintf(`UPDATE billings_tab 
+		SET %s = ?
+		WHERE id IN (?)`, column), time.Now().UnixMilli(), ids)
+	if err != nil {
+		return err
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertLoanPaymentHoliday(ctx context.Context, tx *sqlx.Tx, model *dtos.LoanPaymentHolidayModel) error {
+	var (
+		err error
+
+		query = `INSERT INTO 
+			loan_payment_holidays_tab 
+			(loan_id, holiday_code, periods,
+			 holiday_interest_amount, reversed_penalty_amount,
+			 actor, reason, created_at) VALUES 
+			(?, ?, ?,
+			 ?, ?,
+			 ?, ?, ?)`
+		args = []interface{}{
+			model.LoanID, model.HolidayCode, model.Periods,
+			model.HolidayInterestAmount, model.ReversedPenaltyAmount,
+			model.Actor, model.Reason, time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+func DBGetLoanPaymentHoliday(ctx context.Context, tx *sqlx.Tx, loanID int64, holidayCode string) (*dtos.LoanPaymentHolidayModel, error) {
+	var (
+		holidayModel dtos.LoanPaymentHolidayModel
+		err          error
+
+		args = []interface{}{
+			loanID,
+			holidayCode,
+		}
+		query = `
+			SELECT 
+				id, loan_id, holiday_code, periods,
+				holiday_interest_amount, reversed_penalty_amount,
+				actor, reason, created_at
+			FROM loan_payment_holidays_tab
+			WHERE 
+			    loan_id = ?
+			    AND holiday_code = ?
+			LIMIT 1`
+	)
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(holidayModel.GetAll()...)
+	} else {
+		err = getDatabase().QueryRowContext(ctx, query, args...).Scan(holidayModel.GetAll()...)
+	}
+	if err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &holidayModel, nil
+}
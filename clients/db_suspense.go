@@ -0,0 +1,141 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertSuspenseItem(ctx context.Context, tx *sqlx.Tx, model *dtos.SuspenseItemModel) (int64, error) {
+	var (
+		itemID int64
+		res    sql.Result
+		err    error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			suspense_items_tab 
+			(source, reference_id, user_id, loan_id, amount, reason, status,
+			 created_at, updated_at) VALUES 
+			(?, ?, ?, ?, ?, ?, ?,
+			 ?, ?)`
+	)
+
+	// follows the reason column's length
+	if len(model.Reason) > 1024 {
+		model.Reason = model.Reason[:1024]
+	}
+	args := []interface{}{
+		model.Source, model.ReferenceID, model.UserID, model.LoanID, model.Amount, model.Reason, model.Status,
+		now, now,
+	}
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	itemID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return itemID, nil
+}
+
+func DBGetSuspenseItemByIDForUpdate(ctx context.Context, tx *sqlx.Tx, itemID int64) (*dtos.SuspenseItemModel, error) {
+	var (
+		itemModel dtos.SuspenseItemModel
+		err       error
+
+		query = `
+			SELECT 
+				id, source, reference_id, user_id, loan_id, amount, reason, status,
+				payment_id, resolved_by, resolved_reason, resolved_at,
+				created_at, updated_at
+			FROM suspense_items_tab
+			WHERE 
+			    id = ?
+			FOR UPDATE`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, itemID).Scan(itemModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &itemModel, nil
+}
+
+func DBGetSuspenseItems(ctx context.Context, status constants.SuspenseItemStatus, lastID int64, limit int) ([]dtos.SuspenseItemModel, error) {
+	var (
+		itemModels []dtos.SuspenseItemModel
+		err        error
+
+		args  []interface{}
+		where = []string{"1 = 1"}
+	)
+	if status != 0 {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+	if lastID != 0 {
+		where = append(where, "id < ?")
+		args = append(args, lastID)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+			SELECT 
+				id, source, reference_id, user_id, loan_id, amount, reason, status,
+				payment_id, resolved_by, resolved_reason, resolved_at,
+				created_at, updated_at
+			FROM suspense_items_tab
+			WHERE %s
+			ORDER BY id DESC
+			LIMIT ?`, strings.Join(where, " AND "))
+
+	if err = getDatabase().SelectContext(ctx, &itemModels, query, args...); err != nil {
+		return nil, err
+	}
+	return itemModels, nil
+}
+
+func DBUpdateSuspenseItemResolutionByID(ctx context.Context, tx *sqlx.Tx, model *dtos.SuspenseItemModel) error {
+	var err error
+
+	query := `UPDATE suspense_items_tab 
+		SET status = ?,
+			payment_id = ?,
+			resolved_by = ?,
+			resolved_reason = ?,
+			resolved_at = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		model.Status,
+		model.PaymentID,
+		model.ResolvedBy,
+		model.ResolvedReason,
+		model.ResolvedAt,
+		time.Now().UnixMilli(),
+		model.ID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertVirtualAccount(ctx context.Context, tx *sqlx.Tx, model *dtos.VirtualAccountModel) (int64, error) {
+	var (
+		virtualAccountID int64
+		res              sql.Result
+		err              error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			virtual_accounts_tab 
+			(provider, external_id, bank_code, va_number,
+			 user_id, loan_id, billing_id, expected_amount, status,
+			 created_at, updated_at) VALUES 
+			(?, ?, ?, ?,
+			 ?, ?, ?, ?, ?,
+			 ?, ?)`
+		args = []interface{}{
+			model.Provider, model.ExternalID, model.BankCode, model.VANumber,
+			model.UserID, model.LoanID, model.BillingID, model.ExpectedAmount, model.Status,
+			now, now,
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	virtualAccountID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return virtualAccountID, nil
+}
+
+func DBGetActiveVirtualAccountsByLoanIDForUpdate(ctx context.Context, tx *sqlx.Tx, provider string, loanID int64) ([]dtos.VirtualAccountModel, error) {
+	var (
+		virtualAccountModels []dtos.VirtualAccountModel
+		err                  error
+
+		args = []interface{}{
+			provider,
+			loanID,
+			constants.VirtualAccountStatus_Active,
+		}
+		query = `
+			SELECT 
+				id, provider, external_id, bank_code, va_number,
+				user_id, loan_id, billing_id, expected_amount, status,
+				created_at, updated_at
+			FROM virtual_accounts_tab
+			WHERE 
+			    provider = ?
+			  	AND loan_id = ?
+			  	AND status = ?
+			ORDER BY id
+			FOR UPDATE`
+	)
+
+	if err = tx.SelectContext(ctx, &virtualAccountModels, query, args...); err != nil {
+		return nil, err
+	}
+	return virtualAccountModels, nil
+}
+
+func DBGetVirtualAccountByNumberForUpdate(ctx context.Context, tx *sqlx.Tx, provider, bankCode, vaNumber string) (*dtos.VirtualAccountModel, error) {
+	var (
+		virtualAccountModel dtos.VirtualAccountModel
+		err                 error
+
+		args = []interface{}{
+			provider,
+			bankCode,
+			vaNumber,
+		}
+		query = `
+			SELECT 
+				id, provider, external_id, bank_code, va_number,
+				user_id, loan_id, billing_id, expected_amount, status,
+				created_at, updated_at
+			FROM virtual_accounts_tab
+			WHERE 
+			    provider = ?
+			  	AND bank_code = ?
+			  	AND va_number = ?
+			LIMIT 1
+			FOR UPDATE`
+	)
+
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(virtualAccountModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &virtualAccountModel, nil
+}
+
+func DBUpdateVirtualAccountStatusByID(ctx context.Context, tx *sqlx.Tx, virtualAccountID int64, status constants.VirtualAccountStatus) error {
+	var err error
+
+	query := `UPDATE virtual_accounts_tab 
+		SET status = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		status,
+		time.Now().UnixMilli(),
+		virtualAccountID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBInsertVirtualAccountPayment returns false when the callback is already recorded
+func DBInsertVirtualAccountPayment(ctx context.Context, tx *sqlx.Tx, model *dtos.VirtualAccountPaymentModel) (bool, error) {
+	var (
+		res          sql.Result
+		affectedRows int64
+		err          error
+
+		query = `INSERT IGNORE INTO 
+			virtual_account_payments_tab 
+			(provider, callback_id, virtual_account_id, loan_id, amount, paid_at,
+			 payment_id, suspense_item_id, status, suspense_reason, payload,
+			 created_at) VALUES 
+			(?, ?, ?, ?, ?, ?,
+			 ?, ?, ?, ?, ?,
+			 ?)`
+		args = []interface{}{
+			model.Provider, model.CallbackID, model.VirtualAccountID, model.LoanID, model.Amount, model.PaidAt,
+			model.PaymentID, model.SuspenseItemID, model.Status, model.SuspenseReason, model.Payload,
+			time.Now().UnixMilli(),
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	return affectedRows > 0, nil
+}
+
+func DBGetVirtualAccountPaymentByCallbackID(ctx context.Context, tx *sqlx.Tx, provider, callbackID string) (*dtos.VirtualAccountPaymentModel, error) {
+	var (
+		paymentModel dtos.VirtualAccountPaymentModel
+		err          error
+
+		query = `
+			SELECT 
+				id, provider, callback_id, virtual_account_id, loan_id, amount, paid_at,
+				payment_id, suspense_item_id, status, suspense_reason, payload,
+				created_at
+			FROM virtual_account_payments_tab
+			WHERE 
+			    provider = ?
+			  	AND callback_id = ?
+			LIMIT 1`
+	)
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, provider, callbackID).Scan(paymentModel.GetAll()...)
+	} else {
+		err = getDatabase().QueryRowContext(ctx, query, provider, callbackID).Scan(paymentModel.GetAll()...)
+	}
+	if err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &paymentModel, nil
+}
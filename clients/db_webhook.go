@@ -0,0 +1,397 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func DBInsertWebhookSubscription(ctx context.Context, tx *sqlx.Tx, model *dtos.WebhookSubscriptionModel) (int64, error) {
+	var (
+		subscriptionID int64
+		res            sql.Result
+		err            error
+
+		now   = time.Now().UnixMilli()
+		query = `INSERT INTO 
+			webhook_subscriptions_tab 
+			(partner_id, url, event_types, secret, status,
+			 created_at, updated_at, deleted_at) VALUES 
+			(?, ?, ?, ?, ?,
+			 ?, ?, ?)`
+		args = []interface{}{
+			model.PartnerID, model.URL, model.EventTypes, model.Secret, model.Status,
+			now, now, 0,
+		}
+	)
+
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptionID, err = res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return subscriptionID, nil
+}
+
+func DBGetWebhookSubscriptionByID(ctx context.Context, subscriptionID int64) (*dtos.WebhookSubscriptionModel, error) {
+	var (
+		subscriptionModel dtos.WebhookSubscriptionModel
+		err               error
+
+		query = `
+			SELECT 
+				id, partner_id, url, event_types, secret, status,
+				created_at, updated_at, deleted_at
+			FROM webhook_subscriptions_tab
+			WHERE 
+			    id = ? 
+			  	AND deleted_at = 0
+			LIMIT 1`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, subscriptionID).Scan(subscriptionModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &subscriptionModel, nil
+}
+
+// DBGetActiveWebhookSubscriptionsByLoanID returns the active subscriptions of the loan's partner, none without a partner
+func DBGetActiveWebhookSubscriptionsByLoanID(ctx context.Context, tx *sqlx.Tx, loanID int64) ([]dtos.WebhookSubscriptionModel, error) {
+	var (
+		subscriptionModels []dtos.WebhookSubscriptionModel
+		err                error
+
+		args = []interface{}{
+			loanID,
+			constants.WebhookSubscriptionStatus_Active,
+		}
+		query = `
+			SELECT 
+				s.id, s.partner_id, s.url, s.event_types, s.secret, s.status,
+				s.created_at, s.updated_at, s.deleted_at
+			FROM webhook_subscriptions_tab s
+			JOIN loan_requests_tab l ON l.partner_id = s.partner_id
+			WHERE 
+			    l.id = ? 
+			  	AND s.status = ?
+			  	AND s.deleted_at = 0
+			ORDER BY s.id`
+	)
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &subscriptionModels, query, args...)
+	} else {
+		err = getDatabase().SelectContext(ctx, &subscriptionModels, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionModels, nil
+}
+
+func DBUpdateWebhookSubscriptionStatusByID(ctx context.Context, tx *sqlx.Tx, subscriptionID int64, status constants.WebhookSubscriptionStatus) error {
+	var err error
+
+	query := `UPDATE webhook_subscriptions_tab 
+		SET status = ?,
+		    updated_at = ?
+		WHERE id = ?`
+	args := []interface{}{
+		status,
+		time.Now().UnixMilli(),
+		subscriptionID,
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBBatchInsertWebhookDeliveries skips the deliveries already recorded for the same subscription and event
+func DBBatchInsertWebhookDeliveries(ctx context.Context, tx *sqlx.Tx, models []dtos.WebhookDeliveryModel) error {
+	var (
+		err error
+
+		now          = time.Now().UnixMilli()
+		placeholders = make([]string, 0, len(models))
+		args         = make([]interface{}, 0)
+	)
+
+	queryTemplate := `INSERT IGNORE INTO webhook_deliveries_tab 
+		(subscription_id, event_id, event_type, loan_id, payload,
+		status, attempts, next_attempt_at, last_response_code, last_error, delivered_at,
+		created_at, updated_at) VALUES %s`
+	insertPlaceholder := `(
+		?, ?, ?, ?, ?,
+		?, ?, ?, ?, ?, ?,
+		?, ?)`
+
+	for _, model := range models {
+		placeholders = append(placeholders, insertPlaceholder)
+		args = append(args,
+			model.SubscriptionID, model.EventID, model.EventType, model.LoanID, model.Payload,
+			constants.WebhookDeliveryStatus_Pending, 0, now, 0, "", 0,
+			now, now,
+		)
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, fmt.Sprintf(queryTemplate, strings.Join(placeholders, ",")), args...)
+	}
+	return err
+}
+
+func DBGetWebhookDeliveryByID(ctx context.Context, deliveryID int64) (*dtos.WebhookDeliveryModel, error) {
+	var (
+		deliveryModel dtos.WebhookDeliveryModel
+		err           error
+
+		query = `
+			SELECT 
+				id, subscription_id, event_id, event_type, loan_id, payload,
+				status, attempts, next_attempt_at, last_response_code, last_error, delivered_at,
+				replay_count, replayed_attempts, locked_until,
+				created_at, updated_at
+			FROM webhook_deliveries_tab
+			WHERE id = ?
+			LIMIT 1`
+	)
+
+	if err = getDatabase().QueryRowContext(ctx, query, deliveryID).Scan(deliveryModel.GetAll()...); err == sql.ErrNoRows {
+		return nil, constants.ErrRecordNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &deliveryModel, nil
+}
+
+// DBGetDueWebhookDeliveriesForUpdate returns the unclaimed pending deliveries whose next attempt is due,
+// skipping the ones a concurrent run is claiming
+func DBGetDueWebhookDeliveriesForUpdate(ctx context.Context, tx *sqlx.Tx, now, lastID int64, limit int) ([]dtos.WebhookDeliveryModel, error) {
+	var (
+		deliveryModels []dtos.WebhookDeliveryModel
+		err            error
+
+		args = []interface{}{
+			constants.WebhookDeliveryStatus_Pending,
+			now,
+			now,
+			lastID,
+			limit,
+		}
+		query = `
+			SELECT 
+				id, subscription_id, event_id, event_type, loan_id, payload,
+				status, attempts, next_attempt_at, last_response_code, last_error, delivered_at,
+				replay_count, replayed_attempts, locked_until,
+				created_at, updated_at
+			FROM webhook_deliveries_tab
+			WHERE 
+			    status = ?
+			  	AND next_attempt_at <= ?
+			  	AND locked_until <= ?
+			  	AND id > ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`
+	)
+
+	if err = tx.SelectContext(ctx, &deliveryModels, query, args...); err != nil {
+		return nil, err
+	}
+	return deliveryModels, nil
+}
+
+func DBUpdateWebhookDeliveryLockedUntilByIDs(ctx context.Context, tx *sqlx.Tx, ids []int64, lockedUntil int64) error {
+	var err error
+
+	query, args, err := sqlx.In(`UPDATE webhook_deliveries_tab 
+		SET locked_until = ?
+		WHERE id IN (?)`, lockedUntil, ids)
+	if err != nil {
+		return err
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBClaimWebhookDeliveryReplayByID moves a delivered or dead lettered delivery back to pending for a replay,
+// it returns false when the delivery is no longer in the status, e.g. a concurrent replay claimed it first
+func DBClaimWebhookDeliveryReplayByID(ctx context.Context, tx *sqlx.Tx, model *dtos.WebhookDeliveryModel, lockedUntil int64) (bool, error) {
+	var (
+		affectedRows int64
+		err          error
+	)
+
+	query := `UPDATE webhook_deliveries_tab 
+		SET status = ?,
+			replay_count = replay_count + 1,
+			replayed_attempts = attempts,
+			delivered_at = 0,
+			locked_until = ?,
+		    updated_at = ?
+		WHERE id = ? AND status = ?`
+	args := []interface{}{
+		constants.WebhookDeliveryStatus_Pending,
+		lockedUntil,
+		time.Now().UnixMilli(),
+		model.ID,
+		model.Status,
+	}
+
+	var res sql.Result
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	return affectedRows > 0, nil
+}
+
+func DBInsertWebhookDeliveryReplay(ctx context.Context, tx *sqlx.Tx, model *dtos.WebhookDeliveryReplayModel) error {
+	var err error
+
+	// follows the previous_error column's length
+	if len(model.PreviousError) > 1024 {
+		model.PreviousError = model.PreviousError[:1024]
+	}
+
+	query := `INSERT INTO 
+		webhook_delivery_replays_tab 
+		(delivery_id, previous_status, previous_attempts, previous_response_code, previous_error,
+		 actor, created_at) VALUES 
+		(?, ?, ?, ?, ?,
+		 ?, ?)`
+	args := []interface{}{
+		model.DeliveryID, model.PreviousStatus, model.PreviousAttempts, model.PreviousResponseCode, model.PreviousError,
+		model.Actor, time.Now().UnixMilli(),
+	}
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		_, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	return err
+}
+
+// DBGetWebhookDeliveriesBySubscriptionID lists the subscription's deliveries from the latest, status 0 means any status
+func DBGetWebhookDeliveriesBySubscriptionID(ctx context.Context, subscriptionID int64, status constants.WebhookDeliveryStatus, lastID int64, limit int) ([]dtos.WebhookDeliveryModel, error) {
+	var (
+		deliveryModels []dtos.WebhookDeliveryModel
+		err            error
+
+		args  = []interface{}{subscriptionID}
+		where = []string{"subscription_id = ?"}
+	)
+	if status != 0 {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+	if lastID != 0 {
+		where = append(where, "id < ?")
+		args = append(args, lastID)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+			SELECT 
+				id, subscription_id, event_id, event_type, loan_id, payload,
+				status, attempts, next_attempt_at, last_response_code, last_error, delivered_at,
+				replay_count, replayed_attempts, locked_until,
+				created_at, updated_at
+			FROM webhook_deliveries_tab
+			WHERE %s
+			ORDER BY id DESC
+			LIMIT ?`, strings.Join(where, " AND "))
+
+	if err = getDatabase().SelectContext(ctx, &deliveryModels, query, args...); err != nil {
+		return nil, err
+	}
+	return deliveryModels, nil
+}
+
+// DBUpdateWebhookDeliveryAttemptByID records the attempt and releases the claim, it returns false when the claim expired
+// and another run claimed the delivery
+func DBUpdateWebhookDeliveryAttemptByID(ctx context.Context, tx *sqlx.Tx, model *dtos.WebhookDeliveryModel) (bool, error) {
+	var (
+		affectedRows int64
+		err          error
+	)
+
+	// follows the last_error column's length
+	if len(model.LastError) > 1024 {
+		model.LastError = model.LastError[:1024]
+	}
+
+	query := `UPDATE webhook_deliveries_tab 
+		SET status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_response_code = ?,
+			last_error = ?,
+			delivered_at = ?,
+			locked_until = 0,
+		    updated_at = ?
+		WHERE id = ? AND locked_until = ?`
+	args := []interface{}{
+		model.Status,
+		model.Attempts,
+		model.NextAttemptAt,
+		model.LastResponseCode,
+		model.LastError,
+		model.DeliveredAt,
+		time.Now().UnixMilli(),
+		model.ID,
+		model.LockedUntil,
+	}
+
+	var res sql.Result
+	if tx != nil {
+		res, err = tx.ExecContext(ctx, query, args...)
+	} else {
+		res, err = getDatabase().ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if affectedRows, err = res.RowsAffected(); err != nil {
+		return false, err
+	}
+	return affectedRows > 0, nil
+}
@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventPublisher delivers the domain events to the downstream systems.
+// Publish returns once the event is acknowledged, the relay retries the event otherwise
+type EventPublisher interface {
+	Publish(ctx context.Context, event dtos.Event) error
+	Close() error
+}
+
+var (
+	eventPublisherOnce sync.Once
+	eventPublisher     EventPublisher
+	eventPublisherErr  error
+)
+
+// InitEventPublisher builds the publisher selected by event_publisher.type once, it is called at startup
+// so a misconfigured or unreachable broker fails the start instead of the relay
+func InitEventPublisher() error {
+	eventPublisherOnce.Do(func() {
+		eventPublisher, eventPublisherErr = newEventPublisher()
+	})
+	return eventPublisherErr
+}
+
+// GetEventPublisher returns the publisher built by InitEventPublisher, building it when the start did not
+func GetEventPublisher() (EventPublisher, error) {
+	if err := InitEventPublisher(); err != nil {
+		return nil, err
+	}
+	return eventPublisher, nil
+}
+
+// SetEventPublisher replaces the publisher, e.g. with an in-process stand-in, before it is used
+func SetEventPublisher(publisher EventPublisher) {
+	eventPublisherOnce.Do(func() {})
+	eventPublisher, eventPublisherErr = publisher, nil
+}
+
+// CloseEventPublisher flushes and closes the publisher on shutdown
+func CloseEventPublisher() error {
+	if eventPublisher == nil {
+		return nil
+	}
+	return eventPublisher.Close()
+}
+
+func newEventPublisher() (EventPublisher, error) {
+	conf := configs.Get().EventPublisher
+	switch conf.Type {
+	case "log":
+		return &logEventPublisher{}, nil
+	case "memory":
+		return NewMemoryEventPublisher(), nil
+	case "file":
+		publisher, err := newFileEventPublisher(conf.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	case "kafka":
+		return newKafkaEventPublisher(conf.KafkaBrokers, conf.KafkaTopic, nil), nil
+	case "nats":
+		streamConfig, err := newNATSStreamConfig(conf.NATSStream, conf.NATSSubjectPrefix, conf.NATSRetention, conf.NATSMaxAge, conf.NATSDuplicateWindow)
+		if err != nil {
+			return nil, err
+		}
+		publisher, err := newNATSEventPublisher(conf.NATSURL, conf.NATSSubjectPrefix, streamConfig)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	}
+	return nil, fmt.Errorf("%w. unknown event publisher type %q", constants.ErrInvalidValue, conf.Type)
+}
+
+type logEventPublisher struct{}
+
+func (p *logEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	logrus.Infof("event %s %s of loan %d: %s", event.EventID, event.EventType, event.LoanID, string(event.Payload))
+	return nil
+}
+
+func (p *logEventPublisher) Close() error {
+	return nil
+}
+
+// MemoryEventPublisher keeps the events in process, it stands in for a broker where none is available
+type MemoryEventPublisher struct {
+	mu     sync.Mutex
+	events []dtos.Event
+	err    error
+}
+
+func NewMemoryEventPublisher() *MemoryEventPublisher {
+	return &MemoryEventPublisher{}
+}
+
+func (p *MemoryEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *MemoryEventPublisher) Close() error {
+	return nil
+}
+
+// Events returns a copy of the published events in publishing order
+func (p *MemoryEventPublisher) Events() []dtos.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]dtos.Event(nil), p.events...)
+}
+
+// SetError makes the next publishes fail with err, nil makes them succeed again
+func (p *MemoryEventPublisher) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"loan-payment/dtos"
+)
+
+// fileEventPublisher appends the events as json lines, meant for local development
+type fileEventPublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileEventPublisher(path string) (*fileEventPublisher, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileEventPublisher{file: file}, nil
+}
+
+func (p *fileEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err = p.file.Write(append(content, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *fileEventPublisher) Close() error {
+	return p.file.Close()
+}
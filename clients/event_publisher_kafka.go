@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"loan-payment/dtos"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaEventPublisher struct {
+	writer *kafka.Writer
+}
+
+// newKafkaEventPublisher takes the transport to the brokers, nil for the default one
+func newKafkaEventPublisher(brokers []string, topic string, transport kafka.RoundTripper) *kafkaEventPublisher {
+	return &kafkaEventPublisher{
+		writer: &kafka.Writer{
+			Addr:      kafka.TCP(brokers...),
+			Topic:     topic,
+			Transport: transport,
+			// the loan id is the message key, hashing keeps a loan's events in one partition and in order
+			Balancer: &kafka.Hash{},
+			// every in-sync replica acknowledges the event before it is marked published
+			RequiredAcks: kafka.RequireAll,
+			// the relay writes one event at a time and waits for it, the default second long batching would hold every write
+			BatchTimeout: 5 * time.Millisecond,
+		},
+	}
+}
+
+func (p *kafkaEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(strconv.FormatInt(event.LoanID, 10)),
+		Value: content,
+		Headers: []kafka.Header{
+			{Key: "event_id", Value: []byte(event.EventID)},
+			{Key: "event_type", Value: []byte(event.EventType)},
+		},
+	})
+}
+
+func (p *kafkaEventPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,104 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/nats-io/nats.go"
+)
+
+var natsRetentionPolicies = map[string]nats.RetentionPolicy{
+	"limits":    nats.LimitsPolicy,
+	"interest":  nats.InterestPolicy,
+	"workqueue": nats.WorkQueuePolicy,
+}
+
+type natsEventPublisher struct {
+	conn          *nats.Conn
+	jetStream     nats.JetStreamContext
+	subjectPrefix string
+}
+
+// newNATSStreamConfig captures every event subject of the prefix
+func newNATSStreamConfig(name, subjectPrefix, retention string, maxAge, duplicateWindow time.Duration) (nats.StreamConfig, error) {
+	retentionPolicy, ok := natsRetentionPolicies[retention]
+	if !ok {
+		return nats.StreamConfig{}, fmt.Errorf("%w. unknown nats stream retention %q", constants.ErrInvalidValue, retention)
+	}
+	return nats.StreamConfig{
+		Name:       name,
+		Subjects:   []string{subjectPrefix + ".>"},
+		Retention:  retentionPolicy,
+		MaxAge:     maxAge,
+		Duplicates: duplicateWindow,
+	}, nil
+}
+
+func newNATSEventPublisher(url, subjectPrefix string, streamConfig nats.StreamConfig) (*natsEventPublisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	jetStream, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err = ensureNATSStream(jetStream, streamConfig); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &natsEventPublisher{
+		conn:          conn,
+		jetStream:     jetStream,
+		subjectPrefix: subjectPrefix,
+	}, nil
+}
+
+// ensureNATSStream creates the stream when it does not exist, otherwise the publishes would never be acknowledged.
+// An existing stream keeps its settings, it only has to capture the event subjects
+func ensureNATSStream(jetStream nats.JetStreamManager, streamConfig nats.StreamConfig) error {
+	streamInfo, err := jetStream.StreamInfo(streamConfig.Name)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if _, err = jetStream.AddStream(&streamConfig); err != nil {
+			return fmt.Errorf("failed to create nats stream %s for %v. %w", streamConfig.Name, streamConfig.Subjects, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get nats stream %s, is jetstream enabled? %w", streamConfig.Name, err)
+	}
+
+	for _, subject := range streamInfo.Config.Subjects {
+		if subject == streamConfig.Subjects[0] || subject == ">" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w. nats stream %s captures %v, not the event subjects %s",
+		constants.ErrInvalidValue, streamConfig.Name, streamInfo.Config.Subjects, streamConfig.Subjects[0])
+}
+
+// Publish waits for the stream's acknowledgement, the event id deduplicates the redelivery of a retried event
+func (p *natsEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.jetStream.Publish(p.subjectPrefix+"."+event.EventType, content, nats.MsgId(event.EventID), nats.Context(ctx))
+	return err
+}
+
+func (p *natsEventPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
@@ -0,0 +1,412 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	metadataAPI "github.com/segmentio/kafka-go/protocol/metadata"
+	produceAPI "github.com/segmentio/kafka-go/protocol/produce"
+)
+
+func newTestEvents(loanID int64, count int) []dtos.Event {
+	events := make([]dtos.Event, 0, count)
+	for i := 1; i <= count; i++ {
+		events = append(events, dtos.Event{
+			EventID:    fmt.Sprintf("event-%d-%d", loanID, i),
+			EventType:  "billing.due",
+			LoanID:     loanID,
+			Payload:    json.RawMessage(fmt.Sprintf(`{"recurring_index":%d}`, i)),
+			OccurredAt: int64(i),
+		})
+	}
+	return events
+}
+
+func assertEventIDs(t *testing.T, got, want []dtos.Event) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].EventID != want[i].EventID {
+			t.Errorf("event %d is %s, want %s", i, got[i].EventID, want[i].EventID)
+		}
+	}
+}
+
+func TestMemoryEventPublisher(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		publisher = NewMemoryEventPublisher()
+		events    = newTestEvents(1, 3)
+		errBroker = errors.New("broker unavailable")
+	)
+
+	for _, event := range events[:2] {
+		if err := publisher.Publish(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publisher.SetError(errBroker)
+	if err := publisher.Publish(ctx, events[2]); !errors.Is(err, errBroker) {
+		t.Fatalf("got error %v, want %v", err, errBroker)
+	}
+	assertEventIDs(t, publisher.Events(), events[:2])
+
+	publisher.SetError(nil)
+	if err := publisher.Publish(ctx, events[2]); err != nil {
+		t.Fatal(err)
+	}
+	assertEventIDs(t, publisher.Events(), events)
+}
+
+func TestFileEventPublisher(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		path   = filepath.Join(t.TempDir(), "events", "events.jsonl")
+		events = newTestEvents(1, 3)
+	)
+
+	publisher, err := newFileEventPublisher(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if err = publisher.Publish(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = publisher.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = publisher.Publish(ctx, events[0]); err == nil {
+		t.Fatal("publishing to a closed file should fail")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	written := make([]dtos.Event, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event dtos.Event
+		if err = json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		written = append(written, event)
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	assertEventIDs(t, written, events)
+	if string(written[2].Payload) != string(events[2].Payload) {
+		t.Errorf("payload is %s, want %s", written[2].Payload, events[2].Payload)
+	}
+}
+
+type kafkaTestMessage struct {
+	partition int32
+	key       string
+	value     []byte
+}
+
+// fakeKafkaTransport stands in for the brokers: it serves a topic's metadata and keeps the produced records
+type fakeKafkaTransport struct {
+	mu         sync.Mutex
+	topic      string
+	partitions int
+	errorCode  kafka.Error
+	messages   []kafkaTestMessage
+	batchSizes []int // the records of each produce request
+}
+
+func (f *fakeKafkaTransport) RoundTrip(ctx context.Context, addr net.Addr, request protocol.Message) (protocol.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch request := request.(type) {
+	case *metadataAPI.Request:
+		partitions := make([]metadataAPI.ResponsePartition, 0, f.partitions)
+		for i := 0; i < f.partitions; i++ {
+			partitions = append(partitions, metadataAPI.ResponsePartition{PartitionIndex: int32(i)})
+		}
+		return &metadataAPI.Response{
+			Topics: []metadataAPI.ResponseTopic{{Name: f.topic, Partitions: partitions}},
+		}, nil
+
+	case *produceAPI.Request:
+		response := &produceAPI.Response{}
+		for _, topic := range request.Topics {
+			responseTopic := produceAPI.ResponseTopic{Topic: topic.Topic}
+			for _, partition := range topic.Partitions {
+				responseTopic.Partitions = append(responseTopic.Partitions, produceAPI.ResponsePartition{
+					Partition: partition.Partition,
+					ErrorCode: int16(f.errorCode),
+				})
+				if f.errorCode != 0 {
+					continue
+				}
+
+				batchSize := 0
+				for {
+					record, err := partition.RecordSet.Records.ReadRecord()
+					if err == io.EOF {
+						break
+					} else if err != nil {
+						return nil, err
+					}
+					key, err := protocol.ReadAll(record.Key)
+					if err != nil {
+						return nil, err
+					}
+					value, err := protocol.ReadAll(record.Value)
+					if err != nil {
+						return nil, err
+					}
+					f.messages = append(f.messages, kafkaTestMessage{partition: partition.Partition, key: string(key), value: value})
+					batchSize++
+				}
+				f.batchSizes = append(f.batchSizes, batchSize)
+			}
+			response.Topics = append(response.Topics, responseTopic)
+		}
+		return response, nil
+	}
+	return nil, fmt.Errorf("unexpected kafka request %T", request)
+}
+
+func (f *fakeKafkaTransport) setErrorCode(errorCode kafka.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errorCode = errorCode
+}
+
+func (f *fakeKafkaTransport) getMessages() []kafkaTestMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]kafkaTestMessage(nil), f.messages...)
+}
+
+func (f *fakeKafkaTransport) getBatchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]int(nil), f.batchSizes...)
+}
+
+func TestKafkaEventPublisher(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		transport = &fakeKafkaTransport{topic: "loan-events", partitions: 4}
+		publisher = newKafkaEventPublisher([]string{"localhost:9092"}, "loan-events", transport)
+
+		firstLoanEvents  = newTestEvents(1, 3)
+		secondLoanEvents = newTestEvents(2, 3)
+	)
+	defer publisher.Close()
+
+	publishedCount := 0
+	for i := range firstLoanEvents {
+		for _, event := range []dtos.Event{firstLoanEvents[i], secondLoanEvents[i]} {
+			if err := publisher.Publish(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+			// the publish returns once its own write is produced, not batched with the later events
+			publishedCount++
+			if count := len(transport.getMessages()); count != publishedCount {
+				t.Errorf("got %d messages after publishing %d events", count, publishedCount)
+			}
+		}
+	}
+	if batchSizes := transport.getBatchSizes(); fmt.Sprint(batchSizes) != fmt.Sprint([]int{1, 1, 1, 1, 1, 1}) {
+		t.Errorf("got produce batches of %v, want a batch per event", batchSizes)
+	}
+
+	var (
+		messages       = transport.getMessages()
+		loanEvents     = make(map[string][]dtos.Event)
+		loanPartitions = make(map[string]int32)
+	)
+	if len(messages) != 6 {
+		t.Fatalf("got %d messages, want 6", len(messages))
+	}
+	for _, message := range messages {
+		var event dtos.Event
+		if err := json.Unmarshal(message.value, &event); err != nil {
+			t.Fatal(err)
+		}
+		if message.key != strconv.FormatInt(event.LoanID, 10) {
+			t.Errorf("message key is %s, want loan id %d", message.key, event.LoanID)
+		}
+		if partition, ok := loanPartitions[message.key]; ok && partition != message.partition {
+			t.Errorf("loan %s is written to partitions %d and %d", message.key, partition, message.partition)
+		}
+		loanPartitions[message.key] = message.partition
+		loanEvents[message.key] = append(loanEvents[message.key], event)
+	}
+	assertEventIDs(t, loanEvents["1"], firstLoanEvents)
+	assertEventIDs(t, loanEvents["2"], secondLoanEvents)
+
+	transport.setErrorCode(kafka.TopicAuthorizationFailed)
+	if err := publisher.Publish(ctx, newTestEvents(3, 1)[0]); err == nil {
+		t.Fatal("a rejected write should fail the publish")
+	}
+	if messages = transport.getMessages(); len(messages) != 6 {
+		t.Errorf("got %d messages after a rejected write, want 6", len(messages))
+	}
+}
+
+func runTestNATSServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	natsServer, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go natsServer.Start()
+	if !natsServer.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(natsServer.Shutdown)
+	return natsServer
+}
+
+func TestNATSEventPublisher(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		natsServer = runTestNATSServer(t)
+		events     = newTestEvents(1, 3)
+	)
+
+	streamConfig, err := newNATSStreamConfig("LOAN", "loan", "limits", time.Hour, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the stream is created on start
+	publisher, err := newNATSEventPublisher(natsServer.ClientURL(), "loan", streamConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.Close()
+
+	streamInfo, err := publisher.jetStream.StreamInfo("LOAN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(streamInfo.Config.Subjects) != "[loan.>]" || streamInfo.Config.Retention != nats.LimitsPolicy ||
+		streamInfo.Config.MaxAge != time.Hour || streamInfo.Config.Duplicates != time.Minute {
+		t.Errorf("got stream config %+v", streamInfo.Config)
+	}
+
+	for _, event := range events {
+		if err = publisher.Publish(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a retried event keeps its id, the stream drops the redelivery
+	if err = publisher.Publish(ctx, events[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := make([]dtos.Event, 0)
+	for sequence := uint64(1); ; sequence++ {
+		message, err := publisher.jetStream.GetMsg("LOAN", sequence)
+		if errors.Is(err, nats.ErrMsgNotFound) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if message.Subject != "loan.billing.due" {
+			t.Errorf("subject is %s, want loan.billing.due", message.Subject)
+		}
+
+		var event dtos.Event
+		if err = json.Unmarshal(message.Data, &event); err != nil {
+			t.Fatal(err)
+		}
+		stored = append(stored, event)
+	}
+	assertEventIDs(t, stored, events)
+
+	// a restart finds the stream and keeps it
+	restartedPublisher, err := newNATSEventPublisher(natsServer.ClientURL(), "loan", streamConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restartedPublisher.Close()
+
+	// no stream captures the subject, so nothing acknowledges the event
+	unroutedPublisher := &natsEventPublisher{conn: publisher.conn, jetStream: publisher.jetStream, subjectPrefix: "unrouted"}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err = unroutedPublisher.Publish(timeoutCtx, events[0]); err == nil {
+		t.Fatal("an unacknowledged event should fail the publish")
+	}
+
+	// the existing stream does not capture another prefix, the start fails instead of the publishes
+	otherStreamConfig, err := newNATSStreamConfig("LOAN", "other", "limits", 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newNATSEventPublisher(natsServer.ClientURL(), "other", otherStreamConfig); !errors.Is(err, constants.ErrInvalidValue) {
+		t.Errorf("got error %v, want %v", err, constants.ErrInvalidValue)
+	}
+}
+
+func TestNATSEventPublisherStartFailure(t *testing.T) {
+	if _, err := newNATSStreamConfig("LOAN", "loan", "forever", 0, time.Minute); !errors.Is(err, constants.ErrInvalidValue) {
+		t.Errorf("an unknown retention got error %v, want %v", err, constants.ErrInvalidValue)
+	}
+
+	streamConfig, err := newNATSStreamConfig("LOAN", "loan", "workqueue", 0, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newNATSEventPublisher("nats://127.0.0.1:1", "loan", streamConfig); err == nil {
+		t.Fatal("connecting to no server should fail")
+	}
+
+	natsServer, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go natsServer.Start()
+	if !natsServer.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	defer natsServer.Shutdown()
+	if _, err = newNATSEventPublisher(natsServer.ClientURL(), "loan", streamConfig); err == nil {
+		t.Fatal("starting against a server without jetstream should fail")
+	}
+}
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationSender delivers the notifications of one channel, Send returns once the provider accepts the notification
+type NotificationSender interface {
+	Send(ctx context.Context, notification dtos.Notification) error
+}
+
+var (
+	notificationSendersMu sync.Mutex
+	notificationSenders   = make(map[constants.NotificationChannel]NotificationSender)
+)
+
+// InitNotificationSenders builds the senders of the configured channels, it is called at startup
+// so a misconfigured sender fails the start instead of the first notification
+func InitNotificationSenders() error {
+	for _, channel := range constants.NotificationChannels {
+		if _, _, err := GetNotificationSender(channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNotificationSender returns the sender selected by the channel's notification config, false when the channel is disabled
+func GetNotificationSender(channel constants.NotificationChannel) (NotificationSender, bool, error) {
+	notificationSendersMu.Lock()
+	defer notificationSendersMu.Unlock()
+
+	if sender, ok := notificationSenders[channel]; ok {
+		return sender, true, nil
+	}
+
+	conf, ok := configs.Get().Notification.Channels[string(channel)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var sender NotificationSender
+	switch conf.Sender {
+	case "log":
+		sender = &logNotificationSender{}
+	case "smtp":
+		sender = newSMTPNotificationSender(conf.SMTPHost, conf.SMTPPort, conf.SMTPUsername, conf.SMTPPassword, conf.SMTPFrom)
+	case "http":
+		sender = newHTTPNotificationSender(conf.HTTPURL, conf.HTTPAPIKey)
+	default:
+		return nil, false, fmt.Errorf("%w. unknown %s notification sender %q", constants.ErrInvalidValue, channel, conf.Sender)
+	}
+	notificationSenders[channel] = sender
+	return sender, true, nil
+}
+
+// SetNotificationSender replaces the channel's sender, e.g. with an in-process stand-in
+func SetNotificationSender(channel constants.NotificationChannel, sender NotificationSender) {
+	notificationSendersMu.Lock()
+	defer notificationSendersMu.Unlock()
+
+	notificationSenders[channel] = sender
+}
+
+// logNotificationSender only logs the notifications, meant for local development
+type logNotificationSender struct{}
+
+func (s *logNotificationSender) Send(ctx context.Context, notification dtos.Notification) error {
+	logrus.Infof("%s notification %d to %s: %s %s", notification.Channel, notification.NotificationID,
+		notification.Recipient, notification.Subject, notification.Body)
+	return nil
+}
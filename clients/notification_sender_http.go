@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"loan-payment/dtos"
+)
+
+const (
+	httpNotificationSenderTimeout = 10 * time.Second
+)
+
+// httpNotificationSender posts the notifications as json to an sms or push gateway
+type httpNotificationSender struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func newHTTPNotificationSender(url, apiKey string) *httpNotificationSender {
+	return &httpNotificationSender{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: httpNotificationSenderTimeout},
+	}
+}
+
+func (s *httpNotificationSender) Send(ctx context.Context, notification dtos.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"loan-payment/dtos"
+)
+
+// headerNewlineReplacer strips the line breaks that would let a value inject its own headers
+var headerNewlineReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// smtpNotificationSender sends the email notifications as plain text
+type smtpNotificationSender struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func newSMTPNotificationSender(host, port, username, password, from string) *smtpNotificationSender {
+	sender := &smtpNotificationSender{
+		host: host,
+		addr: net.JoinHostPort(host, port),
+		from: headerNewlineReplacer.Replace(from),
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+func (s *smtpNotificationSender) Send(ctx context.Context, notification dtos.Notification) error {
+	recipient, err := mail.ParseAddress(headerNewlineReplacer.Replace(notification.Recipient))
+	if err != nil {
+		return fmt.Errorf("invalid email recipient. %w", err)
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", s.from)
+	fmt.Fprintf(&message, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerNewlineReplacer.Replace(notification.Subject)))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	if err = s.sendMail(ctx, recipient.Address, []byte(message.String())); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// sendMail follows smtp.SendMail, except the dial and the whole exchange are bound to ctx
+func (s *smtpNotificationSender) sendMail(ctx context.Context, recipient string, message []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	// a cancelled ctx interrupts the exchange in flight
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err = client.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(s.from); err != nil {
+		return err
+	}
+	if err = client.Rcpt(recipient); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(message); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,152 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"loan-payment/dtos"
+)
+
+type smtpTestMail struct {
+	recipients []string
+	data       string
+}
+
+// runTestSMTPServer serves one connection with the minimal smtp exchange, or never answers when stalled
+func runTestSMTPServer(t *testing.T, stalled bool) (string, string, <-chan smtpTestMail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan smtpTestMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if stalled {
+			// holds the connection open until the client gives up
+			_, _ = conn.Read(make([]byte, 1))
+			return
+		}
+
+		var (
+			mail   smtpTestMail
+			reader = bufio.NewReader(conn)
+			reply  = func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		)
+		reply("220 localhost")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				reply("250 ok")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				mail.recipients = append(mail.recipients, strings.TrimSpace(line[len("RCPT TO:"):]))
+				reply("250 ok")
+			case command == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply("250 ok")
+			case command == "QUIT":
+				reply("221 bye")
+				mails <- mail
+				return
+			default:
+				reply("502 unknown command")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host, port, mails
+}
+
+func TestSMTPNotificationSender(t *testing.T) {
+	host, port, mails := runTestSMTPServer(t, false)
+	sender := newSMTPNotificationSender(host, port, "", "", "no-reply@example.com")
+
+	err := sender.Send(context.Background(), dtos.Notification{
+		Channel:   "email",
+		Recipient: "budi@example.com\r\nBcc: eve@example.com",
+		Subject:   "Your bill is due today\r\nBcc: eve@example.com",
+		Body:      "Hi Budi,\nplease pay.",
+	})
+	if err == nil {
+		t.Fatal("a recipient with injected headers should be rejected")
+	}
+
+	err = sender.Send(context.Background(), dtos.Notification{
+		Channel:   "email",
+		Recipient: "budi@example.com",
+		Subject:   "Your bill is due today\r\nBcc: eve@example.com",
+		Body:      "Hi Budi,\nplease pay.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-mails
+	if len(mail.recipients) != 1 || mail.recipients[0] != "<budi@example.com>" {
+		t.Errorf("got recipients %v, want <budi@example.com>", mail.recipients)
+	}
+	headers, body, _ := strings.Cut(mail.data, "\r\n\r\n")
+	for _, header := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(strings.ToLower(header), "bcc:") {
+			t.Errorf("injected header %q", header)
+		}
+	}
+	if !strings.Contains(headers, "To: <budi@example.com>") {
+		t.Errorf("headers %q have no recipient", headers)
+	}
+	if body != "Hi Budi,\r\nplease pay.\r\n" {
+		t.Errorf("got body %q", body)
+	}
+}
+
+func TestSMTPNotificationSenderHonorsContext(t *testing.T) {
+	host, port, _ := runTestSMTPServer(t, true)
+	sender := newSMTPNotificationSender(host, port, "", "", "no-reply@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+	err := sender.Send(ctx, dtos.Notification{Channel: "email", Recipient: "budi@example.com", Subject: "subject", Body: "body"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("send returned after %s", elapsed)
+	}
+}
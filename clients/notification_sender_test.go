@@ -0,0 +1,46 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+)
+
+func TestInitNotificationSenders(t *testing.T) {
+	configs.InitFromFile("test", "../configs/app.yaml")
+	channels := configs.Get().Notification.Channels
+	defer func() {
+		notificationSendersMu.Lock()
+		defer notificationSendersMu.Unlock()
+		notificationSenders = make(map[constants.NotificationChannel]NotificationSender)
+	}()
+
+	if err := InitNotificationSenders(); err != nil {
+		t.Fatal(err)
+	}
+	for _, channel := range constants.NotificationChannels {
+		if sender, ok, err := GetNotificationSender(channel); err != nil || !ok || sender == nil {
+			t.Errorf("%s: got sender %v, %t and error %v", channel, sender, ok, err)
+		}
+	}
+
+	// a disabled channel has no sender, an unknown sender fails the start instead of the first notification
+	notificationSendersMu.Lock()
+	delete(notificationSenders, constants.NotificationChannel_Email)
+	delete(notificationSenders, constants.NotificationChannel_SMS)
+	notificationSendersMu.Unlock()
+	delete(channels, string(constants.NotificationChannel_Email))
+	channels[string(constants.NotificationChannel_SMS)] = configs.NotificationChannel{Sender: "pigeon"}
+
+	if sender, ok, err := GetNotificationSender(constants.NotificationChannel_Email); err != nil || ok || sender != nil {
+		t.Errorf("disabled channel: got sender %v, %t and error %v", sender, ok, err)
+	}
+	if err := InitNotificationSenders(); !errors.Is(err, constants.ErrInvalidValue) {
+		t.Errorf("got error %v, want %v", err, constants.ErrInvalidValue)
+	}
+	if _, _, err := GetNotificationSender(constants.NotificationChannel_SMS); !errors.Is(err, constants.ErrInvalidValue) {
+		t.Errorf("got error %v, want %v", err, constants.ErrInvalidValue)
+	}
+}
@@ -0,0 +1,120 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+)
+
+const (
+	PaymentCallbackTimestampHeader = "X-Callback-Timestamp"
+	PaymentCallbackSignatureHeader = "X-Callback-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+
+	paymentCallbackSignaturePrefix = "sha256="
+)
+
+// PaymentProvider issues the virtual accounts borrowers pay to, and verifies the provider's paid callbacks
+type PaymentProvider interface {
+	CreateVirtualAccount(ctx context.Context, request dtos.CreateVirtualAccountRequest) (*dtos.VirtualAccount, error)
+	// ParsePaidCallback returns constants.ErrInvalidSignature when the callback is not signed by the provider or is too old
+	ParsePaidCallback(timestamp, signature string, body []byte) (*dtos.VirtualAccountPaidCallback, error)
+}
+
+var paymentProvider PaymentProvider
+
+func GetPaymentProvider() PaymentProvider {
+	if paymentProvider != nil {
+		return paymentProvider
+	}
+
+	conf := configs.Get().PaymentProvider
+	paymentProvider = &httpPaymentProvider{
+		baseURL:           strings.TrimSuffix(conf.BaseURL, "/"),
+		apiKey:            conf.APIKey,
+		callbackSecret:    conf.CallbackSecret,
+		callbackTolerance: conf.CallbackTolerance,
+		client:            &http.Client{Timeout: conf.Timeout},
+	}
+	return paymentProvider
+}
+
+// SetPaymentProvider replaces the provider, e.g. with an in-process stand-in
+func SetPaymentProvider(provider PaymentProvider) {
+	paymentProvider = provider
+}
+
+// httpPaymentProvider speaks the provider's json api, cmd/fakeprovider serves the same api locally
+type httpPaymentProvider struct {
+	baseURL           string
+	apiKey            string
+	callbackSecret    string
+	callbackTolerance time.Duration
+	client            *http.Client
+}
+
+func (p *httpPaymentProvider) CreateVirtualAccount(ctx context.Context, request dtos.CreateVirtualAccountRequest) (*dtos.VirtualAccount, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/virtual-accounts", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to create virtual account, status %d: %s", resp.StatusCode, string(content))
+	}
+
+	var virtualAccount dtos.VirtualAccount
+	if err = json.Unmarshal(content, &virtualAccount); err != nil {
+		return nil, err
+	}
+	return &virtualAccount, nil
+}
+
+func (p *httpPaymentProvider) ParsePaidCallback(timestamp, signature string, body []byte) (*dtos.VirtualAccountPaidCallback, error) {
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w. timestamp", constants.ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > p.callbackTolerance || age < -p.callbackTolerance {
+		return nil, fmt.Errorf("%w. timestamp is out of the tolerance", constants.ErrInvalidSignature)
+	}
+
+	message := append([]byte(timestamp+"."), body...)
+	if !strings.HasPrefix(signature, paymentCallbackSignaturePrefix) ||
+		!utils.VerifyHMACSHA256(p.callbackSecret, message, strings.TrimPrefix(signature, paymentCallbackSignaturePrefix)) {
+		return nil, constants.ErrInvalidSignature
+	}
+
+	var callback dtos.VirtualAccountPaidCallback
+	if err = json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+	}
+	return &callback, nil
+}
@@ -0,0 +1,251 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/fakeprovider"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	testProviderAPIKey         = "test-api-key"
+	testProviderCallbackSecret = "test-callback-secret"
+)
+
+// callbackReceiver verifies the paid callbacks as the callback handler does, and posts each callback id once
+type callbackReceiver struct {
+	mu         sync.Mutex
+	provider   PaymentProvider
+	deliveries []dtos.VirtualAccountPaidCallback
+	posted     map[string]decimal.Decimal
+}
+
+func (c *callbackReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	callback, err := c.provider.ParsePaidCallback(r.Header.Get(PaymentCallbackTimestampHeader), r.Header.Get(PaymentCallbackSignatureHeader), body)
+	if errors.Is(err, constants.ErrInvalidSignature) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deliveries = append(c.deliveries, *callback)
+	if _, ok := c.posted[callback.CallbackID]; !ok {
+		c.posted[callback.CallbackID] = callback.Amount
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *callbackReceiver) getDeliveries() ([]dtos.VirtualAccountPaidCallback, map[string]decimal.Decimal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	posted := make(map[string]decimal.Decimal, len(c.posted))
+	for callbackID, amount := range c.posted {
+		posted[callbackID] = amount
+	}
+	return append([]dtos.VirtualAccountPaidCallback(nil), c.deliveries...), posted
+}
+
+func newTestPaymentProvider(baseURL, apiKey string) *httpPaymentProvider {
+	return &httpPaymentProvider{
+		baseURL:           baseURL,
+		apiKey:            apiKey,
+		callbackSecret:    testProviderCallbackSecret,
+		callbackTolerance: 5 * time.Minute,
+		client:            &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type testCallbackResult struct {
+	CallbackID string `json:"callback_id"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+}
+
+// postFakeProvider calls the fake provider's simulation endpoints, which the provider client does not cover
+func postFakeProvider(t *testing.T, url, body string) (int, testCallbackResult) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testProviderAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result testCallbackResult
+	if resp.StatusCode == http.StatusOK {
+		if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+func TestPaymentProviderWithFakeProvider(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		receiver = &callbackReceiver{posted: make(map[string]decimal.Decimal)}
+		amount   = decimal.RequireFromString("221500")
+	)
+	callbackServer := httptest.NewServer(receiver)
+	defer callbackServer.Close()
+	providerServer := httptest.NewServer(fakeprovider.NewServer(testProviderAPIKey, testProviderCallbackSecret, callbackServer.URL))
+	defer providerServer.Close()
+
+	provider := newTestPaymentProvider(providerServer.URL, testProviderAPIKey)
+	receiver.provider = provider
+
+	// create the account, a retried create with the same external id returns the same account
+	request := dtos.CreateVirtualAccountRequest{
+		ExternalID:     "loan-42-billing-3",
+		BankCode:       "BCA",
+		Name:           "Budi",
+		ExpectedAmount: amount,
+		IsSingleUse:    true,
+	}
+	virtualAccount, err := provider.CreateVirtualAccount(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if virtualAccount.VANumber == "" || virtualAccount.Status != "active" || !virtualAccount.ExpectedAmount.Equal(amount) {
+		t.Fatalf("got virtual account %+v", virtualAccount)
+	}
+	retriedAccount, err := provider.CreateVirtualAccount(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retriedAccount.ID != virtualAccount.ID || retriedAccount.VANumber != virtualAccount.VANumber {
+		t.Errorf("retried create returned %s, want %s", retriedAccount.VANumber, virtualAccount.VANumber)
+	}
+	if _, err = newTestPaymentProvider(providerServer.URL, "wrong-api-key").CreateVirtualAccount(ctx, request); err == nil {
+		t.Error("creating with a wrong api key should fail")
+	}
+
+	// the signed paid callback is verified and posted
+	paymentsURL := providerServer.URL + "/virtual-accounts/" + virtualAccount.VANumber + "/payments"
+	if statusCode, _ := postFakeProvider(t, paymentsURL, `{"amount":"1000"}`); statusCode != http.StatusBadRequest {
+		t.Errorf("paying another amount than expected got status %d, want %d", statusCode, http.StatusBadRequest)
+	}
+	statusCode, result := postFakeProvider(t, paymentsURL, `{"amount":"221500"}`)
+	if statusCode != http.StatusOK || result.StatusCode != http.StatusOK {
+		t.Fatalf("paying got status %d and callback %+v", statusCode, result)
+	}
+	deliveries, posted := receiver.getDeliveries()
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d callbacks, want 1", len(deliveries))
+	}
+	callback := deliveries[0]
+	if callback.CallbackID != result.CallbackID || callback.VANumber != virtualAccount.VANumber ||
+		callback.ExternalID != request.ExternalID || !callback.Amount.Equal(amount) {
+		t.Errorf("got callback %+v", callback)
+	}
+
+	// a single use account is closed once paid
+	if statusCode, _ = postFakeProvider(t, paymentsURL, `{"amount":"221500"}`); statusCode != http.StatusBadRequest {
+		t.Errorf("paying a paid single use account got status %d, want %d", statusCode, http.StatusBadRequest)
+	}
+
+	// the replayed callback is signed again and carries the same callback id, so it is posted once
+	statusCode, replayResult := postFakeProvider(t, providerServer.URL+"/callbacks/"+result.CallbackID+"/resend", "")
+	if statusCode != http.StatusOK || replayResult.StatusCode != http.StatusOK {
+		t.Fatalf("replaying got status %d and callback %+v", statusCode, replayResult)
+	}
+	deliveries, posted = receiver.getDeliveries()
+	if len(deliveries) != 2 || deliveries[1].CallbackID != result.CallbackID {
+		t.Fatalf("got callbacks %+v, want the replay of %s", deliveries, result.CallbackID)
+	}
+	if len(posted) != 1 || !posted[result.CallbackID].Equal(amount) {
+		t.Errorf("got posted %v, want %s once", posted, amount)
+	}
+	if statusCode, _ = postFakeProvider(t, providerServer.URL+"/callbacks/unknown/resend", ""); statusCode != http.StatusNotFound {
+		t.Errorf("replaying an unknown callback got status %d, want %d", statusCode, http.StatusNotFound)
+	}
+
+	// a provider signing with another secret is refused, nothing is posted
+	forgedServer := httptest.NewServer(fakeprovider.NewServer(testProviderAPIKey, "another-secret", callbackServer.URL))
+	defer forgedServer.Close()
+	forgedAccount, err := newTestPaymentProvider(forgedServer.URL, testProviderAPIKey).CreateVirtualAccount(ctx, dtos.CreateVirtualAccountRequest{ExternalID: "loan-42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusCode, forgedResult := postFakeProvider(t, forgedServer.URL+"/virtual-accounts/"+forgedAccount.VANumber+"/payments", `{"amount":"5000"}`)
+	if statusCode != http.StatusOK || forgedResult.StatusCode != http.StatusUnauthorized {
+		t.Errorf("forged callback got status %d and callback %+v, want %d", statusCode, forgedResult, http.StatusUnauthorized)
+	}
+	if deliveries, _ = receiver.getDeliveries(); len(deliveries) != 2 {
+		t.Errorf("got %d callbacks after the forged one, want 2", len(deliveries))
+	}
+}
+
+func TestParsePaidCallback(t *testing.T) {
+	var (
+		provider = newTestPaymentProvider("", "")
+		body     = []byte(`{"callback_id":"callback-1","va_number":"8808000000000001","amount":"221500"}`)
+		now      = time.Now().Unix()
+	)
+	sign := func(secret string, signedAt int64, body []byte) (string, string) {
+		timestamp := strconv.FormatInt(signedAt, 10)
+		return timestamp, paymentCallbackSignaturePrefix + utils.SignHMACSHA256(secret, append([]byte(timestamp+"."), body...))
+	}
+
+	timestamp, signature := sign(testProviderCallbackSecret, now, body)
+	callback, err := provider.ParsePaidCallback(timestamp, signature, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callback.CallbackID != "callback-1" || !callback.Amount.Equal(decimal.RequireFromString("221500")) {
+		t.Errorf("got callback %+v", callback)
+	}
+
+	var (
+		wrongTimestamp, wrongSignature = sign("another-secret", now, body)
+		staleTimestamp, staleSignature = sign(testProviderCallbackSecret, now-int64(time.Hour/time.Second), body)
+	)
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+	}{
+		{name: "tampered body", timestamp: timestamp, signature: signature, body: bytes.Replace(body, []byte("221500"), []byte("2215000"), 1)},
+		{name: "missing prefix", timestamp: timestamp, signature: strings.TrimPrefix(signature, paymentCallbackSignaturePrefix), body: body},
+		{name: "invalid timestamp", timestamp: "yesterday", signature: signature, body: body},
+		{name: "another secret", timestamp: wrongTimestamp, signature: wrongSignature, body: body},
+		{name: "stale timestamp", timestamp: staleTimestamp, signature: staleSignature, body: body},
+	}
+	for _, tt := range tests {
+		if _, err = provider.ParsePaidCallback(tt.timestamp, tt.signature, tt.body); !errors.Is(err, constants.ErrInvalidSignature) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, constants.ErrInvalidSignature)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"loan-payment/configs"
+)
+
+var webhookHTTPClient *http.Client
+
+func getWebhookHTTPClient() *http.Client {
+	if webhookHTTPClient != nil {
+		return webhookHTTPClient
+	}
+
+	webhookHTTPClient = newWebhookHTTPClient(configs.Get().Webhook.Timeout)
+	return webhookHTTPClient
+}
+
+// newWebhookHTTPClient does not follow redirects, the signed payload is only sent to the subscribed url
+// and a redirect response fails the attempt
+func newWebhookHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// PostWebhook sends the body as json and returns the response status code
+func PostWebhook(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := getWebhookHTTPClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,37 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPostWebhookDoesNotFollowRedirects(t *testing.T) {
+	var redirectedCount int32
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&redirectedCount, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer targetServer.Close()
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetServer.URL, http.StatusTemporaryRedirect)
+	}))
+	defer webhookServer.Close()
+
+	webhookHTTPClient = newWebhookHTTPClient(5 * time.Second)
+	defer func() { webhookHTTPClient = nil }()
+
+	statusCode, err := PostWebhook(context.Background(), webhookServer.URL, map[string]string{"X-Webhook-Signature": "sha256=signature"}, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusCode != http.StatusTemporaryRedirect {
+		t.Errorf("got status %d, want the redirect %d", statusCode, http.StatusTemporaryRedirect)
+	}
+	if count := atomic.LoadInt32(&redirectedCount); count != 0 {
+		t.Errorf("the redirect target got %d requests, want none", count)
+	}
+}
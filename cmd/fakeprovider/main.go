@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"loan-payment/configs"
+	"loan-payment/fakeprovider"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runs the fake payment provider with the api key and callback secret of configs/app.yaml, from the repository root
+func main() {
+	configs.Init("fakeprovider")
+
+	var (
+		addr        = flag.String("addr", ":8090", "listen address")
+		callbackURL = flag.String("callback-url", "http://localhost:8080/callbacks/virtual-accounts", "receives the paid callbacks")
+	)
+	flag.Parse()
+
+	providerConfig := configs.Get().PaymentProvider
+	server := fakeprovider.NewServer(providerConfig.APIKey, providerConfig.CallbackSecret, *callbackURL)
+
+	logrus.Infof("fake payment provider listening on %s, sending callbacks to %s", *addr, *callbackURL)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		logrus.Fatal(err)
+	}
+}
@@ -3,7 +3,9 @@ package configs
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
@@ -18,6 +20,22 @@ type configYAML struct {
 	HttpPort string `yaml:"httpport"`
 
 	DB dbYAML `yaml:"db"`
+
+	RegulatoryCap regulatoryCapYAML `yaml:"regulatory_cap"`
+
+	GLExport glExportYAML `yaml:"gl_export"`
+
+	InterestAccrual interestAccrualYAML `yaml:"interest_accrual"`
+
+	ECL eclYAML `yaml:"ecl"`
+
+	EventPublisher eventPublisherYAML `yaml:"event_publisher"`
+
+	Webhook webhookYAML `yaml:"webhook"`
+
+	Notification notificationYAML `yaml:"notification"`
+
+	PaymentProvider paymentProviderYAML `yaml:"payment_provider"`
 }
 
 type dbConfigYAML struct {
@@ -34,6 +52,109 @@ type dbYAML struct {
 	Master dbConfigYAML `yaml:"master"`
 }
 
+type regulatoryCapYAML struct {
+	MaxDailyEconomicBenefitRate string `yaml:"max_daily_economic_benefit_rate"`
+	MaxTotalPenaltyRate         string `yaml:"max_total_penalty_rate"`
+	NearCapThreshold            string `yaml:"near_cap_threshold"`
+}
+
+type glExportYAML struct {
+	OutputDir       string                   `yaml:"output_dir"`
+	Timezone        string                   `yaml:"timezone"`
+	ChartOfAccounts map[string]glAccountYAML `yaml:"chart_of_accounts"` // keyed by the ledger account name
+}
+
+type glAccountYAML struct {
+	Code string `yaml:"code"`
+	Name string `yaml:"name"`
+}
+
+type interestAccrualYAML struct {
+	NonAccrualDaysPastDue int `yaml:"non_accrual_days_past_due"`
+}
+
+type eclYAML struct {
+	Stage2DaysPastDue int                      `yaml:"stage_2_days_past_due"`
+	Stage3DaysPastDue int                      `yaml:"stage_3_days_past_due"`
+	Rates             map[string][]eclRateYAML `yaml:"rates"` // keyed by the product code
+}
+
+type eclRateYAML struct {
+	Stage int8   `yaml:"stage"`
+	PD    string `yaml:"pd"`
+	LGD   string `yaml:"lgd"`
+}
+
+type eventPublisherYAML struct {
+	Type        string                  `yaml:"type"`
+	MaxAttempts int                     `yaml:"max_attempts"`
+	File        fileEventPublisherYAML  `yaml:"file"`
+	Kafka       kafkaEventPublisherYAML `yaml:"kafka"`
+	NATS        natsEventPublisherYAML  `yaml:"nats"`
+}
+
+type fileEventPublisherYAML struct {
+	Path string `yaml:"path"`
+}
+
+type kafkaEventPublisherYAML struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+}
+
+type natsEventPublisherYAML struct {
+	URL                    string `yaml:"url"`
+	SubjectPrefix          string `yaml:"subject_prefix"`
+	Stream                 string `yaml:"stream"`
+	Retention              string `yaml:"retention"`
+	MaxAgeHours            int    `yaml:"max_age_hours"`
+	DuplicateWindowSeconds int    `yaml:"duplicate_window_seconds"`
+}
+
+type webhookYAML struct {
+	TimeoutSeconds        int `yaml:"timeout_seconds"`
+	MaxAttempts           int `yaml:"max_attempts"`
+	InitialBackoffSeconds int `yaml:"initial_backoff_seconds"`
+	MaxBackoffSeconds     int `yaml:"max_backoff_seconds"`
+}
+
+type notificationYAML struct {
+	Timezone      string                  `yaml:"timezone"`
+	DefaultLocale string                  `yaml:"default_locale"`
+	Email         notificationChannelYAML `yaml:"email"`
+	SMS           notificationChannelYAML `yaml:"sms"`
+	Push          notificationChannelYAML `yaml:"push"`
+}
+
+type notificationChannelYAML struct {
+	Sender string                     `yaml:"sender"`
+	SMTP   smtpNotificationSenderYAML `yaml:"smtp"`
+	HTTP   httpNotificationSenderYAML `yaml:"http"`
+}
+
+type smtpNotificationSenderYAML struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type httpNotificationSenderYAML struct {
+	URL    string `yaml:"url"`
+	APIKey string `yaml:"api_key"`
+}
+
+type paymentProviderYAML struct {
+	Name                     string `yaml:"name"`
+	BaseURL                  string `yaml:"base_url"`
+	APIKey                   string `yaml:"api_key"`
+	BankCode                 string `yaml:"bank_code"`
+	CallbackSecret           string `yaml:"callback_secret"`
+	CallbackToleranceSeconds int    `yaml:"callback_tolerance_seconds"`
+	TimeoutSeconds           int    `yaml:"timeout_seconds"`
+}
+
 type Config struct {
 	// app
 	AppName  string
@@ -42,6 +163,22 @@ type Config struct {
 	CronCheckLoanStatusSchedule string
 
 	DBMaster *sqlDatabase
+
+	RegulatoryCap *regulatoryCap
+
+	GLExport *glExport
+
+	InterestAccrual *interestAccrual
+
+	ECL *ecl
+
+	EventPublisher *eventPublisher
+
+	Webhook *webhook
+
+	Notification *notification
+
+	PaymentProvider *paymentProvider
 }
 
 type sqlDatabase struct {
@@ -55,12 +192,112 @@ type sqlDatabase struct {
 	Name             string
 }
 
+// zero value disables the cap
+type regulatoryCap struct {
+	MaxDailyEconomicBenefitRate decimal.Decimal // inflated by 10^2, interest and fees per day of the loan amount
+	MaxTotalPenaltyRate         decimal.Decimal // inflated by 10^2, of the loan amount
+	NearCapThreshold            decimal.Decimal // inflated by 10^2, of the cap
+}
+
+type glExport struct {
+	OutputDir       string
+	Location        *time.Location // the export date follows this timezone
+	ChartOfAccounts map[string]GLAccount
+}
+
+type GLAccount struct {
+	Code string
+	Name string
+}
+
+// zero value never stops the accrual
+type interestAccrual struct {
+	NonAccrualDaysPastDue int // the interest stops accruing once the loan is past due for more days than this
+}
+
+type ecl struct {
+	Stage2DaysPastDue int // the loan moves to stage 2 once it is past due for more days than this
+	Stage3DaysPastDue int // and to stage 3 past this one
+	Rates             map[string]map[int8]ECLRate
+}
+
+type ECLRate struct {
+	PD  decimal.Decimal // probability of default, inflated by 10^2
+	LGD decimal.Decimal // loss given default, inflated by 10^2
+}
+
+const (
+	ECLDefaultRatesKey = "default" // used by the loans whose product has no rates
+)
+
+type eventPublisher struct {
+	Type        string // log, memory, file, kafka or nats
+	MaxAttempts int    // an event failing this many publishes is dead lettered
+
+	FilePath string // jsonl, one event per line
+
+	KafkaBrokers []string
+	KafkaTopic   string // keyed by the loan id, so a loan's events stay in one partition
+
+	NATSURL             string
+	NATSSubjectPrefix   string        // published through JetStream as <prefix>.<event type>
+	NATSStream          string        // captures <prefix>.>, created on start when it does not exist
+	NATSRetention       string        // limits, interest or workqueue
+	NATSMaxAge          time.Duration // 0 keeps the events until the retention removes them
+	NATSDuplicateWindow time.Duration // a retried event is deduplicated by its event id within the window
+}
+
+type notification struct {
+	Location      *time.Location // the reminder and overdue days follow this timezone
+	DefaultLocale string         // used by the users without a locale
+	Channels      map[string]NotificationChannel
+}
+
+// NotificationChannel is keyed by email, sms or push in the notification config, a channel without a sender is disabled
+type NotificationChannel struct {
+	Sender string // log, smtp or http
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	HTTPURL    string // receives the notification as json
+	HTTPAPIKey string
+}
+
+type paymentProvider struct {
+	Name              string // recorded with the virtual accounts and their payments
+	BaseURL           string
+	APIKey            string
+	BankCode          string // the bank issuing the virtual accounts
+	CallbackSecret    string // verifies the paid callbacks' HMAC-SHA256 signature
+	CallbackTolerance time.Duration
+	Timeout           time.Duration
+}
+
+type webhook struct {
+	Timeout        time.Duration
+	MaxAttempts    int // the delivery is dead lettered after this many failed attempts
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration // the backoff doubles on every failed attempt up to this
+}
+
 var appConfig *Config
 
+const defaultConfigPath = "./configs/app.yaml"
+
+// Init loads the config from configs/app.yaml, relative to the working directory
 func Init(serviceName string) {
+	InitFromFile(serviceName, defaultConfigPath)
+}
+
+// InitFromFile loads the config from the path, e.g. by the tests which run from their package's directory
+func InitFromFile(serviceName, path string) {
 	cfg := &configYAML{}
-	logrus.Info("loading config from local config")
-	if err := loadConfigFromLocalFile(cfg); err != nil {
+	logrus.Infof("loading config from local config %s", path)
+	if err := loadConfigFromLocalFile(cfg, path); err != nil {
 		panic(fmt.Sprintf("failed reading local config, err: %v", err))
 	}
 
@@ -68,14 +305,22 @@ func Init(serviceName string) {
 	appConfig = &Config{}
 	appConfig.initCommonConfig(cfg)
 	appConfig.initSqlDBConfig(cfg)
+	appConfig.initRegulatoryCapConfig(cfg)
+	appConfig.initGLExportConfig(cfg)
+	appConfig.initInterestAccrualConfig(cfg)
+	appConfig.initECLConfig(cfg)
+	appConfig.initEventPublisherConfig(cfg)
+	appConfig.initWebhookConfig(cfg)
+	appConfig.initNotificationConfig(cfg)
+	appConfig.initPaymentProviderConfig(cfg)
 }
 
 func Get() *Config {
 	return appConfig
 }
 
-func loadConfigFromLocalFile(c *configYAML) error {
-	yamlFile, err := os.ReadFile("./configs/app.yaml")
+func loadConfigFromLocalFile(c *configYAML, path string) error {
+	yamlFile, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
@@ -118,3 +363,198 @@ func (c *Config) initSqlDBConfig(cfg *configYAML) {
 		MaxOpen: cfg.DB.Master.Maxopen,
 	}
 }
+
+func (c *Config) initRegulatoryCapConfig(cfg *configYAML) {
+	c.RegulatoryCap = &regulatoryCap{
+		MaxDailyEconomicBenefitRate: mustParseDecimal("regulatory_cap.max_daily_economic_benefit_rate", cfg.RegulatoryCap.MaxDailyEconomicBenefitRate),
+		MaxTotalPenaltyRate:         mustParseDecimal("regulatory_cap.max_total_penalty_rate", cfg.RegulatoryCap.MaxTotalPenaltyRate),
+		NearCapThreshold:            mustParseDecimal("regulatory_cap.near_cap_threshold", cfg.RegulatoryCap.NearCapThreshold),
+	}
+}
+
+func (c *Config) initGLExportConfig(cfg *configYAML) {
+	c.GLExport = &glExport{
+		OutputDir:       cfg.GLExport.OutputDir,
+		Location:        time.Local,
+		ChartOfAccounts: make(map[string]GLAccount, len(cfg.GLExport.ChartOfAccounts)),
+	}
+	if c.GLExport.OutputDir == "" {
+		c.GLExport.OutputDir = "./exports"
+	}
+
+	if cfg.GLExport.Timezone != "" {
+		location, err := time.LoadLocation(cfg.GLExport.Timezone)
+		if err != nil {
+			panic(fmt.Sprintf("failed parsing config gl_export.timezone, err: %v", err))
+		}
+		c.GLExport.Location = location
+	}
+
+	for name, account := range cfg.GLExport.ChartOfAccounts {
+		c.GLExport.ChartOfAccounts[name] = GLAccount{
+			Code: account.Code,
+			Name: account.Name,
+		}
+	}
+}
+
+func (c *Config) initInterestAccrualConfig(cfg *configYAML) {
+	c.InterestAccrual = &interestAccrual{
+		NonAccrualDaysPastDue: cfg.InterestAccrual.NonAccrualDaysPastDue,
+	}
+}
+
+func (c *Config) initECLConfig(cfg *configYAML) {
+	c.ECL = &ecl{
+		Stage2DaysPastDue: cfg.ECL.Stage2DaysPastDue,
+		Stage3DaysPastDue: cfg.ECL.Stage3DaysPastDue,
+		Rates:             make(map[string]map[int8]ECLRate, len(cfg.ECL.Rates)),
+	}
+	if c.ECL.Stage2DaysPastDue == 0 {
+		c.ECL.Stage2DaysPastDue = 30
+	}
+	if c.ECL.Stage3DaysPastDue == 0 {
+		c.ECL.Stage3DaysPastDue = 90
+	}
+
+	for productCode, rates := range cfg.ECL.Rates {
+		c.ECL.Rates[productCode] = make(map[int8]ECLRate, len(rates))
+		for _, rate := range rates {
+			key := fmt.Sprintf("ecl.rates.%s.stage_%d", productCode, rate.Stage)
+			c.ECL.Rates[productCode][rate.Stage] = ECLRate{
+				PD:  mustParseDecimal(key+".pd", rate.PD),
+				LGD: mustParseDecimal(key+".lgd", rate.LGD),
+			}
+		}
+	}
+}
+
+func (c *Config) initEventPublisherConfig(cfg *configYAML) {
+	c.EventPublisher = &eventPublisher{
+		Type:                cfg.EventPublisher.Type,
+		MaxAttempts:         cfg.EventPublisher.MaxAttempts,
+		FilePath:            cfg.EventPublisher.File.Path,
+		KafkaBrokers:        cfg.EventPublisher.Kafka.Brokers,
+		KafkaTopic:          cfg.EventPublisher.Kafka.Topic,
+		NATSURL:             cfg.EventPublisher.NATS.URL,
+		NATSSubjectPrefix:   cfg.EventPublisher.NATS.SubjectPrefix,
+		NATSStream:          cfg.EventPublisher.NATS.Stream,
+		NATSRetention:       cfg.EventPublisher.NATS.Retention,
+		NATSMaxAge:          time.Duration(cfg.EventPublisher.NATS.MaxAgeHours) * time.Hour,
+		NATSDuplicateWindow: time.Duration(cfg.EventPublisher.NATS.DuplicateWindowSeconds) * time.Second,
+	}
+	if c.EventPublisher.Type == "" {
+		c.EventPublisher.Type = "log"
+	}
+	if c.EventPublisher.MaxAttempts <= 0 {
+		c.EventPublisher.MaxAttempts = 10
+	}
+	if c.EventPublisher.FilePath == "" {
+		c.EventPublisher.FilePath = "./events/events.jsonl"
+	}
+	if c.EventPublisher.KafkaTopic == "" {
+		c.EventPublisher.KafkaTopic = "loan-events"
+	}
+	if c.EventPublisher.NATSSubjectPrefix == "" {
+		c.EventPublisher.NATSSubjectPrefix = "loan"
+	}
+	if c.EventPublisher.NATSStream == "" {
+		c.EventPublisher.NATSStream = "LOAN_EVENTS"
+	}
+	if c.EventPublisher.NATSRetention == "" {
+		c.EventPublisher.NATSRetention = "limits"
+	}
+	if c.EventPublisher.NATSDuplicateWindow <= 0 {
+		c.EventPublisher.NATSDuplicateWindow = 10 * time.Minute
+	}
+}
+
+func (c *Config) initWebhookConfig(cfg *configYAML) {
+	c.Webhook = &webhook{
+		Timeout:        10 * time.Second,
+		MaxAttempts:    8,
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     6 * time.Hour,
+	}
+	if cfg.Webhook.TimeoutSeconds > 0 {
+		c.Webhook.Timeout = time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second
+	}
+	if cfg.Webhook.MaxAttempts > 0 {
+		c.Webhook.MaxAttempts = cfg.Webhook.MaxAttempts
+	}
+	if cfg.Webhook.InitialBackoffSeconds > 0 {
+		c.Webhook.InitialBackoff = time.Duration(cfg.Webhook.InitialBackoffSeconds) * time.Second
+	}
+	if cfg.Webhook.MaxBackoffSeconds > 0 {
+		c.Webhook.MaxBackoff = time.Duration(cfg.Webhook.MaxBackoffSeconds) * time.Second
+	}
+}
+
+func (c *Config) initNotificationConfig(cfg *configYAML) {
+	c.Notification = &notification{
+		Location:      time.Local,
+		DefaultLocale: cfg.Notification.DefaultLocale,
+		Channels:      make(map[string]NotificationChannel),
+	}
+	if c.Notification.DefaultLocale == "" {
+		c.Notification.DefaultLocale = "id"
+	}
+
+	if cfg.Notification.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Notification.Timezone)
+		if err != nil {
+			panic(fmt.Sprintf("failed parsing config notification.timezone, err: %v", err))
+		}
+		c.Notification.Location = location
+	}
+
+	for name, channel := range map[string]notificationChannelYAML{
+		"email": cfg.Notification.Email,
+		"sms":   cfg.Notification.SMS,
+		"push":  cfg.Notification.Push,
+	} {
+		if channel.Sender == "" {
+			continue
+		}
+		c.Notification.Channels[name] = NotificationChannel{
+			Sender:       channel.Sender,
+			SMTPHost:     channel.SMTP.Host,
+			SMTPPort:     channel.SMTP.Port,
+			SMTPUsername: channel.SMTP.Username,
+			SMTPPassword: channel.SMTP.Password,
+			SMTPFrom:     channel.SMTP.From,
+			HTTPURL:      channel.HTTP.URL,
+			HTTPAPIKey:   channel.HTTP.APIKey,
+		}
+	}
+}
+
+func (c *Config) initPaymentProviderConfig(cfg *configYAML) {
+	c.PaymentProvider = &paymentProvider{
+		Name:              cfg.PaymentProvider.Name,
+		BaseURL:           cfg.PaymentProvider.BaseURL,
+		APIKey:            cfg.PaymentProvider.APIKey,
+		BankCode:          cfg.PaymentProvider.BankCode,
+		CallbackSecret:    cfg.PaymentProvider.CallbackSecret,
+		CallbackTolerance: 5 * time.Minute,
+		Timeout:           10 * time.Second,
+	}
+	if cfg.PaymentProvider.CallbackToleranceSeconds > 0 {
+		c.PaymentProvider.CallbackTolerance = time.Duration(cfg.PaymentProvider.CallbackToleranceSeconds) * time.Second
+	}
+	if cfg.PaymentProvider.TimeoutSeconds > 0 {
+		c.PaymentProvider.Timeout = time.Duration(cfg.PaymentProvider.TimeoutSeconds) * time.Second
+	}
+}
+
+func mustParseDecimal(key, value string) decimal.Decimal {
+	if value == "" {
+		return decimal.Zero
+	}
+
+	number, err := decimal.NewFromString(value)
+	if err != nil {
+		panic(fmt.Sprintf("failed parsing config %s, err: %v", key, err))
+	}
+	return number
+}
@@ -6,4 +6,8 @@ var (
 	ErrRecordNotFound = errors.New("record not found")
 
 	ErrInvalidValue = errors.New("invalid value")
+
+	ErrRegulatoryCapExceeded = errors.New("regulatory cap exceeded")
+
+	ErrInvalidSignature = errors.New("invalid signature")
 )
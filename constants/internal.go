@@ -9,10 +9,121 @@ const (
 	TenureUnit_Week
 	TenureUnit_Month
 	TenureUnit_Year
+	TenureUnit_BiWeek
+	TenureUnit_SemiMonth // due on the 15th and the last day of the month
+	TenureUnit_Quarter
 )
 
 func (c TenureUnit) IsValid() bool {
-	for i := TenureUnit_Day; i <= TenureUnit_Year; i++ {
+	for i := TenureUnit_Day; i <= TenureUnit_Quarter; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (c TenureUnit) PeriodsPerYear() int64 {
+	switch c {
+	case TenureUnit_Day:
+		return 365
+	case TenureUnit_Week:
+		return 52
+	case TenureUnit_BiWeek:
+		return 26
+	case TenureUnit_SemiMonth:
+		return 24
+	case TenureUnit_Month:
+		return 12
+	case TenureUnit_Quarter:
+		return 4
+	case TenureUnit_Year:
+		return 1
+	}
+	return 0
+}
+
+type GracePeriodType int8
+
+const (
+	GracePeriodType_InterestOnly   GracePeriodType = iota + 1
+	GracePeriodType_PaymentHoliday                 // interest is capitalised
+)
+
+func (c GracePeriodType) IsValid() bool {
+	for i := GracePeriodType_InterestOnly; i <= GracePeriodType_PaymentHoliday; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+type AmortizationMethod int8
+
+const (
+	AmortizationMethod_Flat    AmortizationMethod = iota + 1
+	AmortizationMethod_Annuity                    // equal installments, interest on the outstanding balance
+)
+
+func (c AmortizationMethod) IsValid() bool {
+	for i := AmortizationMethod_Flat; i <= AmortizationMethod_Annuity; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+type LoanProductStatus int8
+
+const (
+	LoanProductStatus_Active LoanProductStatus = iota + 1
+	LoanProductStatus_Inactive
+)
+
+type FeeType int8
+
+const (
+	FeeType_Provision FeeType = iota + 1
+	FeeType_Admin
+)
+
+func (c FeeType) IsValid() bool {
+	for i := FeeType_Provision; i <= FeeType_Admin; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+type FeeCalculationType int8
+
+const (
+	FeeCalculationType_Flat       FeeCalculationType = iota + 1
+	FeeCalculationType_Percentage                    // of the loan amount, inflated by 10^2
+)
+
+func (c FeeCalculationType) IsValid() bool {
+	for i := FeeCalculationType_Flat; i <= FeeCalculationType_Percentage; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+type FeeChargeType int8
+
+const (
+	FeeChargeType_DeductedFromDisbursement FeeChargeType = iota + 1
+	FeeChargeType_AddedToPrincipal
+	FeeChargeType_BilledWithFirstInstallment
+)
+
+func (c FeeChargeType) IsValid() bool {
+	for i := FeeChargeType_DeductedFromDisbursement; i <= FeeChargeType_BilledWithFirstInstallment; i++ {
 		if i == c {
 			return true
 		}
@@ -23,9 +134,43 @@ func (c TenureUnit) IsValid() bool {
 type LoanStatus int8
 
 const (
-	LoanStatus_InRepayment LoanStatus = iota + 1
+	LoanStatus_InRepayment LoanStatus = iota + 1 // disbursed
 	LoanStatus_Defaulted
 	LoanStatus_Completed
+	LoanStatus_Submitted
+	LoanStatus_Approved
+	LoanStatus_Rejected
+	LoanStatus_Cancelled
+	LoanStatus_WrittenOff
+)
+
+// loanStatusTransitions lists the statuses a loan can move into from each status,
+// statuses without an entry are final
+var loanStatusTransitions = map[LoanStatus][]LoanStatus{
+	LoanStatus_Submitted:   {LoanStatus_Approved, LoanStatus_Rejected, LoanStatus_Cancelled},
+	LoanStatus_Approved:    {LoanStatus_InRepayment, LoanStatus_Cancelled},
+	LoanStatus_InRepayment: {LoanStatus_Defaulted, LoanStatus_Completed, LoanStatus_WrittenOff},
+	LoanStatus_Defaulted:   {LoanStatus_InRepayment, LoanStatus_Completed, LoanStatus_WrittenOff}, // back to in repayment on cure
+}
+
+func (s LoanStatus) CanTransitionTo(status LoanStatus) bool {
+	for _, nextStatus := range loanStatusTransitions[s] {
+		if nextStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDisbursed tells whether the repayment schedule of the loan has been generated
+func (s LoanStatus) IsDisbursed() bool {
+	return s == LoanStatus_InRepayment || s == LoanStatus_Defaulted || s == LoanStatus_Completed || s == LoanStatus_WrittenOff
+}
+
+// actors recorded in the loan request histories besides the operator's identifier
+const (
+	LoanActor_Borrower = "borrower"
+	LoanActor_System   = "system"
 )
 
 type PaymentStatus int8
@@ -33,6 +178,365 @@ type PaymentStatus int8
 const (
 	PaymentStatus_Pending PaymentStatus = iota + 1
 	PaymentStatus_Completed
+	PaymentStatus_Waived        // forgiven, nothing is collected anymore
+	PaymentStatus_Cancelled     // replaced by another billing, e.g. by restructuring
+	PaymentStatus_WrittenOff    // the loan is written off, the remaining amount is collected as recovery
+	PaymentStatus_PartiallyPaid // still open, part of the total amount is paid
+)
+
+// IsOpen tells whether the billing still has to be paid
+func (s PaymentStatus) IsOpen() bool {
+	return s == PaymentStatus_Pending || s == PaymentStatus_PartiallyPaid
+}
+
+type LedgerAccount int8
+
+const (
+	LedgerAccount_Cash LedgerAccount = iota + 1
+	LedgerAccount_LoanReceivable
+	LedgerAccount_InterestReceivable
+	LedgerAccount_UnearnedInterest // scheduled interest that is not earned yet
+	LedgerAccount_InterestIncome
+	LedgerAccount_FeeReceivable
+	LedgerAccount_FeeIncome
+	LedgerAccount_PenaltyReceivable
+	LedgerAccount_PenaltyIncome
+	LedgerAccount_WaiverExpense
+	LedgerAccount_WriteOffExpense
+	LedgerAccount_RecoveryIncome
+	LedgerAccount_Suspense // money that can not be allocated yet
+)
+
+func (c LedgerAccount) IsValid() bool {
+	for i := LedgerAccount_Cash; i <= LedgerAccount_Suspense; i++ {
+		if i == c {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDebitNormal tells whether the account's balance increases with debits (assets and expenses)
+func (c LedgerAccount) IsDebitNormal() bool {
+	switch c {
+	case LedgerAccount_Cash, LedgerAccount_LoanReceivable, LedgerAccount_InterestReceivable,
+		LedgerAccount_FeeReceivable, LedgerAccount_PenaltyReceivable,
+		LedgerAccount_WaiverExpense, LedgerAccount_WriteOffExpense:
+		return true
+	}
+	return false
+}
+
+// Name is the account's key in the chart of accounts mapping
+func (c LedgerAccount) Name() string {
+	return ledgerAccountNames[c]
+}
+
+var ledgerAccountNames = map[LedgerAccount]string{
+	LedgerAccount_Cash:               "cash",
+	LedgerAccount_LoanReceivable:     "loan_receivable",
+	LedgerAccount_InterestReceivable: "interest_receivable",
+	LedgerAccount_UnearnedInterest:   "unearned_interest",
+	LedgerAccount_InterestIncome:     "interest_income",
+	LedgerAccount_FeeReceivable:      "fee_receivable",
+	LedgerAccount_FeeIncome:          "fee_income",
+	LedgerAccount_PenaltyReceivable:  "penalty_receivable",
+	LedgerAccount_PenaltyIncome:      "penalty_income",
+	LedgerAccount_WaiverExpense:      "waiver_expense",
+	LedgerAccount_WriteOffExpense:    "write_off_expense",
+	LedgerAccount_RecoveryIncome:     "recovery_income",
+	LedgerAccount_Suspense:           "suspense",
+}
+
+type JournalEntryType int8
+
+const (
+	JournalEntryType_Disbursement JournalEntryType = iota + 1
+	JournalEntryType_Payment
+	JournalEntryType_Penalty
+	JournalEntryType_Waiver
+	JournalEntryType_Restructure
+	JournalEntryType_PaymentHoliday
+	JournalEntryType_WriteOff
+	JournalEntryType_Recovery
+	JournalEntryType_InterestAccrual
+	JournalEntryType_Suspense // cash held in or released from the suspense account
+)
+
+func (t JournalEntryType) Name() string {
+	return journalEntryTypeNames[t]
+}
+
+var journalEntryTypeNames = map[JournalEntryType]string{
+	JournalEntryType_Disbursement:    "disbursement",
+	JournalEntryType_Payment:         "payment",
+	JournalEntryType_Penalty:         "penalty",
+	JournalEntryType_Waiver:          "waiver",
+	JournalEntryType_Restructure:     "restructure",
+	JournalEntryType_PaymentHoliday:  "payment_holiday",
+	JournalEntryType_WriteOff:        "write_off",
+	JournalEntryType_Recovery:        "recovery",
+	JournalEntryType_InterestAccrual: "interest_accrual",
+	JournalEntryType_Suspense:        "suspense",
+}
+
+type SuspenseItemStatus int8
+
+const (
+	SuspenseItemStatus_Open     SuspenseItemStatus = iota + 1 // the cash is held in the suspense account
+	SuspenseItemStatus_Applied                                // posted as a loan payment
+	SuspenseItemStatus_Refunded                               // returned to the payer
+)
+
+// the sources of the suspense items
+const (
+	SuspenseSource_VirtualAccount = "virtual_account" // referenced by the provider's callback id
+)
+
+func (s SuspenseItemStatus) IsValid() bool {
+	for i := SuspenseItemStatus_Open; i <= SuspenseItemStatus_Refunded; i++ {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
+
+type InterestAccrualSource int8
+
+const (
+	InterestAccrualSource_Daily      InterestAccrualSource = iota + 1
+	InterestAccrualSource_Payment                          // paid interest of a non-accrual loan, recognised on cash basis
+	InterestAccrualSource_Settlement                       // the billing or loan is closed, only its paid interest stays recognised
+)
+
+// ECLStage follows PSAK 71 / IFRS 9
+type ECLStage int8
+
+const (
+	ECLStage_Performing      ECLStage = iota + 1 // stage 1, 12-month expected credit loss
+	ECLStage_UnderPerforming                     // stage 2, significant increase in credit risk, lifetime expected credit loss
+	ECLStage_CreditImpaired                      // stage 3, lifetime expected credit loss
+)
+
+func (s ECLStage) IsValid() bool {
+	for i := ECLStage_Performing; i <= ECLStage_CreditImpaired; i++ {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
+
+type EventType string
+
+const (
+	EventType_LoanCreated       EventType = "loan.created"
+	EventType_LoanStatusChanged EventType = "loan.status_changed"
+	EventType_LoanCompleted     EventType = "loan.completed"
+	EventType_PaymentReceived   EventType = "payment.received"
+	EventType_BillingDue        EventType = "billing.due"     // the billing's due date has come
+	EventType_BillingOverdue    EventType = "billing.overdue" // the billing's due time has passed while it is still open
+)
+
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventType_LoanCreated, EventType_LoanStatusChanged, EventType_LoanCompleted,
+		EventType_PaymentReceived, EventType_BillingDue, EventType_BillingOverdue:
+		return true
+	}
+	return false
+}
+
+type OutboxEventStatus int8
+
+const (
+	OutboxEventStatus_Pending OutboxEventStatus = iota + 1
+	OutboxEventStatus_Published
+	OutboxEventStatus_DeadLettered // gave up after the max attempts, its loan's later events are published without it
+)
+
+type WebhookSubscriptionStatus int8
+
+const (
+	WebhookSubscriptionStatus_Active WebhookSubscriptionStatus = iota + 1
+	WebhookSubscriptionStatus_Inactive
+)
+
+type WebhookDeliveryStatus int8
+
+const (
+	WebhookDeliveryStatus_Pending      WebhookDeliveryStatus = iota + 1
+	WebhookDeliveryStatus_Delivered                          // acknowledged with a 2xx response
+	WebhookDeliveryStatus_DeadLettered                       // gave up after the max attempts, only replayed manually
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	for i := WebhookDeliveryStatus_Pending; i <= WebhookDeliveryStatus_DeadLettered; i++ {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
+
+type NotificationType string
+
+const (
+	NotificationType_ReminderH3  NotificationType = "reminder_h-3" // 3 days before the due date
+	NotificationType_ReminderH1  NotificationType = "reminder_h-1"
+	NotificationType_ReminderDue NotificationType = "reminder_due_day"
+	NotificationType_OverdueD1   NotificationType = "overdue_d+1" // 1 day after the due date
+	NotificationType_OverdueD7   NotificationType = "overdue_d+7"
+	NotificationType_OverdueD30  NotificationType = "overdue_d+30"
+)
+
+// NotificationTypes are sent in this order, each on the days relative to the billing's due date
+var NotificationTypes = []NotificationType{
+	NotificationType_ReminderH3,
+	NotificationType_ReminderH1,
+	NotificationType_ReminderDue,
+	NotificationType_OverdueD1,
+	NotificationType_OverdueD7,
+	NotificationType_OverdueD30,
+}
+
+var notificationTypeDueDayOffsets = map[NotificationType]int{
+	NotificationType_ReminderH3:  -3,
+	NotificationType_ReminderH1:  -1,
+	NotificationType_ReminderDue: 0,
+	NotificationType_OverdueD1:   1,
+	NotificationType_OverdueD7:   7,
+	NotificationType_OverdueD30:  30,
+}
+
+// DueDayOffset is the days from the billing's due date to the day the notification is sent
+func (t NotificationType) DueDayOffset() int {
+	return notificationTypeDueDayOffsets[t]
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannel_Email NotificationChannel = "email"
+	NotificationChannel_SMS   NotificationChannel = "sms"
+	NotificationChannel_Push  NotificationChannel = "push"
+)
+
+var NotificationChannels = []NotificationChannel{
+	NotificationChannel_Email,
+	NotificationChannel_SMS,
+	NotificationChannel_Push,
+}
+
+type NotificationStatus int8
+
+const (
+	NotificationStatus_Pending NotificationStatus = iota + 1 // claimed by a sender, resent once stale, i.e. its sender crashed
+	NotificationStatus_Sent
+	NotificationStatus_Failed // resent on the next run while the notification is still due
+)
+
+type Locale string
+
+const (
+	Locale_ID Locale = "id" // Bahasa Indonesia
+	Locale_EN Locale = "en"
+)
+
+func (l Locale) IsValid() bool {
+	return l == Locale_ID || l == Locale_EN
+}
+
+type CollectionCaseStatus int8
+
+const (
+	CollectionCaseStatus_Open CollectionCaseStatus = iota + 1
+	CollectionCaseStatus_Closed
+)
+
+type CollectionCaseCloseReason int8
+
+const (
+	CollectionCaseCloseReason_Cured      CollectionCaseCloseReason = iota + 1 // no billing is overdue anymore
+	CollectionCaseCloseReason_Completed                                       // the loan is fully paid or waived
+	CollectionCaseCloseReason_WrittenOff                                      // the loan is written off, recoveries are handled outside of collections
+)
+
+// CollectionBucket groups the cases by the loan's days past due
+type CollectionBucket int8
+
+const (
+	CollectionBucket_DPD1To30 CollectionBucket = iota + 1
+	CollectionBucket_DPD31To60
+	CollectionBucket_DPD61To90
+	CollectionBucket_DPD91Plus
+)
+
+type CollectionAssigneeType int8
+
+const (
+	CollectionAssigneeType_Agent  CollectionAssigneeType = iota + 1 // an in-house collector
+	CollectionAssigneeType_Agency                                   // a third-party collection agency
+)
+
+func (t CollectionAssigneeType) IsValid() bool {
+	for i := CollectionAssigneeType_Agent; i <= CollectionAssigneeType_Agency; i++ {
+		if i == t {
+			return true
+		}
+	}
+	return false
+}
+
+type CollectionActivityType int8
+
+const (
+	CollectionActivityType_Call CollectionActivityType = iota + 1
+	CollectionActivityType_Visit
+	CollectionActivityType_Message
+	CollectionActivityType_Note
+	CollectionActivityType_Opened // recorded by the system from here on
+	CollectionActivityType_BucketChanged
+	CollectionActivityType_Assigned
+	CollectionActivityType_PromiseToPay
+	CollectionActivityType_Closed
+)
+
+// IsLoggable tells whether the activity can be logged by a collector
+func (t CollectionActivityType) IsLoggable() bool {
+	return t >= CollectionActivityType_Call && t <= CollectionActivityType_Note
+}
+
+type PromiseToPayStatus int8
+
+const (
+	PromiseToPayStatus_Pending PromiseToPayStatus = iota + 1
+	PromiseToPayStatus_Kept                       // the promised amount is paid by the promised date
+	PromiseToPayStatus_Broken
+)
+
+type VirtualAccountStatus int8
+
+const (
+	VirtualAccountStatus_Active   VirtualAccountStatus = iota + 1
+	VirtualAccountStatus_Inactive                      // a paid billing's account, or replaced after the billing amount changed
+)
+
+type VirtualAccountPaymentStatus int8
+
+const (
+	VirtualAccountPaymentStatus_Posted    VirtualAccountPaymentStatus = iota + 1 // converted into a loan payment
+	VirtualAccountPaymentStatus_Suspended                                        // part or all of it can not be posted to the loan and is held in suspense
+	VirtualAccountPaymentStatus_Unmatched                                        // paid to an unknown virtual account, held in suspense
+)
+
+const (
+	AmountDecimalPlaces int32 = 2 // follows the decimal(25, 2) amount columns
+
+	DateLayout = "2006-01-02"
+
+	DefaultDelinquentOverdueBillings = 2 // used when the loan has no product
 )
 
 var (
@@ -7,17 +7,25 @@ import (
 )
 
 type UserModel struct {
-	ID        int64  `db:"id"`
-	Name      string `db:"name"`
-	CreatedAt uint64 `db:"created_at"`
-	UpdatedAt uint64 `db:"updated_at"`
-	DeletedAt uint64 `db:"deleted_at"`
+	ID          int64  `db:"id"`
+	Name        string `db:"name"`
+	Email       string `db:"email"`        // empty when the user has no email
+	PhoneNumber string `db:"phone_number"` // E.164, empty when the user has no phone number
+	PushToken   string `db:"push_token"`   // the device token of the app, empty when the user has no app installed
+	Locale      string `db:"locale"`       // id or en, the configured default locale when empty
+	CreatedAt   uint64 `db:"created_at"`
+	UpdatedAt   uint64 `db:"updated_at"`
+	DeletedAt   uint64 `db:"deleted_at"`
 }
 
 func (m *UserModel) GetAll() []interface{} {
 	return []interface{}{
 		&m.ID,
 		&m.Name,
+		&m.Email,
+		&m.PhoneNumber,
+		&m.PushToken,
+		&m.Locale,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 		&m.DeletedAt,
@@ -29,33 +37,55 @@ func (m *UserModel) GetTableName() string {
 }
 
 type LoanRequestModel struct {
-	ID                  int64                `db:"id"`
-	UserID              int64                `db:"user_id"`
-	LoanAmount          decimal.Decimal      `db:"loan_amount"`
-	PrincipalPaidAmount decimal.Decimal      `db:"principal_paid_amount"`
-	InterestPaidAmount  decimal.Decimal      `db:"interest_paid_amount"`
-	DisbursementTime    int64                `db:"disbursement_time"`
-	TenureValue         int                  `db:"tenure_value"`
-	TenureUnit          constants.TenureUnit `db:"tenure_unit"`
-	Status              constants.LoanStatus `db:"status"`
-	AnnualInterestRate  decimal.Decimal      `db:"annual_interest_rate"`
-	CreatedAt           int64                `db:"created_at"`
-	UpdatedAt           int64                `db:"updated_at"`
-	DeletedAt           int64                `db:"deleted_at"`
+	ID                  int64                        `db:"id"`
+	UserID              int64                        `db:"user_id"`
+	ProductID           int64                        `db:"product_id"`       // 0 when the loan has no product
+	PartnerID           int64                        `db:"partner_id"`       // 0 when the loan is not originated by a partner
+	LoanAmount          decimal.Decimal              `db:"loan_amount"`      // including fees added to principal
+	DisbursedAmount     decimal.Decimal              `db:"disbursed_amount"` // excluding fees deducted from disbursement
+	PrincipalPaidAmount decimal.Decimal              `db:"principal_paid_amount"`
+	InterestPaidAmount  decimal.Decimal              `db:"interest_paid_amount"`
+	PenaltyPaidAmount   decimal.Decimal              `db:"penalty_paid_amount"`
+	FeePaidAmount       decimal.Decimal              `db:"fee_paid_amount"`
+	DisbursementTime    int64                        `db:"disbursement_time"`
+	TenureValue         int                          `db:"tenure_value"`
+	TenureUnit          constants.TenureUnit         `db:"tenure_unit"`
+	Status              constants.LoanStatus         `db:"status"`
+	AnnualInterestRate  decimal.Decimal              `db:"annual_interest_rate"`
+	AmortizationMethod  constants.AmortizationMethod `db:"amortization_method"`
+	GracePeriodType     constants.GracePeriodType    `db:"grace_period_type"`
+	GracePeriodValue    int                          `db:"grace_period_value"`
+	BalloonAmount       decimal.Decimal              `db:"balloon_amount"`
+	EffectiveAPR        decimal.Decimal              `db:"effective_apr"` // inflated by 10^2
+	TotalCostOfCredit   decimal.Decimal              `db:"total_cost_of_credit"`
+	CreatedAt           int64                        `db:"created_at"`
+	UpdatedAt           int64                        `db:"updated_at"`
+	DeletedAt           int64                        `db:"deleted_at"`
 }
 
 func (m *LoanRequestModel) GetAll() []interface{} {
 	return []interface{}{
 		&m.ID,
 		&m.UserID,
+		&m.ProductID,
+		&m.PartnerID,
 		&m.LoanAmount,
+		&m.DisbursedAmount,
 		&m.PrincipalPaidAmount,
 		&m.InterestPaidAmount,
+		&m.PenaltyPaidAmount,
+		&m.FeePaidAmount,
 		&m.DisbursementTime,
 		&m.TenureValue,
 		&m.TenureUnit,
 		&m.Status,
 		&m.AnnualInterestRate,
+		&m.AmortizationMethod,
+		&m.GracePeriodType,
+		&m.GracePeriodValue,
+		&m.BalloonAmount,
+		&m.EffectiveAPR,
+		&m.TotalCostOfCredit,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 		&m.DeletedAt,
@@ -67,20 +97,29 @@ func (m *LoanRequestModel) GetTableName() string {
 }
 
 type BillingModel struct {
-	ID                 int64                   `db:"id"`
-	BillingID          string                  `db:"billing_id"`
-	LoanID             int64                   `db:"loan_id"`
-	PaymentID          int64                   `db:"payment_id"`
-	RecurringIndex     int                     `db:"recurring_index"`
-	PrincipalAmount    decimal.Decimal         `db:"principal_amount"`
-	InterestAmount     decimal.Decimal         `db:"interest_amount"`
-	TotalAmount        decimal.Decimal         `db:"total_amount"`
-	DueTime            int64                   `db:"due_time"`
-	PaymentCompletedAt int64                   `db:"payment_completed_at"`
-	Status             constants.PaymentStatus `db:"status"`
-	CreatedAt          int64                   `db:"created_at"`
-	UpdatedAt          int64                   `db:"updated_at"`
-	DeletedAt          int64                   `db:"deleted_at"`
+	ID                       int64                   `db:"id"`
+	BillingID                string                  `db:"billing_id"`
+	LoanID                   int64                   `db:"loan_id"`
+	PaymentID                int64                   `db:"payment_id"`
+	RecurringIndex           int                     `db:"recurring_index"`
+	RestructureID            int64                   `db:"restructure_id"` // 0 for the original schedule
+	PrincipalAmount          decimal.Decimal         `db:"principal_amount"`
+	InterestAmount           decimal.Decimal         `db:"interest_amount"`
+	PenaltyAmount            decimal.Decimal         `db:"penalty_amount"`
+	FeeAmount                decimal.Decimal         `db:"fee_amount"`
+	TotalAmount              decimal.Decimal         `db:"total_amount"`
+	PrincipalPaidAmount      decimal.Decimal         `db:"principal_paid_amount"`
+	InterestPaidAmount       decimal.Decimal         `db:"interest_paid_amount"`
+	PenaltyPaidAmount        decimal.Decimal         `db:"penalty_paid_amount"`
+	FeePaidAmount            decimal.Decimal         `db:"fee_paid_amount"`
+	CapitalisedPenaltyAmount decimal.Decimal         `db:"capitalised_penalty_amount"` // restructured arrears, late penalties accrue on top of it
+	InterestAccruedAmount    decimal.Decimal         `db:"interest_accrued_amount"`    // interest recognised as income so far
+	DueTime                  int64                   `db:"due_time"`
+	PaymentCompletedAt       int64                   `db:"payment_completed_at"`
+	Status                   constants.PaymentStatus `db:"status"`
+	CreatedAt                int64                   `db:"created_at"`
+	UpdatedAt                int64                   `db:"updated_at"`
+	DeletedAt                int64                   `db:"deleted_at"`
 }
 
 func (m *BillingModel) GetAll() []interface{} {
@@ -90,9 +129,18 @@ func (m *BillingModel) GetAll() []interface{} {
 		&m.LoanID,
 		&m.PaymentID,
 		&m.RecurringIndex,
+		&m.RestructureID,
 		&m.PrincipalAmount,
 		&m.InterestAmount,
+		&m.PenaltyAmount,
+		&m.FeeAmount,
 		&m.TotalAmount,
+		&m.PrincipalPaidAmount,
+		&m.InterestPaidAmount,
+		&m.PenaltyPaidAmount,
+		&m.FeePaidAmount,
+		&m.CapitalisedPenaltyAmount,
+		&m.InterestAccruedAmount,
 		&m.DueTime,
 		&m.PaymentCompletedAt,
 		&m.Status,
@@ -106,9 +154,19 @@ func (m *BillingModel) GetTableName() string {
 	return "billings_tab"
 }
 
+// GetPaidAmount sums up the paid amount of every billed component
+func (m *BillingModel) GetPaidAmount() decimal.Decimal {
+	return m.PrincipalPaidAmount.Add(m.InterestPaidAmount).Add(m.PenaltyPaidAmount).Add(m.FeePaidAmount)
+}
+
+func (m *BillingModel) GetUnpaidAmount() decimal.Decimal {
+	return m.TotalAmount.Sub(m.GetPaidAmount())
+}
+
 type PaymentModel struct {
 	ID        int64           `db:"id"`
 	UserID    int64           `db:"user_id"`
+	LoanID    int64           `db:"loan_id"`
 	Amount    decimal.Decimal `db:"amount"`
 	CreatedAt int64           `db:"created_at"`
 	UpdatedAt int64           `db:"updated_at"`
@@ -119,6 +177,7 @@ func (m *PaymentModel) GetAll() []interface{} {
 	return []interface{}{
 		&m.ID,
 		&m.UserID,
+		&m.LoanID,
 		&m.Amount,
 		&m.CreatedAt,
 		&m.UpdatedAt,
@@ -136,6 +195,8 @@ type LoanRequestHistory struct {
 	PrincipalPaidAmount decimal.Decimal      `db:"principal_paid_amount"`
 	InterestPaidAmount  decimal.Decimal      `db:"interest_paid_amount"`
 	Status              constants.LoanStatus `db:"status"`
+	Actor               string               `db:"actor"`
+	Reason              string               `db:"reason"`
 	CreatedAt           int64                `db:"created_at"`
 }
 
@@ -146,6 +207,8 @@ func (m *LoanRequestHistory) GetAll() []interface{} {
 		&m.PrincipalPaidAmount,
 		&m.InterestPaidAmount,
 		&m.Status,
+		&m.Actor,
+		&m.Reason,
 		&m.CreatedAt,
 	}
 }
@@ -157,6 +220,7 @@ func (m *LoanRequestHistory) GetTableName() string {
 type BillingHistoryModel struct {
 	ID                 int64                   `db:"id"`
 	BillingID          string                  `db:"billing_id"`
+	DueTime            int64                   `db:"due_time"`
 	PaymentCompletedAt int64                   `db:"payment_completed_at"`
 	Status             constants.PaymentStatus `db:"status"`
 	CreatedAt          int64                   `db:"created_at"`
@@ -166,6 +230,7 @@ func (m *BillingHistoryModel) GetAll() []interface{} {
 	return []interface{}{
 		&m.ID,
 		&m.BillingID,
+		&m.DueTime,
 		&m.PaymentCompletedAt,
 		&m.Status,
 		&m.CreatedAt,
@@ -175,3 +240,883 @@ func (m *BillingHistoryModel) GetAll() []interface{} {
 func (m *BillingHistoryModel) GetTableName() string {
 	return "billing_histories_tab"
 }
+
+type LoanProductModel struct {
+	ID                        int64                        `db:"id"`
+	ProductCode               string                       `db:"product_code"`
+	Name                      string                       `db:"name"`
+	MinLoanAmount             decimal.Decimal              `db:"min_loan_amount"`
+	MaxLoanAmount             decimal.Decimal              `db:"max_loan_amount"`
+	AllowedTenureUnits        string                       `db:"allowed_tenure_units"` // comma separated
+	MinTenureValue            int                          `db:"min_tenure_value"`
+	MaxTenureValue            int                          `db:"max_tenure_value"`
+	AnnualInterestRate        decimal.Decimal              `db:"annual_interest_rate"` // inflated by 10^2, used when no rate matches the tenure
+	AmortizationMethod        constants.AmortizationMethod `db:"amortization_method"`
+	LatePenaltyRate           decimal.Decimal              `db:"late_penalty_rate"` // daily, inflated by 10^2, of the overdue amount
+	LatePenaltyGraceDays      int                          `db:"late_penalty_grace_days"`
+	DelinquentOverdueBillings int                          `db:"delinquent_overdue_billings"`
+	Status                    constants.LoanProductStatus  `db:"status"`
+	CreatedAt                 int64                        `db:"created_at"`
+	UpdatedAt                 int64                        `db:"updated_at"`
+	DeletedAt                 int64                        `db:"deleted_at"`
+}
+
+func (m *LoanProductModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.ProductCode,
+		&m.Name,
+		&m.MinLoanAmount,
+		&m.MaxLoanAmount,
+		&m.AllowedTenureUnits,
+		&m.MinTenureValue,
+		&m.MaxTenureValue,
+		&m.AnnualInterestRate,
+		&m.AmortizationMethod,
+		&m.LatePenaltyRate,
+		&m.LatePenaltyGraceDays,
+		&m.DelinquentOverdueBillings,
+		&m.Status,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.DeletedAt,
+	}
+}
+
+func (m *LoanProductModel) GetTableName() string {
+	return "loan_products_tab"
+}
+
+type LoanProductRateModel struct {
+	ID                 int64                `db:"id"`
+	ProductID          int64                `db:"product_id"`
+	TenureUnit         constants.TenureUnit `db:"tenure_unit"`
+	MinTenureValue     int                  `db:"min_tenure_value"`
+	MaxTenureValue     int                  `db:"max_tenure_value"`
+	AnnualInterestRate decimal.Decimal      `db:"annual_interest_rate"` // inflated by 10^2
+	CreatedAt          int64                `db:"created_at"`
+}
+
+func (m *LoanProductRateModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.ProductID,
+		&m.TenureUnit,
+		&m.MinTenureValue,
+		&m.MaxTenureValue,
+		&m.AnnualInterestRate,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanProductRateModel) GetTableName() string {
+	return "loan_product_rates_tab"
+}
+
+type LoanProductFeeModel struct {
+	ID              int64                        `db:"id"`
+	ProductID       int64                        `db:"product_id"`
+	FeeType         constants.FeeType            `db:"fee_type"`
+	CalculationType constants.FeeCalculationType `db:"calculation_type"`
+	Value           decimal.Decimal              `db:"value"`
+	ChargeType      constants.FeeChargeType      `db:"charge_type"`
+	CreatedAt       int64                        `db:"created_at"`
+}
+
+func (m *LoanProductFeeModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.ProductID,
+		&m.FeeType,
+		&m.CalculationType,
+		&m.Value,
+		&m.ChargeType,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanProductFeeModel) GetTableName() string {
+	return "loan_product_fees_tab"
+}
+
+type LoanFeeModel struct {
+	ID              int64                        `db:"id"`
+	LoanID          int64                        `db:"loan_id"`
+	FeeType         constants.FeeType            `db:"fee_type"`
+	CalculationType constants.FeeCalculationType `db:"calculation_type"`
+	Value           decimal.Decimal              `db:"value"`
+	Amount          decimal.Decimal              `db:"amount"`
+	ChargeType      constants.FeeChargeType      `db:"charge_type"`
+	CreatedAt       int64                        `db:"created_at"`
+}
+
+func (m *LoanFeeModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.FeeType,
+		&m.CalculationType,
+		&m.Value,
+		&m.Amount,
+		&m.ChargeType,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanFeeModel) GetTableName() string {
+	return "loan_fees_tab"
+}
+
+type LoanRequestInstallmentModel struct {
+	ID              int64           `db:"id"`
+	LoanID          int64           `db:"loan_id"`
+	RecurringIndex  int             `db:"recurring_index"`
+	DueTime         int64           `db:"due_time"`
+	PrincipalAmount decimal.Decimal `db:"principal_amount"`
+	CreatedAt       int64           `db:"created_at"`
+}
+
+func (m *LoanRequestInstallmentModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.RecurringIndex,
+		&m.DueTime,
+		&m.PrincipalAmount,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanRequestInstallmentModel) GetTableName() string {
+	return "loan_request_installments_tab"
+}
+
+type LoanRestructureModel struct {
+	ID                         int64                        `db:"id"`
+	LoanID                     int64                        `db:"loan_id"`
+	PreviousTenureValue        int                          `db:"previous_tenure_value"`
+	PreviousTenureUnit         constants.TenureUnit         `db:"previous_tenure_unit"`
+	PreviousAnnualInterestRate decimal.Decimal              `db:"previous_annual_interest_rate"`
+	PreviousAmortizationMethod constants.AmortizationMethod `db:"previous_amortization_method"`
+	TenureValue                int                          `db:"tenure_value"`
+	TenureUnit                 constants.TenureUnit         `db:"tenure_unit"`
+	AnnualInterestRate         decimal.Decimal              `db:"annual_interest_rate"`
+	AmortizationMethod         constants.AmortizationMethod `db:"amortization_method"`
+	OutstandingPrincipalAmount decimal.Decimal              `db:"outstanding_principal_amount"`
+	CapitalisedArrearsAmount   decimal.Decimal              `db:"capitalised_arrears_amount"`
+	FirstRecurringIndex        int                          `db:"first_recurring_index"`
+	CancelledBillingCount      int                          `db:"cancelled_billing_count"`
+	Actor                      string                       `db:"actor"`
+	Reason                     string                       `db:"reason"`
+	CreatedAt                  int64                        `db:"created_at"`
+}
+
+func (m *LoanRestructureModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.PreviousTenureValue,
+		&m.PreviousTenureUnit,
+		&m.PreviousAnnualInterestRate,
+		&m.PreviousAmortizationMethod,
+		&m.TenureValue,
+		&m.TenureUnit,
+		&m.AnnualInterestRate,
+		&m.AmortizationMethod,
+		&m.OutstandingPrincipalAmount,
+		&m.CapitalisedArrearsAmount,
+		&m.FirstRecurringIndex,
+		&m.CancelledBillingCount,
+		&m.Actor,
+		&m.Reason,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanRestructureModel) GetTableName() string {
+	return "loan_restructures_tab"
+}
+
+// LoanRestructureBillingModel links a billing cancelled by a restructure to it, a billing is cancelled at most once
+type LoanRestructureBillingModel struct {
+	ID            int64  `db:"id"`
+	RestructureID int64  `db:"restructure_id"`
+	LoanID        int64  `db:"loan_id"`
+	BillingID     string `db:"billing_id"`
+	CreatedAt     int64  `db:"created_at"`
+}
+
+func (m *LoanRestructureBillingModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.RestructureID,
+		&m.LoanID,
+		&m.BillingID,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanRestructureBillingModel) GetTableName() string {
+	return "loan_restructure_billings_tab"
+}
+
+// LoanPaymentHolidayModel marks a payment holiday as applied to a loan, a loan gets the same holiday code at most once
+type LoanPaymentHolidayModel struct {
+	ID                    int64           `db:"id"`
+	LoanID                int64           `db:"loan_id"`
+	HolidayCode           string          `db:"holiday_code"`
+	Periods               int             `db:"periods"`
+	HolidayInterestAmount decimal.Decimal `db:"holiday_interest_amount"`
+	ReversedPenaltyAmount decimal.Decimal `db:"reversed_penalty_amount"`
+	Actor                 string          `db:"actor"`
+	Reason                string          `db:"reason"`
+	CreatedAt             int64           `db:"created_at"`
+}
+
+func (m *LoanPaymentHolidayModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.HolidayCode,
+		&m.Periods,
+		&m.HolidayInterestAmount,
+		&m.ReversedPenaltyAmount,
+		&m.Actor,
+		&m.Reason,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanPaymentHolidayModel) GetTableName() string {
+	return "loan_payment_holidays_tab"
+}
+
+type LoanWriteOffModel struct {
+	ID              int64           `db:"id"`
+	LoanID          int64           `db:"loan_id"`
+	PrincipalAmount decimal.Decimal `db:"principal_amount"`
+	InterestAmount  decimal.Decimal `db:"interest_amount"`
+	PenaltyAmount   decimal.Decimal `db:"penalty_amount"`
+	FeeAmount       decimal.Decimal `db:"fee_amount"`
+	Actor           string          `db:"actor"`
+	Reason          string          `db:"reason"`
+	CreatedAt       int64           `db:"created_at"`
+}
+
+func (m *LoanWriteOffModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.PrincipalAmount,
+		&m.InterestAmount,
+		&m.PenaltyAmount,
+		&m.FeeAmount,
+		&m.Actor,
+		&m.Reason,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanWriteOffModel) GetTableName() string {
+	return "loan_write_offs_tab"
+}
+
+// GetTotalAmount sums up every written off component
+func (m *LoanWriteOffModel) GetTotalAmount() decimal.Decimal {
+	return m.PrincipalAmount.Add(m.InterestAmount).Add(m.PenaltyAmount).Add(m.FeeAmount)
+}
+
+type LoanRecoveryModel struct {
+	ID        int64           `db:"id"`
+	LoanID    int64           `db:"loan_id"`
+	PaymentID int64           `db:"payment_id"`
+	Amount    decimal.Decimal `db:"amount"`
+	CreatedAt int64           `db:"created_at"`
+}
+
+func (m *LoanRecoveryModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.PaymentID,
+		&m.Amount,
+		&m.CreatedAt,
+	}
+}
+
+func (m *LoanRecoveryModel) GetTableName() string {
+	return "loan_recoveries_tab"
+}
+
+type JournalEntryModel struct {
+	ID          int64                      `db:"id"`
+	EntryType   constants.JournalEntryType `db:"entry_type"`
+	LoanID      int64                      `db:"loan_id"`
+	ReferenceID int64                      `db:"reference_id"` // e.g. payment_id, depends on the entry type
+	Description string                     `db:"description"`
+	CreatedAt   int64                      `db:"created_at"`
+}
+
+func (m *JournalEntryModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.EntryType,
+		&m.LoanID,
+		&m.ReferenceID,
+		&m.Description,
+		&m.CreatedAt,
+	}
+}
+
+func (m *JournalEntryModel) GetTableName() string {
+	return "journal_entries_tab"
+}
+
+type JournalLineModel struct {
+	ID             int64                   `db:"id"`
+	JournalEntryID int64                   `db:"journal_entry_id"`
+	LoanID         int64                   `db:"loan_id"`
+	Account        constants.LedgerAccount `db:"account"`
+	DebitAmount    decimal.Decimal         `db:"debit_amount"`
+	CreditAmount   decimal.Decimal         `db:"credit_amount"`
+	CreatedAt      int64                   `db:"created_at"`
+}
+
+func (m *JournalLineModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.JournalEntryID,
+		&m.LoanID,
+		&m.Account,
+		&m.DebitAmount,
+		&m.CreditAmount,
+		&m.CreatedAt,
+	}
+}
+
+func (m *JournalLineModel) GetTableName() string {
+	return "journal_lines_tab"
+}
+
+// SuspenseItemModel is cash received that can not be allocated to a loan yet, held in the suspense account until resolved
+type SuspenseItemModel struct {
+	ID             int64                        `db:"id"`
+	Source         string                       `db:"source"`       // the channel the cash came from, e.g. virtual_account
+	ReferenceID    string                       `db:"reference_id"` // the channel's reference, unique per source
+	UserID         int64                        `db:"user_id"`      // 0 when the payer is unknown
+	LoanID         int64                        `db:"loan_id"`      // 0 when the payer is unknown
+	Amount         decimal.Decimal              `db:"amount"`
+	Reason         string                       `db:"reason"`
+	Status         constants.SuspenseItemStatus `db:"status"`
+	PaymentID      int64                        `db:"payment_id"` // set once applied to a loan
+	ResolvedBy     string                       `db:"resolved_by"`
+	ResolvedReason string                       `db:"resolved_reason"`
+	ResolvedAt     int64                        `db:"resolved_at"`
+	CreatedAt      int64                        `db:"created_at"`
+	UpdatedAt      int64                        `db:"updated_at"`
+}
+
+func (m *SuspenseItemModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.Source,
+		&m.ReferenceID,
+		&m.UserID,
+		&m.LoanID,
+		&m.Amount,
+		&m.Reason,
+		&m.Status,
+		&m.PaymentID,
+		&m.ResolvedBy,
+		&m.ResolvedReason,
+		&m.ResolvedAt,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *SuspenseItemModel) GetTableName() string {
+	return "suspense_items_tab"
+}
+
+type LedgerAccountBalanceModel struct {
+	Account      constants.LedgerAccount `db:"account"`
+	DebitAmount  decimal.Decimal         `db:"debit_amount"`
+	CreditAmount decimal.Decimal         `db:"credit_amount"`
+}
+
+type JournalLineSummaryModel struct {
+	EntryType    constants.JournalEntryType `db:"entry_type"`
+	Account      constants.LedgerAccount    `db:"account"`
+	DebitAmount  decimal.Decimal            `db:"debit_amount"`
+	CreditAmount decimal.Decimal            `db:"credit_amount"`
+}
+
+type InterestAccrualModel struct {
+	ID          int64                           `db:"id"`
+	LoanID      int64                           `db:"loan_id"`
+	BillingID   string                          `db:"billing_id"`
+	AccrualDate int64                           `db:"accrual_date"` // start of the accrued day
+	Amount      decimal.Decimal                 `db:"amount"`       // negative when accrued interest is reversed
+	Source      constants.InterestAccrualSource `db:"source"`
+	CreatedAt   int64                           `db:"created_at"`
+}
+
+func (m *InterestAccrualModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.BillingID,
+		&m.AccrualDate,
+		&m.Amount,
+		&m.Source,
+		&m.CreatedAt,
+	}
+}
+
+func (m *InterestAccrualModel) GetTableName() string {
+	return "interest_accruals_tab"
+}
+
+type OutboxEventModel struct {
+	ID           int64                       `db:"id"`
+	EventID      string                      `db:"event_id"`
+	EventType    constants.EventType         `db:"event_type"`
+	LoanID       int64                       `db:"loan_id"` // the events of a loan are published in order
+	Payload      string                      `db:"payload"` // json
+	Status       constants.OutboxEventStatus `db:"status"`
+	Attempts     int                         `db:"attempts"`
+	LastError    string                      `db:"last_error"`
+	ClaimedBy    string                      `db:"claimed_by"`    // the relay run publishing the event
+	ClaimedUntil int64                       `db:"claimed_until"` // another run may claim the event afterwards
+	CreatedAt    int64                       `db:"created_at"`
+	PublishedAt  int64                       `db:"published_at"`
+}
+
+func (m *OutboxEventModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.EventID,
+		&m.EventType,
+		&m.LoanID,
+		&m.Payload,
+		&m.Status,
+		&m.Attempts,
+		&m.LastError,
+		&m.ClaimedBy,
+		&m.ClaimedUntil,
+		&m.CreatedAt,
+		&m.PublishedAt,
+	}
+}
+
+func (m *OutboxEventModel) GetTableName() string {
+	return "outbox_events_tab"
+}
+
+// BillingEventModel is an open billing along with its loan's user, used to emit the billing events
+type BillingEventModel struct {
+	ID             int64           `db:"id"`
+	BillingID      string          `db:"billing_id"`
+	LoanID         int64           `db:"loan_id"`
+	UserID         int64           `db:"user_id"`
+	RecurringIndex int             `db:"recurring_index"`
+	DueTime        int64           `db:"due_time"`
+	UnpaidAmount   decimal.Decimal `db:"unpaid_amount"`
+}
+
+type WebhookSubscriptionModel struct {
+	ID         int64                               `db:"id"`
+	PartnerID  int64                               `db:"partner_id"`
+	URL        string                              `db:"url"`
+	EventTypes string                              `db:"event_types"` // comma separated
+	Secret     string                              `db:"secret"`      // signs the payloads
+	Status     constants.WebhookSubscriptionStatus `db:"status"`
+	CreatedAt  int64                               `db:"created_at"`
+	UpdatedAt  int64                               `db:"updated_at"`
+	DeletedAt  int64                               `db:"deleted_at"`
+}
+
+func (m *WebhookSubscriptionModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.PartnerID,
+		&m.URL,
+		&m.EventTypes,
+		&m.Secret,
+		&m.Status,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.DeletedAt,
+	}
+}
+
+func (m *WebhookSubscriptionModel) GetTableName() string {
+	return "webhook_subscriptions_tab"
+}
+
+type WebhookDeliveryModel struct {
+	ID               int64                           `db:"id"`
+	SubscriptionID   int64                           `db:"subscription_id"`
+	EventID          string                          `db:"event_id"`
+	EventType        constants.EventType             `db:"event_type"`
+	LoanID           int64                           `db:"loan_id"`
+	Payload          string                          `db:"payload"` // the signed request body
+	Status           constants.WebhookDeliveryStatus `db:"status"`
+	Attempts         int                             `db:"attempts"`
+	NextAttemptAt    int64                           `db:"next_attempt_at"`
+	LastResponseCode int                             `db:"last_response_code"`
+	LastError        string                          `db:"last_error"`
+	DeliveredAt      int64                           `db:"delivered_at"`
+	ReplayCount      int                             `db:"replay_count"`
+	ReplayedAttempts int                             `db:"replayed_attempts"` // the attempts before the last replay, the retries count from it
+	LockedUntil      int64                           `db:"locked_until"`      // claimed by a sender until then
+	CreatedAt        int64                           `db:"created_at"`
+	UpdatedAt        int64                           `db:"updated_at"`
+}
+
+func (m *WebhookDeliveryModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.SubscriptionID,
+		&m.EventID,
+		&m.EventType,
+		&m.LoanID,
+		&m.Payload,
+		&m.Status,
+		&m.Attempts,
+		&m.NextAttemptAt,
+		&m.LastResponseCode,
+		&m.LastError,
+		&m.DeliveredAt,
+		&m.ReplayCount,
+		&m.ReplayedAttempts,
+		&m.LockedUntil,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *WebhookDeliveryModel) GetTableName() string {
+	return "webhook_deliveries_tab"
+}
+
+// WebhookDeliveryReplayModel keeps the delivery's outcome as it was before a manual replay
+type WebhookDeliveryReplayModel struct {
+	ID                   int64                           `db:"id"`
+	DeliveryID           int64                           `db:"delivery_id"`
+	PreviousStatus       constants.WebhookDeliveryStatus `db:"previous_status"`
+	PreviousAttempts     int                             `db:"previous_attempts"`
+	PreviousResponseCode int                             `db:"previous_response_code"`
+	PreviousError        string                          `db:"previous_error"`
+	Actor                string                          `db:"actor"`
+	CreatedAt            int64                           `db:"created_at"`
+}
+
+func (m *WebhookDeliveryReplayModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.DeliveryID,
+		&m.PreviousStatus,
+		&m.PreviousAttempts,
+		&m.PreviousResponseCode,
+		&m.PreviousError,
+		&m.Actor,
+		&m.CreatedAt,
+	}
+}
+
+func (m *WebhookDeliveryReplayModel) GetTableName() string {
+	return "webhook_delivery_replays_tab"
+}
+
+// BillingNotificationModel is an open billing along with its loan's user contacts, used to send the billing notifications
+type BillingNotificationModel struct {
+	ID             int64           `db:"id"`
+	BillingID      string          `db:"billing_id"`
+	LoanID         int64           `db:"loan_id"`
+	RecurringIndex int             `db:"recurring_index"`
+	DueTime        int64           `db:"due_time"`
+	UnpaidAmount   decimal.Decimal `db:"unpaid_amount"`
+	UserID         int64           `db:"user_id"`
+	UserName       string          `db:"user_name"`
+	Email          string          `db:"email"`
+	PhoneNumber    string          `db:"phone_number"`
+	PushToken      string          `db:"push_token"`
+	Locale         string          `db:"locale"`
+}
+
+type NotificationModel struct {
+	ID               int64                         `db:"id"`
+	UserID           int64                         `db:"user_id"`
+	LoanID           int64                         `db:"loan_id"`
+	BillingID        string                        `db:"billing_id"`
+	DueTime          int64                         `db:"due_time"` // the billing's due time the notification is sent for
+	NotificationType constants.NotificationType    `db:"notification_type"`
+	Channel          constants.NotificationChannel `db:"channel"`
+	Locale           constants.Locale              `db:"locale"`
+	Recipient        string                        `db:"recipient"` // the email, phone number or push token
+	Status           constants.NotificationStatus  `db:"status"`
+	Attempts         int                           `db:"attempts"`
+	LastError        string                        `db:"last_error"`
+	SentAt           int64                         `db:"sent_at"`
+	CreatedAt        int64                         `db:"created_at"`
+	UpdatedAt        int64                         `db:"updated_at"`
+}
+
+func (m *NotificationModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.UserID,
+		&m.LoanID,
+		&m.BillingID,
+		&m.DueTime,
+		&m.NotificationType,
+		&m.Channel,
+		&m.Locale,
+		&m.Recipient,
+		&m.Status,
+		&m.Attempts,
+		&m.LastError,
+		&m.SentAt,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *NotificationModel) GetTableName() string {
+	return "notifications_tab"
+}
+
+type CollectionCaseModel struct {
+	ID           int64                               `db:"id"`
+	LoanID       int64                               `db:"loan_id"`
+	UserID       int64                               `db:"user_id"`
+	Status       constants.CollectionCaseStatus      `db:"status"`
+	Bucket       constants.CollectionBucket          `db:"bucket"`
+	DaysPastDue  int                                 `db:"days_past_due"` // as of the last sync
+	AssigneeType constants.CollectionAssigneeType    `db:"assignee_type"` // 0 when unassigned
+	AssigneeID   int64                               `db:"assignee_id"`
+	CloseReason  constants.CollectionCaseCloseReason `db:"close_reason"` // 0 while open
+	OpenedAt     int64                               `db:"opened_at"`
+	ClosedAt     int64                               `db:"closed_at"`
+	CreatedAt    int64                               `db:"created_at"`
+	UpdatedAt    int64                               `db:"updated_at"`
+}
+
+func (m *CollectionCaseModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.LoanID,
+		&m.UserID,
+		&m.Status,
+		&m.Bucket,
+		&m.DaysPastDue,
+		&m.AssigneeType,
+		&m.AssigneeID,
+		&m.CloseReason,
+		&m.OpenedAt,
+		&m.ClosedAt,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *CollectionCaseModel) GetTableName() string {
+	return "collection_cases_tab"
+}
+
+type CollectionActivityModel struct {
+	ID           int64                            `db:"id"`
+	CaseID       int64                            `db:"case_id"`
+	LoanID       int64                            `db:"loan_id"`
+	ActivityType constants.CollectionActivityType `db:"activity_type"`
+	Actor        string                           `db:"actor"`
+	Notes        string                           `db:"notes"`
+	ActivityTime int64                            `db:"activity_time"` // when the call or visit happened
+	CreatedAt    int64                            `db:"created_at"`
+}
+
+func (m *CollectionActivityModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.CaseID,
+		&m.LoanID,
+		&m.ActivityType,
+		&m.Actor,
+		&m.Notes,
+		&m.ActivityTime,
+		&m.CreatedAt,
+	}
+}
+
+func (m *CollectionActivityModel) GetTableName() string {
+	return "collection_activities_tab"
+}
+
+type PromiseToPayModel struct {
+	ID              int64                        `db:"id"`
+	CaseID          int64                        `db:"case_id"`
+	LoanID          int64                        `db:"loan_id"`
+	PromisedAmount  decimal.Decimal              `db:"promised_amount"`
+	PromisedDueTime int64                        `db:"promised_due_time"` // the start of the day after the promised date, exclusive
+	PaidAmount      decimal.Decimal              `db:"paid_amount"`       // paid since the promise was made, as of the last check
+	Status          constants.PromiseToPayStatus `db:"status"`
+	Actor           string                       `db:"actor"`
+	CreatedAt       int64                        `db:"created_at"`
+	UpdatedAt       int64                        `db:"updated_at"`
+}
+
+func (m *PromiseToPayModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.CaseID,
+		&m.LoanID,
+		&m.PromisedAmount,
+		&m.PromisedDueTime,
+		&m.PaidAmount,
+		&m.Status,
+		&m.Actor,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *PromiseToPayModel) GetTableName() string {
+	return "promises_to_pay_tab"
+}
+
+type VirtualAccountModel struct {
+	ID             int64                          `db:"id"`
+	Provider       string                         `db:"provider"`
+	ExternalID     string                         `db:"external_id"` // our reference sent to the provider, makes the creation idempotent
+	BankCode       string                         `db:"bank_code"`
+	VANumber       string                         `db:"va_number"`
+	UserID         int64                          `db:"user_id"`
+	LoanID         int64                          `db:"loan_id"`
+	BillingID      string                         `db:"billing_id"`      // empty when the account takes any payment of the loan
+	ExpectedAmount decimal.Decimal                `db:"expected_amount"` // the billing's unpaid amount, 0 for any amount
+	Status         constants.VirtualAccountStatus `db:"status"`
+	CreatedAt      int64                          `db:"created_at"`
+	UpdatedAt      int64                          `db:"updated_at"`
+}
+
+func (m *VirtualAccountModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.Provider,
+		&m.ExternalID,
+		&m.BankCode,
+		&m.VANumber,
+		&m.UserID,
+		&m.LoanID,
+		&m.BillingID,
+		&m.ExpectedAmount,
+		&m.Status,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}
+}
+
+func (m *VirtualAccountModel) GetTableName() string {
+	return "virtual_accounts_tab"
+}
+
+type VirtualAccountPaymentModel struct {
+	ID               int64                                 `db:"id"`
+	Provider         string                                `db:"provider"`
+	CallbackID       string                                `db:"callback_id"` // the provider's id of the paid callback, a redelivered callback is posted once
+	VirtualAccountID int64                                 `db:"virtual_account_id"`
+	LoanID           int64                                 `db:"loan_id"`
+	Amount           decimal.Decimal                       `db:"amount"`
+	PaidAt           int64                                 `db:"paid_at"`
+	PaymentID        int64                                 `db:"payment_id"`       // 0 when nothing is posted to the loan
+	SuspenseItemID   int64                                 `db:"suspense_item_id"` // the part held in suspense, 0 when fully posted
+	Status           constants.VirtualAccountPaymentStatus `db:"status"`
+	SuspenseReason   string                                `db:"suspense_reason"`
+	Payload          string                                `db:"payload"` // the callback body as received
+	CreatedAt        int64                                 `db:"created_at"`
+}
+
+func (m *VirtualAccountPaymentModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.Provider,
+		&m.CallbackID,
+		&m.VirtualAccountID,
+		&m.LoanID,
+		&m.Amount,
+		&m.PaidAt,
+		&m.PaymentID,
+		&m.SuspenseItemID,
+		&m.Status,
+		&m.SuspenseReason,
+		&m.Payload,
+		&m.CreatedAt,
+	}
+}
+
+func (m *VirtualAccountPaymentModel) GetTableName() string {
+	return "virtual_account_payments_tab"
+}
+
+// ECLRunModel is the stored provision of a reporting date, rerunning the date returns it as stored
+type ECLRunModel struct {
+	ID                  int64           `db:"id"`
+	AsOfDate            int64           `db:"as_of_date"` // the start of the reporting date
+	LoanCount           int             `db:"loan_count"`
+	TotalExposureAmount decimal.Decimal `db:"total_exposure_amount"`
+	TotalECLAmount      decimal.Decimal `db:"total_ecl_amount"`
+	CreatedAt           int64           `db:"created_at"`
+}
+
+func (m *ECLRunModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.AsOfDate,
+		&m.LoanCount,
+		&m.TotalExposureAmount,
+		&m.TotalECLAmount,
+		&m.CreatedAt,
+	}
+}
+
+func (m *ECLRunModel) GetTableName() string {
+	return "ecl_runs_tab"
+}
+
+type ECLRunLoanModel struct {
+	ID             int64           `db:"id"`
+	RunID          int64           `db:"run_id"`
+	LoanID         int64           `db:"loan_id"`
+	UserID         int64           `db:"user_id"`
+	ProductCode    string          `db:"product_code"`
+	DaysPastDue    int             `db:"days_past_due"`
+	IsRestructured bool            `db:"is_restructured"`
+	Stage          int8            `db:"stage"`
+	ExposureAmount decimal.Decimal `db:"exposure_amount"`
+	PD             decimal.Decimal `db:"pd"`  // inflated by 10^2
+	LGD            decimal.Decimal `db:"lgd"` // inflated by 10^2
+	ECLAmount      decimal.Decimal `db:"ecl_amount"`
+}
+
+func (m *ECLRunLoanModel) GetAll() []interface{} {
+	return []interface{}{
+		&m.ID,
+		&m.RunID,
+		&m.LoanID,
+		&m.UserID,
+		&m.ProductCode,
+		&m.DaysPastDue,
+		&m.IsRestructured,
+		&m.Stage,
+		&m.ExposureAmount,
+		&m.PD,
+		&m.LGD,
+		&m.ECLAmount,
+	}
+}
+
+func (m *ECLRunLoanModel) GetTableName() string {
+	return "ecl_run_loans_tab"
+}
@@ -0,0 +1,62 @@
+package dtos
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// Event is the envelope of every published domain event, LoanID is the partition key
+type Event struct {
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	LoanID     int64           `json:"loan_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt int64           `json:"occurred_at"`
+}
+
+type LoanCreatedEvent struct {
+	LoanID      int64           `json:"loan_id"`
+	UserID      int64           `json:"user_id"`
+	ProductID   int64           `json:"product_id"`
+	PartnerID   int64           `json:"partner_id"`
+	LoanAmount  decimal.Decimal `json:"loan_amount"`
+	TenureValue int             `json:"tenure_value"`
+	TenureUnit  int8            `json:"tenure_unit"`
+}
+
+type LoanStatusChangedEvent struct {
+	LoanID     int64  `json:"loan_id"`
+	UserID     int64  `json:"user_id"`
+	FromStatus int8   `json:"from_status"`
+	ToStatus   int8   `json:"to_status"`
+	Actor      string `json:"actor"`
+	Reason     string `json:"reason"`
+}
+
+type LoanCompletedEvent struct {
+	LoanID int64 `json:"loan_id"`
+	UserID int64 `json:"user_id"`
+}
+
+type PaymentReceivedEvent struct {
+	PaymentID       int64           `json:"payment_id"`
+	LoanID          int64           `json:"loan_id"`
+	UserID          int64           `json:"user_id"`
+	Amount          decimal.Decimal `json:"amount"`
+	PrincipalAmount decimal.Decimal `json:"principal_amount"`
+	InterestAmount  decimal.Decimal `json:"interest_amount"`
+	PenaltyAmount   decimal.Decimal `json:"penalty_amount"`
+	FeeAmount       decimal.Decimal `json:"fee_amount"`
+	IsRecovery      bool            `json:"is_recovery"` // paid after the loan is written off
+}
+
+// BillingDueEvent is the payload of both billing.due and billing.overdue
+type BillingDueEvent struct {
+	BillingID      string          `json:"billing_id"`
+	LoanID         int64           `json:"loan_id"`
+	UserID         int64           `json:"user_id"`
+	RecurringIndex int             `json:"recurring_index"`
+	DueTime        int64           `json:"due_time"`
+	UnpaidAmount   decimal.Decimal `json:"unpaid_amount"`
+}
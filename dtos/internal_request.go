@@ -2,10 +2,144 @@ package dtos
 
 type CreateLoanRequestParam struct {
 	UserID             int64  `json:"user_id"`
+	ProductCode        string `json:"product_code"` // optional
+	PartnerID          int64  `json:"partner_id"`   // optional, the merchant partner receiving the loan's webhooks
 	LoanAmount         string `json:"loan_amount"`
 	TenureValue        int    `json:"tenure_value"`
 	TenureUnit         int8   `json:"tenure_unit"`
-	AnnualInterestRate string `json:"annual_interest_rate"` // inflated by 10^2
+	AnnualInterestRate string `json:"annual_interest_rate"` // inflated by 10^2, follows the product when empty
+	AmortizationMethod int8   `json:"amortization_method"`  // optional, follows the product or flat by default
+	GracePeriodType    int8   `json:"grace_period_type"`    // optional
+	GracePeriodValue   int    `json:"grace_period_value"`   // number of tenure periods, included in tenure_value
+	BalloonAmount      string `json:"balloon_amount"`       // optional, repaid with the last installment
+	BalloonPercentage  string `json:"balloon_percentage"`   // optional, inflated by 10^2, alternative of balloon_amount
+
+	// optional, replaces the generated schedule. the interest is still calculated by the engine
+	Installments []LoanInstallmentParam `json:"installments"`
+
+	// optional, only when the loan has no product. otherwise the product's fees are charged
+	Fees []FeeParam `json:"fees"`
+}
+
+type LoanInstallmentParam struct {
+	DueTime         int64  `json:"due_time"` // unix milli, billed at the end of the day
+	PrincipalAmount string `json:"principal_amount"`
+}
+
+type ApproveLoanRequestParam struct {
+	LoanID int64  `json:"loan_id"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"` // optional
+}
+
+type RejectLoanRequestParam struct {
+	LoanID int64  `json:"loan_id"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+type CancelLoanRequestParam struct {
+	UserID int64  `json:"user_id"`
+	LoanID int64  `json:"loan_id"`
+	Reason string `json:"reason"` // optional
+}
+
+type DisburseLoanRequestParam struct {
+	LoanID           int64  `json:"loan_id"`
+	Actor            string `json:"actor"`
+	DisbursementTime int64  `json:"disbursement_time"` // optional, default: now
+}
+
+type AmendLoanRequestInstallmentsParam struct {
+	LoanID       int64                  `json:"loan_id"`
+	Actor        string                 `json:"actor"`
+	Reason       string                 `json:"reason"`
+	Installments []LoanInstallmentParam `json:"installments"` // replaces the submitted installments, same count and principal
+}
+
+type DefaultLoanRequestParam struct {
+	LoanID int64  `json:"loan_id"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+type WaiveBillingsParam struct {
+	LoanID     int64    `json:"loan_id"`
+	BillingIDs []string `json:"billing_ids"`
+	Actor      string   `json:"actor"`
+	Reason     string   `json:"reason"`
+}
+
+type RestructureLoanParam struct {
+	LoanID             int64  `json:"loan_id"`
+	TenureValue        int    `json:"tenure_value"`         // number of installments of the new schedule
+	TenureUnit         int8   `json:"tenure_unit"`          // optional, default: the loan's tenure unit
+	AnnualInterestRate string `json:"annual_interest_rate"` // optional, default: the loan's annual interest rate
+	AmortizationMethod int8   `json:"amortization_method"`  // optional, default: the loan's amortization method
+	CapitaliseArrears  bool   `json:"capitalise_arrears"`   // overdue billings are folded into the new schedule
+	Actor              string `json:"actor"`
+	Reason             string `json:"reason"`
+}
+
+type PaymentHolidayParam struct {
+	HolidayCode    string  `json:"holiday_code"` // identifies the holiday, a loan gets the same holiday code at most once
+	LoanIDs        []int64 `json:"loan_ids"`     // optional, default: every loan in repayment or defaulted
+	ProductCode    string  `json:"product_code"` // optional, only applied without loan_ids
+	Periods        int     `json:"periods"`      // number of tenure periods the open billings are shifted by
+	AccrueInterest bool    `json:"accrue_interest"`
+	Actor          string  `json:"actor"`
+	Reason         string  `json:"reason"`
+}
+
+type WriteOffLoanParam struct {
+	LoanID int64  `json:"loan_id"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+type GetWriteOffReportParam struct {
+	StartTime int64 `json:"start_time"` // write-offs created from start_time (inclusive)
+	EndTime   int64 `json:"end_time"`   // until end_time (exclusive)
+}
+
+type GetLedgerBalancesParam struct {
+	LoanID int64 `json:"loan_id"` // optional, balances of every loan when empty
+}
+
+type GetSuspenseItemsParam struct {
+	Status     int8  `json:"status"`       // optional, any status when empty
+	LastItemID int64 `json:"last_item_id"` // optional, the last item id of the previous page
+	Limit      int   `json:"limit"`        // optional, 20 when empty
+}
+
+type ResolveSuspenseItemParam struct {
+	SuspenseItemID int64  `json:"suspense_item_id"`
+	Status         int8   `json:"status"`  // applied or refunded
+	UserID         int64  `json:"user_id"` // the loan's borrower, only when applied
+	LoanID         int64  `json:"loan_id"` // only when applied
+	Actor          string `json:"actor"`
+	Reason         string `json:"reason"`
+}
+
+type AccrueInterestParam struct {
+	Date string `json:"date"` // YYYY-MM-DD, optional, yesterday when empty
+}
+
+type GetInterestAccrualReportParam struct {
+	LoanID int64 `json:"loan_id"` // optional, every loan in repayment or defaulted when empty
+}
+
+type GetECLReportParam struct {
+	AsOf string `json:"as_of"` // YYYY-MM-DD, optional, yesterday when empty, only a date with a stored run can be reported
+}
+
+type ExportGLJournalParam struct {
+	Date string `json:"date"` // YYYY-MM-DD in the configured timezone, only a past date can be exported
+}
+
+type GetLoanDetailParam struct {
+	UserID int64 `json:"user_id"`
+	LoanID int64 `json:"loan_id"`
 }
 
 type GetOutstandingParam struct {
@@ -22,3 +156,103 @@ type MakePaymentParam struct {
 	LoanID int64  `json:"loan_id"`
 	Amount string `json:"amount"`
 }
+
+type CreateLoanProductParam struct {
+	ProductCode               string                 `json:"product_code"`
+	Name                      string                 `json:"name"`
+	MinLoanAmount             string                 `json:"min_loan_amount"`
+	MaxLoanAmount             string                 `json:"max_loan_amount"`
+	AllowedTenureUnits        []int8                 `json:"allowed_tenure_units"`
+	MinTenureValue            int                    `json:"min_tenure_value"`
+	MaxTenureValue            int                    `json:"max_tenure_value"`
+	AnnualInterestRate        string                 `json:"annual_interest_rate"` // inflated by 10^2
+	AmortizationMethod        int8                   `json:"amortization_method"`
+	LatePenaltyRate           string                 `json:"late_penalty_rate"` // daily, inflated by 10^2
+	LatePenaltyGraceDays      int                    `json:"late_penalty_grace_days"`
+	DelinquentOverdueBillings int                    `json:"delinquent_overdue_billings"`
+	Rates                     []LoanProductRateParam `json:"rates"` // optional, overrides annual_interest_rate by tenure
+	Fees                      []FeeParam             `json:"fees"`
+}
+
+type LoanProductRateParam struct {
+	TenureUnit         int8   `json:"tenure_unit"`
+	MinTenureValue     int    `json:"min_tenure_value"`
+	MaxTenureValue     int    `json:"max_tenure_value"`
+	AnnualInterestRate string `json:"annual_interest_rate"` // inflated by 10^2
+}
+
+type FeeParam struct {
+	FeeType         int8   `json:"fee_type"`
+	CalculationType int8   `json:"calculation_type"`
+	Value           string `json:"value"` // percentage is inflated by 10^2
+	ChargeType      int8   `json:"charge_type"`
+}
+
+type GetLoanProductParam struct {
+	ProductCode string `json:"product_code"`
+}
+
+type CreateWebhookSubscriptionParam struct {
+	PartnerID  int64    `json:"partner_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"` // signs the payloads with HMAC-SHA256
+}
+
+type DeactivateWebhookSubscriptionParam struct {
+	SubscriptionID int64 `json:"subscription_id"`
+}
+
+type GetWebhookDeliveriesParam struct {
+	SubscriptionID int64 `json:"subscription_id"`
+	Status         int8  `json:"status"`           // optional, any status when empty
+	LastDeliveryID int64 `json:"last_delivery_id"` // optional, the last delivery id of the previous page
+	Limit          int   `json:"limit"`            // optional, 20 when empty
+}
+
+type ReplayWebhookDeliveryParam struct {
+	DeliveryID int64  `json:"delivery_id"`
+	Actor      string `json:"actor"`
+}
+
+type SendBillingNotificationsParam struct {
+	Date string `json:"date"` // YYYY-MM-DD in the configured timezone, optional, today when empty
+}
+
+type AssignCollectionCaseParam struct {
+	CaseID       int64  `json:"case_id"`
+	AssigneeType int8   `json:"assignee_type"`
+	AssigneeID   int64  `json:"assignee_id"` // the agent or agency id
+	Actor        string `json:"actor"`
+}
+
+type LogCollectionActivityParam struct {
+	CaseID       int64  `json:"case_id"`
+	ActivityType int8   `json:"activity_type"` // call, visit, message or note
+	Notes        string `json:"notes"`
+	ActivityTime int64  `json:"activity_time"` // optional, now when empty
+	Actor        string `json:"actor"`
+}
+
+type CreatePromiseToPayParam struct {
+	CaseID int64  `json:"case_id"`
+	Amount string `json:"amount"`
+	Date   string `json:"date"` // YYYY-MM-DD, the amount is paid by the end of the date
+	Actor  string `json:"actor"`
+}
+
+type GetCollectionCaseParam struct {
+	CaseID int64 `json:"case_id"`
+}
+
+type CreateVirtualAccountParam struct {
+	UserID    int64  `json:"user_id"`
+	LoanID    int64  `json:"loan_id"`
+	BillingID string `json:"billing_id"` // optional, an account of the loan taking any amount when empty
+}
+
+type VirtualAccountCallbackParam struct {
+	Timestamp string `json:"-"` // the X-Callback-Timestamp header
+	Signature string `json:"-"` // the X-Callback-Signature header
+	Body      []byte `json:"-"` // the raw body, the signature covers it byte by byte
+}
@@ -1 +1,385 @@
 package dtos
+
+import (
+	"encoding/json"
+
+	"loan-payment/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+type LoanQuoteResponse struct {
+	LoanAmount           decimal.Decimal        `json:"loan_amount"` // including fees added to principal
+	DisbursedAmount      decimal.Decimal        `json:"disbursed_amount"`
+	TotalFeeAmount       decimal.Decimal        `json:"total_fee_amount"`
+	TotalInterestAmount  decimal.Decimal        `json:"total_interest_amount"`
+	TotalRepaymentAmount decimal.Decimal        `json:"total_repayment_amount"`
+	EffectiveAPR         decimal.Decimal        `json:"effective_apr"` // inflated by 10^2
+	TotalCostOfCredit    decimal.Decimal        `json:"total_cost_of_credit"`
+	Installments         []LoanQuoteInstallment `json:"installments"`
+}
+
+type LoanQuoteInstallment struct {
+	RecurringIndex  int             `json:"recurring_index"`
+	DueTime         int64           `json:"due_time"`
+	PrincipalAmount decimal.Decimal `json:"principal_amount"`
+	InterestAmount  decimal.Decimal `json:"interest_amount"`
+	FeeAmount       decimal.Decimal `json:"fee_amount"`
+	TotalAmount     decimal.Decimal `json:"total_amount"`
+}
+
+type LoanDetailResponse struct {
+	LoanID              int64                   `json:"loan_id"`
+	UserID              int64                   `json:"user_id"`
+	LoanAmount          decimal.Decimal         `json:"loan_amount"`
+	DisbursedAmount     decimal.Decimal         `json:"disbursed_amount"`
+	PrincipalPaidAmount decimal.Decimal         `json:"principal_paid_amount"`
+	InterestPaidAmount  decimal.Decimal         `json:"interest_paid_amount"`
+	PenaltyPaidAmount   decimal.Decimal         `json:"penalty_paid_amount"`
+	FeePaidAmount       decimal.Decimal         `json:"fee_paid_amount"`
+	DisbursementTime    int64                   `json:"disbursement_time"`
+	TenureValue         int                     `json:"tenure_value"`
+	TenureUnit          constants.TenureUnit    `json:"tenure_unit"`
+	Status              constants.LoanStatus    `json:"status"`
+	AnnualInterestRate  decimal.Decimal         `json:"annual_interest_rate"` // inflated by 10^2
+	EffectiveAPR        decimal.Decimal         `json:"effective_apr"`        // inflated by 10^2
+	TotalCostOfCredit   decimal.Decimal         `json:"total_cost_of_credit"`
+	Fees                []LoanDetailFee         `json:"fees"`
+	Restructures        []LoanDetailRestructure `json:"restructures"`
+	Billings            []LoanDetailBilling     `json:"billings"`
+}
+
+type LoanDetailRestructure struct {
+	RestructureID              int64                        `json:"restructure_id"`
+	TenureValue                int                          `json:"tenure_value"`
+	TenureUnit                 constants.TenureUnit         `json:"tenure_unit"`
+	AnnualInterestRate         decimal.Decimal              `json:"annual_interest_rate"`
+	AmortizationMethod         constants.AmortizationMethod `json:"amortization_method"`
+	OutstandingPrincipalAmount decimal.Decimal              `json:"outstanding_principal_amount"`
+	CapitalisedArrearsAmount   decimal.Decimal              `json:"capitalised_arrears_amount"`
+	FirstRecurringIndex        int                          `json:"first_recurring_index"`
+	Reason                     string                       `json:"reason"`
+	CreatedAt                  int64                        `json:"created_at"`
+}
+
+type LoanDetailFee struct {
+	FeeType    constants.FeeType       `json:"fee_type"`
+	Amount     decimal.Decimal         `json:"amount"`
+	ChargeType constants.FeeChargeType `json:"charge_type"`
+}
+
+type LoanDetailBilling struct {
+	BillingID                string                  `json:"billing_id"`
+	RecurringIndex           int                     `json:"recurring_index"`
+	RestructureID            int64                   `json:"restructure_id"`              // 0 for the original schedule
+	CancelledByRestructureID int64                   `json:"cancelled_by_restructure_id"` // 0 unless cancelled by a restructure
+	PrincipalAmount          decimal.Decimal         `json:"principal_amount"`
+	InterestAmount           decimal.Decimal         `json:"interest_amount"`
+	PenaltyAmount            decimal.Decimal         `json:"penalty_amount"`
+	FeeAmount                decimal.Decimal         `json:"fee_amount"`
+	TotalAmount              decimal.Decimal         `json:"total_amount"`
+	PaidAmount               decimal.Decimal         `json:"paid_amount"`
+	DueTime                  int64                   `json:"due_time"`
+	PaymentCompletedAt       int64                   `json:"payment_completed_at"`
+	Status                   constants.PaymentStatus `json:"status"`
+}
+
+type LoanProductResponse struct {
+	ProductCode               string                       `json:"product_code"`
+	Name                      string                       `json:"name"`
+	MinLoanAmount             decimal.Decimal              `json:"min_loan_amount"`
+	MaxLoanAmount             decimal.Decimal              `json:"max_loan_amount"`
+	AllowedTenureUnits        []constants.TenureUnit       `json:"allowed_tenure_units"`
+	MinTenureValue            int                          `json:"min_tenure_value"`
+	MaxTenureValue            int                          `json:"max_tenure_value"`
+	AnnualInterestRate        decimal.Decimal              `json:"annual_interest_rate"` // inflated by 10^2
+	AmortizationMethod        constants.AmortizationMethod `json:"amortization_method"`
+	LatePenaltyRate           decimal.Decimal              `json:"late_penalty_rate"` // daily, inflated by 10^2
+	LatePenaltyGraceDays      int                          `json:"late_penalty_grace_days"`
+	DelinquentOverdueBillings int                          `json:"delinquent_overdue_billings"`
+	Status                    constants.LoanProductStatus  `json:"status"`
+	Rates                     []LoanProductRate            `json:"rates"`
+	Fees                      []LoanProductFee             `json:"fees"`
+}
+
+type LoanProductRate struct {
+	TenureUnit         constants.TenureUnit `json:"tenure_unit"`
+	MinTenureValue     int                  `json:"min_tenure_value"`
+	MaxTenureValue     int                  `json:"max_tenure_value"`
+	AnnualInterestRate decimal.Decimal      `json:"annual_interest_rate"` // inflated by 10^2
+}
+
+type LoanProductFee struct {
+	FeeType         constants.FeeType            `json:"fee_type"`
+	CalculationType constants.FeeCalculationType `json:"calculation_type"`
+	Value           decimal.Decimal              `json:"value"`
+	ChargeType      constants.FeeChargeType      `json:"charge_type"`
+}
+
+type RegulatoryCapReportResponse struct {
+	MaxDailyEconomicBenefitRate decimal.Decimal           `json:"max_daily_economic_benefit_rate"` // inflated by 10^2
+	MaxTotalPenaltyRate         decimal.Decimal           `json:"max_total_penalty_rate"`          // inflated by 10^2
+	NearCapThreshold            decimal.Decimal           `json:"near_cap_threshold"`              // inflated by 10^2
+	Loans                       []RegulatoryCapReportLoan `json:"loans"`
+}
+
+type RegulatoryCapReportLoan struct {
+	LoanID                    int64           `json:"loan_id"`
+	UserID                    int64           `json:"user_id"`
+	DailyEconomicBenefitRate  decimal.Decimal `json:"daily_economic_benefit_rate"`  // inflated by 10^2
+	DailyEconomicBenefitUsage decimal.Decimal `json:"daily_economic_benefit_usage"` // inflated by 10^2, of the cap
+	PenaltyAmount             decimal.Decimal `json:"penalty_amount"`
+	PenaltyCapAmount          decimal.Decimal `json:"penalty_cap_amount"`
+	PenaltyUsage              decimal.Decimal `json:"penalty_usage"` // inflated by 10^2, of the cap
+}
+
+type PaymentHolidayResponse struct {
+	TotalHolidayInterestAmount decimal.Decimal      `json:"total_holiday_interest_amount"`
+	TotalReversedPenaltyAmount decimal.Decimal      `json:"total_reversed_penalty_amount"`
+	Loans                      []PaymentHolidayLoan `json:"loans"`
+	SkippedLoanIDs             []int64              `json:"skipped_loan_ids"` // the holiday code is already applied
+	FailedLoanIDs              []int64              `json:"failed_loan_ids"`
+}
+
+type PaymentHolidayLoan struct {
+	LoanID                int64                   `json:"loan_id"`
+	UserID                int64                   `json:"user_id"`
+	HolidayInterestAmount decimal.Decimal         `json:"holiday_interest_amount"`
+	ReversedPenaltyAmount decimal.Decimal         `json:"reversed_penalty_amount"`
+	Billings              []PaymentHolidayBilling `json:"billings"`
+}
+
+type PaymentHolidayBilling struct {
+	BillingID         string          `json:"billing_id"`
+	RecurringIndex    int             `json:"recurring_index"`
+	DueTime           int64           `json:"due_time"`
+	NewDueTime        int64           `json:"new_due_time"`
+	InterestAmount    decimal.Decimal `json:"interest_amount"`
+	NewInterestAmount decimal.Decimal `json:"new_interest_amount"`
+	PenaltyAmount     decimal.Decimal `json:"penalty_amount"`
+	NewPenaltyAmount  decimal.Decimal `json:"new_penalty_amount"` // the unpaid penalty is reversed once the billing is no longer overdue
+}
+
+type WriteOffReportResponse struct {
+	TotalWrittenOffAmount decimal.Decimal      `json:"total_written_off_amount"`
+	TotalRecoveredAmount  decimal.Decimal      `json:"total_recovered_amount"`
+	Loans                 []WriteOffReportLoan `json:"loans"`
+}
+
+type WriteOffReportLoan struct {
+	LoanID           int64           `json:"loan_id"`
+	PrincipalAmount  decimal.Decimal `json:"principal_amount"`
+	InterestAmount   decimal.Decimal `json:"interest_amount"`
+	PenaltyAmount    decimal.Decimal `json:"penalty_amount"`
+	FeeAmount        decimal.Decimal `json:"fee_amount"`
+	WrittenOffAmount decimal.Decimal `json:"written_off_amount"`
+	RecoveredAmount  decimal.Decimal `json:"recovered_amount"`
+	WrittenOffAt     int64           `json:"written_off_at"`
+}
+
+type LedgerBalancesResponse struct {
+	Accounts []LedgerAccountBalance `json:"accounts"`
+}
+
+type LedgerAccountBalance struct {
+	Account      int8            `json:"account"`
+	DebitAmount  decimal.Decimal `json:"debit_amount"`
+	CreditAmount decimal.Decimal `json:"credit_amount"`
+	Balance      decimal.Decimal `json:"balance"` // on the account's normal side
+}
+
+type LedgerCheckResponse struct {
+	TotalDebitAmount          decimal.Decimal `json:"total_debit_amount"`
+	TotalCreditAmount         decimal.Decimal `json:"total_credit_amount"`
+	IsBalanced                bool            `json:"is_balanced"`
+	UnbalancedJournalEntryIDs []int64         `json:"unbalanced_journal_entry_ids"`
+}
+
+type SuspenseItemsResponse struct {
+	Items []SuspenseItemResponse `json:"items"`
+}
+
+type SuspenseItemResponse struct {
+	SuspenseItemID int64           `json:"suspense_item_id"`
+	Source         string          `json:"source"`
+	ReferenceID    string          `json:"reference_id"`
+	UserID         int64           `json:"user_id"`
+	LoanID         int64           `json:"loan_id"`
+	Amount         decimal.Decimal `json:"amount"`
+	Reason         string          `json:"reason"`
+	Status         int8            `json:"status"`
+	PaymentID      int64           `json:"payment_id"`
+	ResolvedBy     string          `json:"resolved_by"`
+	ResolvedReason string          `json:"resolved_reason"`
+	ResolvedAt     int64           `json:"resolved_at"`
+	CreatedAt      int64           `json:"created_at"`
+}
+
+type GLJournalExportResponse struct {
+	Journal      GLJournal `json:"journal"`
+	CSVFilePath  string    `json:"csv_file_path"`
+	JSONFilePath string    `json:"json_file_path"`
+}
+
+type GLJournal struct {
+	Date              string          `json:"date"`
+	TotalDebitAmount  decimal.Decimal `json:"total_debit_amount"`
+	TotalCreditAmount decimal.Decimal `json:"total_credit_amount"`
+	Lines             []GLJournalLine `json:"lines"`
+}
+
+type GLJournalLine struct {
+	JournalID    string          `json:"journal_id"`
+	Date         string          `json:"date"`
+	EntryType    string          `json:"entry_type"`
+	AccountCode  string          `json:"account_code"`
+	AccountName  string          `json:"account_name"`
+	DebitAmount  decimal.Decimal `json:"debit_amount"`
+	CreditAmount decimal.Decimal `json:"credit_amount"`
+}
+
+type InterestAccrualReportResponse struct {
+	TotalAccruedInterestAmount       decimal.Decimal             `json:"total_accrued_interest_amount"`
+	TotalAccruedUnpaidInterestAmount decimal.Decimal             `json:"total_accrued_unpaid_interest_amount"`
+	Loans                            []InterestAccrualReportLoan `json:"loans"`
+}
+
+type InterestAccrualReportLoan struct {
+	LoanID                      int64           `json:"loan_id"`
+	DaysPastDue                 int             `json:"days_past_due"`
+	IsNonAccrual                bool            `json:"is_non_accrual"`
+	AccruedInterestAmount       decimal.Decimal `json:"accrued_interest_amount"`
+	InterestPaidAmount          decimal.Decimal `json:"interest_paid_amount"`
+	AccruedUnpaidInterestAmount decimal.Decimal `json:"accrued_unpaid_interest_amount"`
+}
+
+type ECLReportResponse struct {
+	ECLRunID            int64            `json:"ecl_run_id"`
+	AsOf                string           `json:"as_of"`
+	CreatedAt           int64            `json:"created_at"` // when the run was taken
+	TotalExposureAmount decimal.Decimal  `json:"total_exposure_amount"`
+	TotalECLAmount      decimal.Decimal  `json:"total_ecl_amount"`
+	Stages              []ECLReportStage `json:"stages"`
+	Loans               []ECLReportLoan  `json:"loans"`
+}
+
+type ECLReportStage struct {
+	Stage          int8            `json:"stage"`
+	LoanCount      int             `json:"loan_count"`
+	ExposureAmount decimal.Decimal `json:"exposure_amount"`
+	ECLAmount      decimal.Decimal `json:"ecl_amount"`
+}
+
+type ECLReportLoan struct {
+	LoanID         int64           `json:"loan_id"`
+	UserID         int64           `json:"user_id"`
+	ProductCode    string          `json:"product_code"`
+	DaysPastDue    int             `json:"days_past_due"`
+	IsRestructured bool            `json:"is_restructured"`
+	Stage          int8            `json:"stage"`
+	ExposureAmount decimal.Decimal `json:"exposure_amount"` // unpaid principal and accrued unpaid interest
+	PD             decimal.Decimal `json:"pd"`              // inflated by 10^2
+	LGD            decimal.Decimal `json:"lgd"`             // inflated by 10^2
+	ECLAmount      decimal.Decimal `json:"ecl_amount"`
+}
+
+type DeliverWebhooksResponse struct {
+	DeliveredCount    int `json:"delivered_count"`
+	RetryingCount     int `json:"retrying_count"`
+	DeadLetteredCount int `json:"dead_lettered_count"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryID       int64           `json:"delivery_id"`
+	SubscriptionID   int64           `json:"subscription_id"`
+	EventID          string          `json:"event_id"`
+	EventType        string          `json:"event_type"`
+	LoanID           int64           `json:"loan_id"`
+	Payload          json.RawMessage `json:"payload"`
+	Status           int8            `json:"status"`
+	Attempts         int             `json:"attempts"`
+	NextAttemptAt    int64           `json:"next_attempt_at"`
+	LastResponseCode int             `json:"last_response_code"`
+	LastError        string          `json:"last_error"`
+	DeliveredAt      int64           `json:"delivered_at"`
+	ReplayCount      int             `json:"replay_count"`
+	CreatedAt        int64           `json:"created_at"`
+}
+
+type SendBillingNotificationsResponse struct {
+	SentCount   int `json:"sent_count"`
+	FailedCount int `json:"failed_count"`
+}
+
+type SyncCollectionCasesResponse struct {
+	OpenedCount        int `json:"opened_count"`
+	BucketChangedCount int `json:"bucket_changed_count"`
+	ClosedCount        int `json:"closed_count"`
+}
+
+type CheckPromisesToPayResponse struct {
+	KeptCount   int `json:"kept_count"`
+	BrokenCount int `json:"broken_count"`
+}
+
+type CollectionCaseResponse struct {
+	CaseID       int64 `json:"case_id"`
+	LoanID       int64 `json:"loan_id"`
+	UserID       int64 `json:"user_id"`
+	Status       int8  `json:"status"`
+	Bucket       int8  `json:"bucket"`
+	DaysPastDue  int   `json:"days_past_due"`
+	AssigneeType int8  `json:"assignee_type"`
+	AssigneeID   int64 `json:"assignee_id"`
+	CloseReason  int8  `json:"close_reason"`
+	OpenedAt     int64 `json:"opened_at"`
+	ClosedAt     int64 `json:"closed_at"`
+}
+
+type CollectionCaseDetailResponse struct {
+	CollectionCaseResponse
+	Activities    []CollectionActivityResponse `json:"activities"`
+	PromisesToPay []PromiseToPayResponse       `json:"promises_to_pay"`
+}
+
+type CollectionActivityResponse struct {
+	ActivityID   int64  `json:"activity_id"`
+	ActivityType int8   `json:"activity_type"`
+	Actor        string `json:"actor"`
+	Notes        string `json:"notes"`
+	ActivityTime int64  `json:"activity_time"`
+}
+
+type PromiseToPayResponse struct {
+	PromiseID       int64           `json:"promise_id"`
+	PromisedAmount  decimal.Decimal `json:"promised_amount"`
+	PromisedDueTime int64           `json:"promised_due_time"` // exclusive
+	PaidAmount      decimal.Decimal `json:"paid_amount"`
+	Status          int8            `json:"status"`
+	Actor           string          `json:"actor"`
+	CreatedAt       int64           `json:"created_at"`
+}
+
+type VirtualAccountResponse struct {
+	VirtualAccountID int64           `json:"virtual_account_id"`
+	BankCode         string          `json:"bank_code"`
+	VANumber         string          `json:"va_number"`
+	LoanID           int64           `json:"loan_id"`
+	BillingID        string          `json:"billing_id"`
+	ExpectedAmount   decimal.Decimal `json:"expected_amount"` // 0 takes any amount
+}
+
+type VirtualAccountPaymentResponse struct {
+	CallbackID       string          `json:"callback_id"`
+	VirtualAccountID int64           `json:"virtual_account_id"` // 0 when the account is unknown
+	LoanID           int64           `json:"loan_id"`
+	Amount           decimal.Decimal `json:"amount"`
+	PaymentID        int64           `json:"payment_id"`       // the part posted to the loan, 0 when none
+	SuspenseItemID   int64           `json:"suspense_item_id"` // the part held in suspense, 0 when none
+	Status           int8            `json:"status"`
+	SuspenseReason   string          `json:"suspense_reason"`
+}
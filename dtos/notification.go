@@ -0,0 +1,11 @@
+package dtos
+
+// Notification is a rendered message handed to the channel's sender
+type Notification struct {
+	NotificationID int64  `json:"notification_id"` // lets the provider drop a resent notification
+	Channel        string `json:"channel"`
+	Recipient      string `json:"recipient"` // the email, phone number or push token
+	Locale         string `json:"locale"`
+	Subject        string `json:"subject"` // the email subject or push title, unused by sms
+	Body           string `json:"body"`
+}
@@ -0,0 +1,33 @@
+package dtos
+
+import "github.com/shopspring/decimal"
+
+// CreateVirtualAccountRequest follows the payment provider's virtual account api
+type CreateVirtualAccountRequest struct {
+	ExternalID     string          `json:"external_id"`
+	BankCode       string          `json:"bank_code"`
+	Name           string          `json:"name"`            // shown to the payer by the bank
+	ExpectedAmount decimal.Decimal `json:"expected_amount"` // 0 accepts any amount
+	IsSingleUse    bool            `json:"is_single_use"`   // closed once paid
+}
+
+type VirtualAccount struct {
+	ID             string          `json:"id"`
+	ExternalID     string          `json:"external_id"`
+	BankCode       string          `json:"bank_code"`
+	VANumber       string          `json:"va_number"`
+	ExpectedAmount decimal.Decimal `json:"expected_amount"`
+	IsSingleUse    bool            `json:"is_single_use"`
+	Status         string          `json:"status"` // active or inactive
+}
+
+// VirtualAccountPaidCallback is the body of the provider's paid callback, signed along with the X-Callback-Timestamp header
+type VirtualAccountPaidCallback struct {
+	CallbackID       string          `json:"callback_id"`
+	VirtualAccountID string          `json:"virtual_account_id"`
+	ExternalID       string          `json:"external_id"`
+	BankCode         string          `json:"bank_code"`
+	VANumber         string          `json:"va_number"`
+	Amount           decimal.Decimal `json:"amount"`
+	PaidAt           int64           `json:"paid_at"` // unix ms
+}
@@ -0,0 +1,234 @@
+// Package fakeprovider is an in-memory stand-in of the virtual account payment provider, serving the same json api
+// so the payment flow can be run end to end offline:
+//
+//	POST /virtual-accounts                       creates an account, idempotent by external_id
+//	GET  /virtual-accounts/{va_number}           returns the account
+//	POST /virtual-accounts/{va_number}/payments  simulates a transfer of {"amount"} and sends the signed paid callback
+//	POST /callbacks/{callback_id}/resend         sends a callback again, as the provider does when not acknowledged
+package fakeprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	vaNumberPrefix = "8808"
+
+	accountStatusActive   = "active"
+	accountStatusInactive = "inactive"
+)
+
+type Server struct {
+	mu             sync.Mutex
+	apiKey         string
+	callbackSecret string
+	callbackURL    string
+	client         *http.Client
+
+	lastNumber    int64
+	accounts      map[string]*dtos.VirtualAccount // keyed by the va number
+	externalIDs   map[string]string               // external id to va number
+	callbacks     map[string][]byte               // callback id to the sent body
+	callbackOrder []string
+}
+
+func NewServer(apiKey, callbackSecret, callbackURL string) *Server {
+	return &Server{
+		apiKey:         apiKey,
+		callbackSecret: callbackSecret,
+		callbackURL:    callbackURL,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		accounts:       make(map[string]*dtos.VirtualAccount),
+		externalIDs:    make(map[string]string),
+		callbacks:      make(map[string][]byte),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid api key"})
+		return
+	}
+
+	paths := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && len(paths) == 1 && paths[0] == "virtual-accounts":
+		s.createVirtualAccount(w, r)
+	case r.Method == http.MethodGet && len(paths) == 2 && paths[0] == "virtual-accounts":
+		s.getVirtualAccount(w, paths[1])
+	case r.Method == http.MethodPost && len(paths) == 3 && paths[0] == "virtual-accounts" && paths[2] == "payments":
+		s.payVirtualAccount(w, r, paths[1])
+	case r.Method == http.MethodPost && len(paths) == 3 && paths[0] == "callbacks" && paths[2] == "resend":
+		s.resendCallback(w, paths[1])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func (s *Server) createVirtualAccount(w http.ResponseWriter, r *http.Request) {
+	var request dtos.CreateVirtualAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if request.ExternalID == "" || request.ExpectedAmount.IsNegative() {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "external_id is required and expected_amount should not be negative"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if vaNumber, ok := s.externalIDs[request.ExternalID]; ok {
+		writeJSON(w, http.StatusOK, s.accounts[vaNumber])
+		return
+	}
+
+	s.lastNumber++
+	account := &dtos.VirtualAccount{
+		ID:             uuid.NewString(),
+		ExternalID:     request.ExternalID,
+		BankCode:       request.BankCode,
+		VANumber:       fmt.Sprintf("%s%012d", vaNumberPrefix, s.lastNumber),
+		ExpectedAmount: request.ExpectedAmount,
+		IsSingleUse:    request.IsSingleUse,
+		Status:         accountStatusActive,
+	}
+	s.accounts[account.VANumber] = account
+	s.externalIDs[account.ExternalID] = account.VANumber
+	writeJSON(w, http.StatusOK, account)
+}
+
+func (s *Server) getVirtualAccount(w http.ResponseWriter, vaNumber string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[vaNumber]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "virtual account not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, account)
+}
+
+// payVirtualAccount behaves like a bank accepting the transfer, the transfer stands even when the callback fails
+func (s *Server) payVirtualAccount(w http.ResponseWriter, r *http.Request, vaNumber string) {
+	var request struct {
+		Amount decimal.Decimal `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	account, ok := s.accounts[vaNumber]
+	switch {
+	case !ok:
+		s.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "virtual account not found"})
+		return
+	case account.Status != accountStatusActive:
+		s.mu.Unlock()
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "virtual account is inactive"})
+		return
+	case !request.Amount.IsPositive():
+		s.mu.Unlock()
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "amount should be greater than 0"})
+		return
+	case account.ExpectedAmount.IsPositive() && !request.Amount.Equal(account.ExpectedAmount):
+		s.mu.Unlock()
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "amount should be " + account.ExpectedAmount.String()})
+		return
+	}
+
+	callback := dtos.VirtualAccountPaidCallback{
+		CallbackID:       uuid.NewString(),
+		VirtualAccountID: account.ID,
+		ExternalID:       account.ExternalID,
+		BankCode:         account.BankCode,
+		VANumber:         account.VANumber,
+		Amount:           request.Amount,
+		PaidAt:           time.Now().UnixMilli(),
+	}
+	if account.IsSingleUse {
+		account.Status = accountStatusInactive
+	}
+	body, err := json.Marshal(callback)
+	if err != nil {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	s.callbacks[callback.CallbackID] = body
+	s.callbackOrder = append(s.callbackOrder, callback.CallbackID)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.sendCallback(callback.CallbackID, body))
+}
+
+func (s *Server) resendCallback(w http.ResponseWriter, callbackID string) {
+	s.mu.Lock()
+	body, ok := s.callbacks[callbackID]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "callback not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.sendCallback(callbackID, body))
+}
+
+type callbackResult struct {
+	CallbackID string `json:"callback_id"`
+	StatusCode int    `json:"status_code"` // 0 when the callback url is unreachable
+	Error      string `json:"error,omitempty"`
+}
+
+// sendCallback signs "<timestamp>.<body>" with HMAC-SHA256, the receiver verifies it with the shared callback secret
+func (s *Server) sendCallback(callbackID string, body []byte) callbackResult {
+	result := callbackResult{CallbackID: callbackID}
+
+	req, err := http.NewRequest(http.MethodPost, s.callbackURL, bytes.NewReader(body))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Callback-Timestamp", timestamp)
+	req.Header.Set("X-Callback-Signature", "sha256="+utils.SignHMACSHA256(s.callbackSecret, append([]byte(timestamp+"."), body...)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Error = string(content)
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
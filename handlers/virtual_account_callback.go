@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxCallbackBodySize = 1 << 20
+)
+
+// VirtualAccountCallback receives the payment provider's paid callbacks. A non-2xx response makes the provider redeliver,
+// so a settled payment that can not be posted is still acknowledged once it is held in suspense
+func VirtualAccountCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	response, err := services.HandleVirtualAccountCallback(r.Context(), dtos.VirtualAccountCallbackParam{
+		Timestamp: r.Header.Get(clients.PaymentCallbackTimestampHeader),
+		Signature: r.Header.Get(clients.PaymentCallbackSignatureHeader),
+		Body:      body,
+	})
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, response)
+	case errors.Is(err, constants.ErrInvalidSignature):
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case errors.Is(err, constants.ErrInvalidValue):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		logrus.Errorf("failed to handle virtual account callback. %+v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
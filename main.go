@@ -2,9 +2,16 @@ package main
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"loan-payment/clients"
 	"loan-payment/configs"
 	"loan-payment/dtos"
+	"loan-payment/handlers"
 	"loan-payment/services"
 
 	"github.com/sirupsen/logrus"
@@ -12,24 +19,47 @@ import (
 
 func main() {
 	configs.Init("webservice")
+	if err := clients.InitEventPublisher(); err != nil {
+		logrus.Fatalf("failed to init event publisher. %+v", err)
+	}
+	if err := clients.InitNotificationSenders(); err != nil {
+		logrus.Fatalf("failed to init notification senders. %+v", err)
+	}
 
 	var (
 		ctx    = context.Background()
 		userID = int64(1)
 	)
 
-	loanID, err := services.CreateLoanRequest(ctx, dtos.CreateLoanRequestParam{
+	loanParam := dtos.CreateLoanRequestParam{
 		UserID:             userID,
 		LoanAmount:         "10000000",
 		TenureValue:        50,
 		TenureUnit:         2,
 		AnnualInterestRate: "10.75",
-	})
+	}
+
+	quote, err := services.GetLoanQuote(ctx, loanParam)
+	if err != nil {
+		logrus.Error(err)
+	} else {
+		logrus.Infof("quote total repayment: %s, effective apr: %s", quote.TotalRepaymentAmount.String(), quote.EffectiveAPR.String())
+	}
+
+	loanID, err := services.CreateLoanRequest(ctx, loanParam)
 	if err != nil {
 		logrus.Error(err)
 	}
 	logrus.Infof("loan_id: %d", loanID)
 
+	if err = services.ApproveLoanRequest(ctx, dtos.ApproveLoanRequestParam{LoanID: loanID, Actor: "admin"}); err != nil {
+		logrus.Error(err)
+	}
+
+	if err = services.DisburseLoanRequest(ctx, dtos.DisburseLoanRequestParam{LoanID: loanID, Actor: "admin"}); err != nil {
+		logrus.Error(err)
+	}
+
 	outstandingAmount, err := services.GetOutstanding(ctx, dtos.GetOutstandingParam{
 		UserID: userID,
 		LoanID: loanID,
@@ -52,4 +82,38 @@ func main() {
 	}); err != nil {
 		logrus.Error(err)
 	}
+
+	// pay it through the fake provider started with "go run ./cmd/fakeprovider":
+	// curl -X POST -H 'Authorization: Bearer local-api-key' localhost:8090/virtual-accounts/<va_number>/payments -d '{"amount":"221500"}'
+	virtualAccount, err := services.CreateVirtualAccount(ctx, dtos.CreateVirtualAccountParam{
+		UserID: userID,
+		LoanID: loanID,
+	})
+	if err != nil {
+		logrus.Error(err)
+	} else {
+		logrus.Infof("virtual account: %s %s", virtualAccount.BankCode, virtualAccount.VANumber)
+	}
+
+	http.HandleFunc("/callbacks/virtual-accounts", handlers.VirtualAccountCallback)
+	server := &http.Server{Addr: ":" + configs.Get().HttpPort}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		logrus.Error(err)
+	}
+	// closed last, it flushes the events still buffered by the broker client
+	if err = clients.CloseEventPublisher(); err != nil {
+		logrus.Error(err)
+	}
 }
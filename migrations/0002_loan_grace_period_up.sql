@@ -0,0 +1,3 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `grace_period_type` tinyint unsigned NOT NULL DEFAULT 0 AFTER `annual_interest_rate`,
+    ADD COLUMN `grace_period_value` int NOT NULL DEFAULT 0 AFTER `grace_period_type`;
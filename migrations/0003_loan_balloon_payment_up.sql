@@ -0,0 +1,2 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `balloon_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `grace_period_value`;
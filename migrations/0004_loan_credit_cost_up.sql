@@ -0,0 +1,3 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `effective_apr` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `balloon_amount`,
+    ADD COLUMN `total_cost_of_credit` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `effective_apr`;
@@ -0,0 +1,49 @@
+CREATE TABLE `loan_products_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `product_code` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `name` varchar(191) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `min_loan_amount` decimal(25, 2) NOT NULL,
+    `max_loan_amount` decimal(25, 2) NOT NULL,
+    `allowed_tenure_units` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `min_tenure_value` int NOT NULL,
+    `max_tenure_value` int NOT NULL,
+    `annual_interest_rate` decimal(25, 2) NOT NULL,
+    `amortization_method` tinyint unsigned NOT NULL,
+    `late_penalty_rate` decimal(25, 2) NOT NULL,
+    `late_penalty_grace_days` int NOT NULL,
+    `delinquent_overdue_billings` int NOT NULL,
+    `status` tinyint unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    `deleted_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_productcode` (`product_code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `loan_product_rates_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` bigint(20) unsigned NOT NULL,
+    `tenure_unit` tinyint unsigned NOT NULL,
+    `min_tenure_value` int NOT NULL,
+    `max_tenure_value` int NOT NULL,
+    `annual_interest_rate` decimal(25, 2) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_productid` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `loan_product_fees_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `product_id` bigint(20) unsigned NOT NULL,
+    `fee_type` tinyint unsigned NOT NULL,
+    `calculation_type` tinyint unsigned NOT NULL,
+    `value` decimal(25, 2) NOT NULL,
+    `charge_type` tinyint unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_productid` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `product_id` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `user_id`,
+    ADD COLUMN `amortization_method` tinyint unsigned NOT NULL DEFAULT 1 AFTER `annual_interest_rate`;
@@ -0,0 +1,5 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `penalty_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `interest_paid_amount`;
+
+ALTER TABLE `billings_tab`
+    ADD COLUMN `penalty_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `interest_amount`;
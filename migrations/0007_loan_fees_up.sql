@@ -0,0 +1,21 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `disbursed_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `loan_amount`,
+    ADD COLUMN `fee_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `penalty_paid_amount`;
+
+UPDATE `loan_requests_tab` SET `disbursed_amount` = `loan_amount`;
+
+ALTER TABLE `billings_tab`
+    ADD COLUMN `fee_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `penalty_amount`;
+
+CREATE TABLE `loan_fees_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `fee_type` tinyint unsigned NOT NULL,
+    `calculation_type` tinyint unsigned NOT NULL,
+    `value` decimal(25, 2) NOT NULL,
+    `amount` decimal(25, 2) NOT NULL,
+    `charge_type` tinyint unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
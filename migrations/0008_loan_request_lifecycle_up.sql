@@ -0,0 +1,10 @@
+CREATE TABLE `loan_request_installments_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `recurring_index` int NOT NULL,
+    `due_time` bigint(20) unsigned NOT NULL,
+    `principal_amount` decimal(25, 2) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_loanid_recurringindex` (`loan_id`, `recurring_index`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE `loan_request_histories_tab`
+    ADD COLUMN `actor` varchar(64) NOT NULL DEFAULT '' AFTER `status`,
+    ADD COLUMN `reason` varchar(255) NOT NULL DEFAULT '' AFTER `actor`;
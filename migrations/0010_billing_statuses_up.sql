@@ -0,0 +1,21 @@
+ALTER TABLE `billings_tab`
+    ADD COLUMN `principal_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `total_amount`,
+    ADD COLUMN `interest_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `principal_paid_amount`,
+    ADD COLUMN `penalty_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `interest_paid_amount`,
+    ADD COLUMN `fee_paid_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `penalty_paid_amount`;
+
+-- billings completed before the partial payment support are fully paid
+UPDATE `billings_tab`
+SET `principal_paid_amount` = `principal_amount`,
+    `interest_paid_amount` = `interest_amount`,
+    `penalty_paid_amount` = `penalty_amount`,
+    `fee_paid_amount` = `fee_amount`
+WHERE `status` = 2;
+
+ALTER TABLE `payments_tab`
+    ADD COLUMN `loan_id` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `user_id`,
+    ADD INDEX `idx_loanid` (`loan_id`);
+
+UPDATE `payments_tab` p
+    JOIN `billings_tab` b ON b.`payment_id` = p.`id`
+SET p.`loan_id` = b.`loan_id`;
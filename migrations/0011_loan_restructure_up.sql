@@ -0,0 +1,25 @@
+CREATE TABLE `loan_restructures_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `previous_tenure_value` int NOT NULL,
+    `previous_tenure_unit` tinyint unsigned NOT NULL,
+    `previous_annual_interest_rate` decimal(25, 2) NOT NULL,
+    `previous_amortization_method` tinyint unsigned NOT NULL,
+    `tenure_value` int NOT NULL,
+    `tenure_unit` tinyint unsigned NOT NULL,
+    `annual_interest_rate` decimal(25, 2) NOT NULL,
+    `amortization_method` tinyint unsigned NOT NULL,
+    `outstanding_principal_amount` decimal(25, 2) NOT NULL,
+    `capitalised_arrears_amount` decimal(25, 2) NOT NULL,
+    `first_recurring_index` int NOT NULL,
+    `cancelled_billing_count` int NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `reason` varchar(255) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `billings_tab`
+    ADD COLUMN `restructure_id` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `recurring_index`,
+    ADD COLUMN `capitalised_penalty_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `fee_paid_amount`;
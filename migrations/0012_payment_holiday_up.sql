@@ -0,0 +1,16 @@
+ALTER TABLE `billing_histories_tab`
+    ADD COLUMN `due_time` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `billing_id`;
+
+CREATE TABLE `loan_payment_holidays_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `holiday_code` varchar(64) NOT NULL,
+    `periods` int NOT NULL,
+    `holiday_interest_amount` decimal(25, 2) NOT NULL,
+    `reversed_penalty_amount` decimal(25, 2) NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `reason` varchar(255) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_loanid_holidaycode` (`loan_id`, `holiday_code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
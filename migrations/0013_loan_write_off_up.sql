@@ -0,0 +1,24 @@
+CREATE TABLE `loan_write_offs_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `principal_amount` decimal(25, 2) NOT NULL,
+    `interest_amount` decimal(25, 2) NOT NULL,
+    `penalty_amount` decimal(25, 2) NOT NULL,
+    `fee_amount` decimal(25, 2) NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `reason` varchar(255) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_loanid` (`loan_id`),
+    INDEX `idx_createdat` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `loan_recoveries_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `payment_id` bigint(20) unsigned NOT NULL,
+    `amount` decimal(25, 2) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,45 @@
+CREATE TABLE `journal_entries_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `entry_type` tinyint(3) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `reference_id` bigint(20) unsigned NOT NULL,
+    `description` varchar(255) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid` (`loan_id`),
+    INDEX `idx_createdat` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `journal_lines_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `journal_entry_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `account` tinyint(3) unsigned NOT NULL,
+    `debit_amount` decimal(25, 2) NOT NULL,
+    `credit_amount` decimal(25, 2) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_journalentryid` (`journal_entry_id`),
+    INDEX `idx_loanid_account` (`loan_id`, `account`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `suspense_items_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `source` varchar(32) NOT NULL,
+    `reference_id` varchar(128) NOT NULL,
+    `user_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `loan_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `amount` decimal(25, 2) NOT NULL,
+    `reason` varchar(1024) NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `payment_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `resolved_by` varchar(64) NOT NULL DEFAULT '',
+    `resolved_reason` varchar(255) NOT NULL DEFAULT '',
+    `resolved_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_source_referenceid` (`source`, `reference_id`),
+    INDEX `idx_status` (`status`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
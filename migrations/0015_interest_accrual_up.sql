@@ -0,0 +1,15 @@
+ALTER TABLE `billings_tab`
+    ADD COLUMN `interest_accrued_amount` decimal(25, 2) NOT NULL DEFAULT 0 AFTER `fee_paid_amount`;
+
+CREATE TABLE `interest_accruals_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `billing_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `accrual_date` bigint(20) unsigned NOT NULL,
+    `amount` decimal(25, 2) NOT NULL,
+    `source` tinyint(3) unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid_accrualdate` (`loan_id`, `accrual_date`),
+    INDEX `idx_billingid` (`billing_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,22 @@
+CREATE TABLE `outbox_events_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `event_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `event_type` varchar(64) NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `payload` text NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `attempts` int(10) unsigned NOT NULL DEFAULT 0,
+    `last_error` varchar(1024) NOT NULL DEFAULT '',
+    `claimed_by` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `claimed_until` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `published_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_eventid` (`event_id`),
+    INDEX `idx_status_id` (`status`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `billings_tab`
+    ADD COLUMN `due_event_at` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `payment_completed_at`,
+    ADD COLUMN `overdue_event_at` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `due_event_at`,
+    ADD INDEX `idx_status_duetime` (`status`, `due_time`);
@@ -0,0 +1,53 @@
+ALTER TABLE `loan_requests_tab`
+    ADD COLUMN `partner_id` bigint(20) unsigned NOT NULL DEFAULT 0 AFTER `product_id`;
+
+CREATE TABLE `webhook_subscriptions_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `partner_id` bigint(20) unsigned NOT NULL,
+    `url` varchar(2048) NOT NULL,
+    `event_types` varchar(512) NOT NULL,
+    `secret` varchar(256) NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    `deleted_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_partnerid_status` (`partner_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `webhook_deliveries_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `subscription_id` bigint(20) unsigned NOT NULL,
+    `event_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `event_type` varchar(64) NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `payload` text NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `attempts` int(10) unsigned NOT NULL DEFAULT 0,
+    `next_attempt_at` bigint(20) unsigned NOT NULL,
+    `last_response_code` int(10) unsigned NOT NULL DEFAULT 0,
+    `last_error` varchar(1024) NOT NULL DEFAULT '',
+    `delivered_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `replay_count` int(10) unsigned NOT NULL DEFAULT 0,
+    `replayed_attempts` int(10) unsigned NOT NULL DEFAULT 0,
+    `locked_until` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_subscriptionid_eventid` (`subscription_id`, `event_id`),
+    INDEX `idx_subscriptionid_status` (`subscription_id`, `status`),
+    INDEX `idx_status_nextattemptat` (`status`, `next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `webhook_delivery_replays_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `delivery_id` bigint(20) unsigned NOT NULL,
+    `previous_status` tinyint(3) unsigned NOT NULL,
+    `previous_attempts` int(10) unsigned NOT NULL,
+    `previous_response_code` int(10) unsigned NOT NULL,
+    `previous_error` varchar(1024) NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_deliveryid` (`delivery_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,26 @@
+ALTER TABLE `users_tab`
+    ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '' AFTER `name`,
+    ADD COLUMN `phone_number` varchar(32) NOT NULL DEFAULT '' AFTER `email`,
+    ADD COLUMN `push_token` varchar(255) NOT NULL DEFAULT '' AFTER `phone_number`,
+    ADD COLUMN `locale` varchar(8) NOT NULL DEFAULT '' AFTER `push_token`;
+
+CREATE TABLE `notifications_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `user_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `billing_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `due_time` bigint(20) unsigned NOT NULL,
+    `notification_type` varchar(32) NOT NULL,
+    `channel` varchar(16) NOT NULL,
+    `locale` varchar(8) NOT NULL,
+    `recipient` varchar(255) NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `attempts` int(10) unsigned NOT NULL DEFAULT 0,
+    `last_error` varchar(1024) NOT NULL DEFAULT '',
+    `sent_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_billingid_notificationtype_duetime_channel` (`billing_id`, `notification_type`, `due_time`, `channel`),
+    INDEX `idx_userid` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,50 @@
+CREATE TABLE `collection_cases_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `user_id` bigint(20) unsigned NOT NULL,
+    `status` tinyint(3) unsigned NOT NULL,
+    `bucket` tinyint(3) unsigned NOT NULL,
+    `days_past_due` int(10) unsigned NOT NULL,
+    `assignee_type` tinyint(3) unsigned NOT NULL DEFAULT 0,
+    `assignee_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `close_reason` tinyint(3) unsigned NOT NULL DEFAULT 0,
+    `opened_at` bigint(20) unsigned NOT NULL,
+    `closed_at` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_loanid_status` (`loan_id`, `status`),
+    INDEX `idx_assigneetype_assigneeid_status` (`assignee_type`, `assignee_id`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `collection_activities_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `case_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `activity_type` tinyint(3) unsigned NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `notes` varchar(1024) NOT NULL DEFAULT '',
+    `activity_time` bigint(20) unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_caseid_activitytime` (`case_id`, `activity_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `promises_to_pay_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `case_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `promised_amount` decimal(25, 2) NOT NULL,
+    `promised_due_time` bigint(20) unsigned NOT NULL,
+    `paid_amount` decimal(25, 2) NOT NULL DEFAULT 0,
+    `status` tinyint(3) unsigned NOT NULL,
+    `actor` varchar(64) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_caseid` (`case_id`),
+    INDEX `idx_status_id` (`status`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `payments_tab`
+    ADD INDEX `idx_loanid_createdat` (`loan_id`, `created_at`);
@@ -0,0 +1,37 @@
+CREATE TABLE `virtual_accounts_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `provider` varchar(32) NOT NULL,
+    `external_id` varchar(128) NOT NULL,
+    `bank_code` varchar(16) NOT NULL,
+    `va_number` varchar(32) NOT NULL,
+    `user_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `billing_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+    `expected_amount` decimal(25, 2) NOT NULL DEFAULT 0,
+    `status` tinyint(3) unsigned NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    `updated_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_provider_bankcode_vanumber` (`provider`, `bank_code`, `va_number`),
+    INDEX `idx_provider_loanid_status` (`provider`, `loan_id`, `status`),
+    INDEX `idx_provider_externalid` (`provider`, `external_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `virtual_account_payments_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `provider` varchar(32) NOT NULL,
+    `callback_id` varchar(128) NOT NULL,
+    `virtual_account_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `loan_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `amount` decimal(25, 2) NOT NULL,
+    `paid_at` bigint(20) unsigned NOT NULL,
+    `payment_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `suspense_item_id` bigint(20) unsigned NOT NULL DEFAULT 0,
+    `status` tinyint(3) unsigned NOT NULL,
+    `suspense_reason` varchar(1024) NOT NULL DEFAULT '',
+    `payload` text NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_provider_callbackid` (`provider`, `callback_id`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,27 @@
+CREATE TABLE `ecl_runs_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `as_of_date` bigint(20) unsigned NOT NULL,
+    `loan_count` int(10) unsigned NOT NULL,
+    `total_exposure_amount` decimal(25, 2) NOT NULL,
+    `total_ecl_amount` decimal(25, 2) NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_asofdate` (`as_of_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `ecl_run_loans_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `run_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `user_id` bigint(20) unsigned NOT NULL,
+    `product_code` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `days_past_due` int(10) unsigned NOT NULL,
+    `is_restructured` tinyint(1) NOT NULL,
+    `stage` tinyint(3) unsigned NOT NULL,
+    `exposure_amount` decimal(25, 2) NOT NULL,
+    `pd` decimal(25, 4) NOT NULL,
+    `lgd` decimal(25, 4) NOT NULL,
+    `ecl_amount` decimal(25, 2) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_runid_loanid` (`run_id`, `loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
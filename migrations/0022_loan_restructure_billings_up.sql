@@ -0,0 +1,11 @@
+CREATE TABLE `loan_restructure_billings_tab` (
+    `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `restructure_id` bigint(20) unsigned NOT NULL,
+    `loan_id` bigint(20) unsigned NOT NULL,
+    `billing_id` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+    `created_at` bigint(20) unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `uniq_idx_billingid` (`billing_id`),
+    INDEX `idx_restructureid` (`restructure_id`),
+    INDEX `idx_loanid` (`loan_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 DEFAULT COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AccrueInterest recognises the interest earned by every loan up to the end of the date.
+// Each billing accrues up to its earned interest so far, so rerunning a date or catching up a missed one is safe
+func AccrueInterest(ctx context.Context, param dtos.AccrueInterestParam) error {
+	accrualDate := utils.GetStartOfDay(time.Now()).AddDate(0, 0, -1)
+	if param.Date != "" {
+		date, err := time.ParseInLocation(constants.DateLayout, param.Date, time.Local)
+		if err != nil {
+			return fmt.Errorf("%w. date should be in YYYY-MM-DD format", constants.ErrInvalidValue)
+		}
+		if date.After(accrualDate) {
+			return fmt.Errorf("%w. only a past date can be accrued", constants.ErrInvalidValue)
+		}
+		accrualDate = date
+	}
+
+	var lastLoanID int64
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(loanRequestModels) == 0 {
+			return nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			if err = accrueLoanInterest(ctx, loanRequestModel, accrualDate); err != nil {
+				logrus.Errorf("failed to accrue interest of loan %d. %+v", loanRequestModel.ID, err)
+			}
+		}
+	}
+}
+
+func accrueLoanInterest(ctx context.Context, loanRequestModel dtos.LoanRequestModel, accrualDate time.Time) error {
+	restructureModels, err := clients.DBGetLoanRestructuresByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with payment with pessimistic lock
+	lockedLoanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+	if lockedLoanRequestModel.Status != constants.LoanStatus_InRepayment && lockedLoanRequestModel.Status != constants.LoanStatus_Defaulted {
+		return nil
+	}
+
+	billingModels, err := clients.DBGetBillingsByLoanIDForUpdate(ctx, txn, lockedLoanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+	endOfAccrualDate := accrualDate.AddDate(0, 0, 1)
+	if isNonAccrualLoan(billingModels, endOfAccrualDate) {
+		logrus.Infof("loan %d is non-accrual, its interest is recognised once paid", lockedLoanRequestModel.ID)
+		return nil
+	}
+
+	var (
+		now = time.Now().UnixMilli()
+
+		periodStartTimes = getBillingPeriodStartTimes(*lockedLoanRequestModel, billingModels, restructureModels)
+		accrualModels    []dtos.InterestAccrualModel
+		journalEntry     = newJournalEntry(constants.JournalEntryType_InterestAccrual, lockedLoanRequestModel.ID, lockedLoanRequestModel.ID, "daily interest accrual")
+	)
+	for _, billing := range billingModels {
+		if !isAccruableBilling(billing) {
+			continue
+		}
+
+		// the accrual is never reversed here, e.g. when a payment holiday stretches the period, it resumes once it is earned again
+		amount := getEarnedInterestAmount(billing, periodStartTimes[billing.ID], accrualDate).Sub(billing.InterestAccruedAmount)
+		if !amount.IsPositive() {
+			continue
+		}
+
+		if err = clients.DBUpdateBillingInterestAccruedByID(ctx, txn, billing.ID, billing.InterestAccruedAmount.Add(amount)); err != nil {
+			return err
+		}
+		accrualModels = append(accrualModels, dtos.InterestAccrualModel{
+			LoanID:      lockedLoanRequestModel.ID,
+			BillingID:   billing.BillingID,
+			AccrualDate: accrualDate.UnixMilli(),
+			Amount:      amount,
+			Source:      constants.InterestAccrualSource_Daily,
+			CreatedAt:   now,
+		})
+		journalEntry.transfer(constants.LedgerAccount_UnearnedInterest, constants.LedgerAccount_InterestIncome, amount)
+	}
+	if len(accrualModels) == 0 {
+		return nil
+	}
+
+	if err = clients.DBBatchInsertInterestAccruals(ctx, txn, accrualModels); err != nil {
+		return err
+	}
+
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
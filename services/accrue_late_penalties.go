@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	loanBatchSize = 100
+)
+
+// AccrueLatePenalties recalculates the late penalty of every overdue billing following its loan product.
+// the penalty is recalculated from the due time on every run, so it is safe to rerun
+func AccrueLatePenalties(ctx context.Context) error {
+	var (
+		lastLoanID int64
+
+		products = make(map[int64]*loanProduct)
+	)
+
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(loanRequestModels) == 0 {
+			return nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			if loanRequestModel.ProductID == 0 {
+				continue
+			}
+
+			product, ok := products[loanRequestModel.ProductID]
+			if !ok {
+				if product, err = getLoanProductByID(ctx, loanRequestModel.ProductID); err != nil {
+					return err
+				}
+				products[loanRequestModel.ProductID] = product
+			}
+
+			if err = accrueLoanLatePenalties(ctx, loanRequestModel, product); err != nil {
+				logrus.Errorf("failed to accrue late penalties of loan %d. %+v", loanRequestModel.ID, err)
+			}
+		}
+	}
+}
+
+func accrueLoanLatePenalties(ctx context.Context, loanRequestModel dtos.LoanRequestModel, product *loanProduct) error {
+	if !product.LatePenaltyRate.IsPositive() {
+		return nil
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with payment with pessimistic lock
+	lockedLoanRequestModel, err := clients.DBGetLoanRequestByIDAndUserIDForUpdate(ctx, txn, loanRequestModel.ID, loanRequestModel.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	overdueBillings, err := clients.DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx, txn, lockedLoanRequestModel.ID, now.UnixMilli())
+	if err != nil {
+		return err
+	}
+	sort.Slice(overdueBillings, func(i, j int) bool {
+		return overdueBillings[i].RecurringIndex < overdueBillings[j].RecurringIndex
+	})
+
+	// penalties that are already paid on closed billings count towards the cap,
+	// penalties of the open billings are recomputed below
+	penaltyCapAmount, isCapped := getTotalPenaltyCapAmount(*lockedLoanRequestModel)
+	remainingPenaltyCapAmount := penaltyCapAmount.Sub(lockedLoanRequestModel.PenaltyPaidAmount)
+	for _, billing := range overdueBillings {
+		remainingPenaltyCapAmount = remainingPenaltyCapAmount.Add(billing.PenaltyPaidAmount)
+	}
+
+	journalEntry := newJournalEntry(constants.JournalEntryType_Penalty, lockedLoanRequestModel.ID, lockedLoanRequestModel.ID, "late penalty accrual")
+	for _, billing := range overdueBillings {
+		overdueDays := int64(now.Sub(time.UnixMilli(billing.DueTime)).Hours()/24) - int64(product.LatePenaltyGraceDays)
+		if overdueDays <= 0 {
+			continue
+		}
+
+		billedAmount := billing.PrincipalAmount.Add(billing.InterestAmount)
+		penaltyAmount := billedAmount.
+			Mul(product.LatePenaltyRate).
+			Div(constants.Percent).
+			Mul(decimal.NewFromInt(overdueDays)).
+			Round(constants.AmountDecimalPlaces)
+		if isCapped {
+			cappedPenaltyAmount := decimal.Max(decimal.Zero, decimal.Min(penaltyAmount, remainingPenaltyCapAmount.Sub(billing.CapitalisedPenaltyAmount)))
+			if cappedPenaltyAmount.LessThan(penaltyAmount) {
+				logrus.Warnf("late penalty of billing %s is capped at %s", billing.BillingID, cappedPenaltyAmount.String())
+			}
+			penaltyAmount = cappedPenaltyAmount
+		}
+		// a paid or capitalised penalty is never taken back
+		penaltyAmount = decimal.Max(penaltyAmount.Add(billing.CapitalisedPenaltyAmount), billing.PenaltyPaidAmount)
+		if isCapped {
+			remainingPenaltyCapAmount = remainingPenaltyCapAmount.Sub(penaltyAmount)
+		}
+
+		if penaltyAmount.Equal(billing.PenaltyAmount) {
+			continue
+		}
+		totalAmount := billedAmount.Add(billing.FeeAmount).Add(penaltyAmount)
+		if err = clients.DBUpdateBillingPenaltyByID(ctx, txn, billing.ID, penaltyAmount, totalAmount); err != nil {
+			return err
+		}
+		journalEntry.transfer(constants.LedgerAccount_PenaltyReceivable, constants.LedgerAccount_PenaltyIncome, penaltyAmount.Sub(billing.PenaltyAmount))
+	}
+
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// AmendLoanRequestInstallments replaces the custom installments of a loan request that is not disbursed yet, e.g. when
+// the disbursement is late and the first due dates have passed. The installments keep their count and principal, only
+// the due dates move, so the loan keeps its approval. The schedule is simulated again against the caps from now
+func AmendLoanRequestInstallments(ctx context.Context, param dtos.AmendLoanRequestInstallmentsParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, param.LoanID)
+	if err != nil {
+		return err
+	}
+	if loanRequestModel.Status != constants.LoanStatus_Submitted && loanRequestModel.Status != constants.LoanStatus_Approved {
+		return fmt.Errorf("%w. only submitted or approved loan request can be amended", constants.ErrInvalidValue)
+	}
+
+	installmentModels, err := clients.DBGetLoanRequestInstallmentsByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+	if len(installmentModels) == 0 {
+		return fmt.Errorf("%w. loan request has no custom installments", constants.ErrInvalidValue)
+	}
+
+	feeModels, err := clients.DBGetLoanFeesByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	// the installments were submitted against the requested amount, without the fees added to principal
+	requestedAmount := loanRequestModel.LoanAmount.Sub(getLoanFeeAmount(feeModels, constants.FeeChargeType_AddedToPrincipal))
+	if err = validateCustomInstallments(param.Installments, loanRequestModel.TenureValue, requestedAmount, now); err != nil {
+		return err
+	}
+	for i, installment := range param.Installments {
+		principalAmount, _ := decimal.NewFromString(installment.PrincipalAmount)
+		if !principalAmount.Equal(installmentModels[i].PrincipalAmount) {
+			return fmt.Errorf("%w. installments' principal_amount should stay as submitted", constants.ErrInvalidValue)
+		}
+	}
+
+	loanRequestModel.DisbursementTime = now.UnixMilli()
+	if _, _, err = scheduleLoanRequest(loanRequestModel, feeModels, param.Installments); err != nil {
+		return err
+	}
+	if err = clients.DBUpdateLoanRequestCreditCostByID(ctx, txn, loanRequestModel); err != nil {
+		return err
+	}
+
+	if err = clients.DBDeleteLoanRequestInstallmentsByLoanID(ctx, txn, loanRequestModel.ID); err != nil {
+		return err
+	}
+	if err = clients.DBBatchInsertLoanRequestInstallments(ctx, txn, newLoanRequestInstallmentModels(loanRequestModel.ID, param.Installments, now.UnixMilli())); err != nil {
+		return err
+	}
+
+	// not a status transition, only recorded for audit
+	if err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
+		{
+			LoanID:              loanRequestModel.ID,
+			PrincipalPaidAmount: decimal.Zero,
+			InterestPaidAmount:  decimal.Zero,
+			Status:              loanRequestModel.Status,
+			Actor:               param.Actor,
+			Reason:              param.Reason,
+			CreatedAt:           now.UnixMilli(),
+		},
+	}); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// ApplyPaymentHoliday shifts the due time of the open billings of every targeted loan by the given periods.
+// each loan gets the holiday code at most once, so a rerun only applies it to the loans that were not done yet
+func ApplyPaymentHoliday(ctx context.Context, param dtos.PaymentHolidayParam) (*dtos.PaymentHolidayResponse, error) {
+	return runPaymentHoliday(ctx, param, func(ctx context.Context, loanRequestModel dtos.LoanRequestModel) (*dtos.PaymentHolidayLoan, error) {
+		return applyLoanPaymentHoliday(ctx, loanRequestModel.ID, param)
+	})
+}
+
+func applyLoanPaymentHoliday(ctx context.Context, loanID int64, param dtos.PaymentHolidayParam) (*dtos.PaymentHolidayLoan, error) {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with payment with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the loan lock serialises concurrent runs of the same holiday
+	if _, err = clients.DBGetLoanPaymentHoliday(ctx, txn, loanID, param.HolidayCode); err == nil {
+		return nil, errPaymentHolidayApplied
+	} else if err != constants.ErrRecordNotFound {
+		return nil, err
+	}
+
+	openBillings, err := clients.DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx, txn, loanID, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	loan, shiftedBillings, err := calculatePaymentHoliday(*loanRequestModel, openBillings, param, now)
+	if err != nil {
+		return nil, err
+	}
+
+	billingHistories := make([]dtos.BillingHistoryModel, 0, len(shiftedBillings))
+	for i := range shiftedBillings {
+		billing := &shiftedBillings[i]
+		if err = clients.DBUpdateBillingScheduleByID(ctx, txn, billing); err != nil {
+			return nil, err
+		}
+
+		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
+			BillingID:          billing.BillingID,
+			DueTime:            billing.DueTime,
+			PaymentCompletedAt: billing.PaymentCompletedAt,
+			Status:             billing.Status,
+			CreatedAt:          now,
+		})
+	}
+
+	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+		return nil, err
+	}
+
+	journalEntry := newJournalEntry(constants.JournalEntryType_PaymentHoliday, loanRequestModel.ID, loanRequestModel.ID, param.Reason).
+		transfer(constants.LedgerAccount_InterestReceivable, constants.LedgerAccount_UnearnedInterest, loan.HolidayInterestAmount).
+		transfer(constants.LedgerAccount_PenaltyIncome, constants.LedgerAccount_PenaltyReceivable, loan.ReversedPenaltyAmount)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return nil, err
+	}
+
+	if err = clients.DBInsertLoanPaymentHoliday(ctx, txn, &dtos.LoanPaymentHolidayModel{
+		LoanID:                loanRequestModel.ID,
+		HolidayCode:           param.HolidayCode,
+		Periods:               param.Periods,
+		HolidayInterestAmount: loan.HolidayInterestAmount,
+		ReversedPenaltyAmount: loan.ReversedPenaltyAmount,
+		Actor:                 param.Actor,
+		Reason:                param.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	// not a status transition, only recorded for audit
+	if err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
+		{
+			LoanID:              loanRequestModel.ID,
+			PrincipalPaidAmount: decimal.Zero,
+			InterestPaidAmount:  decimal.Zero,
+			Status:              loanRequestModel.Status,
+			Actor:               param.Actor,
+			Reason:              param.Reason,
+			CreatedAt:           now,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
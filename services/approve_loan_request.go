@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+func ApproveLoanRequest(ctx context.Context, param dtos.ApproveLoanRequestParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	return updateLoanRequestStatus(ctx, param.LoanID, 0, constants.LoanStatus_Approved, param.Actor, param.Reason)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// AssignCollectionCase hands an open case to an in-house agent or a collection agency, replacing the previous assignee
+func AssignCollectionCase(ctx context.Context, param dtos.AssignCollectionCaseParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if !constants.CollectionAssigneeType(param.AssigneeType).IsValid() {
+		return fmt.Errorf("%w. assignee_type", constants.ErrInvalidValue)
+	}
+	if param.AssigneeID < 1 {
+		return fmt.Errorf("%w. assignee_id", constants.ErrInvalidValue)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	caseModel, err := getOpenCollectionCaseForUpdate(ctx, txn, param.CaseID)
+	if err != nil {
+		return err
+	}
+
+	if err = clients.DBUpdateCollectionCaseAssigneeByID(ctx, txn, caseModel.ID, constants.CollectionAssigneeType(param.AssigneeType), param.AssigneeID); err != nil {
+		return err
+	}
+	if err = clients.DBBatchInsertCollectionActivities(ctx, txn, []dtos.CollectionActivityModel{
+		{
+			CaseID:       caseModel.ID,
+			LoanID:       caseModel.LoanID,
+			ActivityType: constants.CollectionActivityType_Assigned,
+			Actor:        param.Actor,
+			Notes:        fmt.Sprintf("assignee type %d, id %d", param.AssigneeType, param.AssigneeID),
+		},
+	}); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// CancelLoanRequest lets the borrower withdraw the loan request as long as it is not disbursed yet
+func CancelLoanRequest(ctx context.Context, param dtos.CancelLoanRequestParam) error {
+	if _, err := clients.DBGetUserByID(ctx, param.UserID); err != nil {
+		return err
+	}
+	return updateLoanRequestStatus(ctx, param.LoanID, param.UserID, constants.LoanStatus_Cancelled, constants.LoanActor_Borrower, param.Reason)
+}
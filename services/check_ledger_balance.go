@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// CheckLedgerBalance verifies the ledger invariant, the debits equal the credits overall and within every journal entry
+func CheckLedgerBalance(ctx context.Context) (*dtos.LedgerCheckResponse, error) {
+	balanceModels, err := clients.DBGetLedgerAccountBalances(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	unbalancedJournalEntryIDs, err := clients.DBGetUnbalancedJournalEntryIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.LedgerCheckResponse{
+		TotalDebitAmount:          decimal.Zero,
+		TotalCreditAmount:         decimal.Zero,
+		UnbalancedJournalEntryIDs: make([]int64, 0, len(unbalancedJournalEntryIDs)),
+	}
+	for _, balanceModel := range balanceModels {
+		response.TotalDebitAmount = response.TotalDebitAmount.Add(balanceModel.DebitAmount)
+		response.TotalCreditAmount = response.TotalCreditAmount.Add(balanceModel.CreditAmount)
+	}
+	response.UnbalancedJournalEntryIDs = append(response.UnbalancedJournalEntryIDs, unbalancedJournalEntryIDs...)
+	response.IsBalanced = response.TotalDebitAmount.Equal(response.TotalCreditAmount) && len(unbalancedJournalEntryIDs) == 0
+	return &response, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// CheckPromisesToPay sums the payments made since each pending promise was created. The promise is kept once the sum
+// reaches the promised amount by the promised date, and broken when the date passes without it
+func CheckPromisesToPay(ctx context.Context) (*dtos.CheckPromisesToPayResponse, error) {
+	var (
+		lastPromiseID int64
+		response      dtos.CheckPromisesToPayResponse
+	)
+
+	for {
+		promiseModels, err := clients.DBGetPendingPromisesToPay(ctx, lastPromiseID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(promiseModels) == 0 {
+			return &response, nil
+		}
+
+		for _, promise := range promiseModels {
+			lastPromiseID = promise.ID
+
+			now := time.Now().UnixMilli()
+			paidAmount, err := clients.DBGetPaymentAmountByLoanID(ctx, promise.LoanID, promise.CreatedAt, promise.PromisedDueTime)
+			if err != nil {
+				return nil, err
+			}
+
+			status := constants.PromiseToPayStatus_Pending
+			switch {
+			case paidAmount.GreaterThanOrEqual(promise.PromisedAmount):
+				status = constants.PromiseToPayStatus_Kept
+				response.KeptCount++
+			case now >= promise.PromisedDueTime:
+				status = constants.PromiseToPayStatus_Broken
+				response.BrokenCount++
+			case paidAmount.Equal(promise.PaidAmount):
+				continue
+			}
+
+			if err = clients.DBUpdatePromiseToPayResultByID(ctx, nil, promise.ID, paidAmount, status); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
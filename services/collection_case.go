@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func getCollectionBucket(daysPastDue int) constants.CollectionBucket {
+	switch {
+	case daysPastDue > 90:
+		return constants.CollectionBucket_DPD91Plus
+	case daysPastDue > 60:
+		return constants.CollectionBucket_DPD61To90
+	case daysPastDue > 30:
+		return constants.CollectionBucket_DPD31To60
+	}
+	return constants.CollectionBucket_DPD1To30
+}
+
+// closeLoanCollectionCase closes the loan's open case if any, within the transaction closing the loan
+func closeLoanCollectionCase(ctx context.Context, tx *sqlx.Tx, loanID int64, reason constants.CollectionCaseCloseReason, actor string) error {
+	caseModel, err := clients.DBGetOpenCollectionCaseByLoanIDForUpdate(ctx, tx, loanID)
+	if err == constants.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return closeCollectionCase(ctx, tx, caseModel, reason, actor)
+}
+
+func closeCollectionCase(ctx context.Context, tx *sqlx.Tx, caseModel *dtos.CollectionCaseModel, reason constants.CollectionCaseCloseReason, actor string) error {
+	if err := clients.DBCloseCollectionCaseByID(ctx, tx, caseModel.ID, reason); err != nil {
+		return err
+	}
+	return clients.DBBatchInsertCollectionActivities(ctx, tx, []dtos.CollectionActivityModel{
+		{
+			CaseID:       caseModel.ID,
+			LoanID:       caseModel.LoanID,
+			ActivityType: constants.CollectionActivityType_Closed,
+			Actor:        actor,
+			Notes:        fmt.Sprintf("closed with reason %d", reason),
+		},
+	})
+}
+
+// getOpenCollectionCaseForUpdate locks the case for a collector's action, which is only allowed while the case is open
+func getOpenCollectionCaseForUpdate(ctx context.Context, tx *sqlx.Tx, caseID int64) (*dtos.CollectionCaseModel, error) {
+	caseModel, err := clients.DBGetCollectionCaseByIDForUpdate(ctx, tx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if caseModel.Status != constants.CollectionCaseStatus_Open {
+		return nil, fmt.Errorf("%w. collection case is closed", constants.ErrInvalidValue)
+	}
+	return caseModel, nil
+}
+
+func newCollectionCaseResponse(caseModel dtos.CollectionCaseModel) dtos.CollectionCaseResponse {
+	return dtos.CollectionCaseResponse{
+		CaseID:       caseModel.ID,
+		LoanID:       caseModel.LoanID,
+		UserID:       caseModel.UserID,
+		Status:       int8(caseModel.Status),
+		Bucket:       int8(caseModel.Bucket),
+		DaysPastDue:  caseModel.DaysPastDue,
+		AssigneeType: int8(caseModel.AssigneeType),
+		AssigneeID:   caseModel.AssigneeID,
+		CloseReason:  int8(caseModel.CloseReason),
+		OpenedAt:     caseModel.OpenedAt,
+		ClosedAt:     caseModel.ClosedAt,
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+func CreateLoanProduct(ctx context.Context, param dtos.CreateLoanProductParam) (int64, error) {
+	if err := validateLoanProduct(ctx, param); err != nil {
+		return 0, err
+	}
+
+	var (
+		now                = time.Now().UnixMilli()
+		allowedTenureUnits = make([]string, 0, len(param.AllowedTenureUnits))
+
+		minLoanAmount, _      = decimal.NewFromString(param.MinLoanAmount)
+		maxLoanAmount, _      = decimal.NewFromString(param.MaxLoanAmount)
+		annualInterestRate, _ = decimal.NewFromString(param.AnnualInterestRate)
+		latePenaltyRate, _    = decimal.NewFromString(param.LatePenaltyRate)
+	)
+	for _, tenureUnit := range param.AllowedTenureUnits {
+		allowedTenureUnits = append(allowedTenureUnits, strconv.Itoa(int(tenureUnit)))
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	productID, err := clients.DBInsertLoanProduct(ctx, txn, &dtos.LoanProductModel{
+		ProductCode:               param.ProductCode,
+		Name:                      param.Name,
+		MinLoanAmount:             minLoanAmount,
+		MaxLoanAmount:             maxLoanAmount,
+		AllowedTenureUnits:        strings.Join(allowedTenureUnits, ","),
+		MinTenureValue:            param.MinTenureValue,
+		MaxTenureValue:            param.MaxTenureValue,
+		AnnualInterestRate:        annualInterestRate,
+		AmortizationMethod:        constants.AmortizationMethod(param.AmortizationMethod),
+		LatePenaltyRate:           latePenaltyRate,
+		LatePenaltyGraceDays:      param.LatePenaltyGraceDays,
+		DelinquentOverdueBillings: param.DelinquentOverdueBillings,
+		Status:                    constants.LoanProductStatus_Active,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(param.Rates) > 0 {
+		rateModels := make([]dtos.LoanProductRateModel, 0, len(param.Rates))
+		for _, rate := range param.Rates {
+			rateAnnualInterestRate, _ := decimal.NewFromString(rate.AnnualInterestRate)
+			rateModels = append(rateModels, dtos.LoanProductRateModel{
+				ProductID:          productID,
+				TenureUnit:         constants.TenureUnit(rate.TenureUnit),
+				MinTenureValue:     rate.MinTenureValue,
+				MaxTenureValue:     rate.MaxTenureValue,
+				AnnualInterestRate: rateAnnualInterestRate,
+				CreatedAt:          now,
+			})
+		}
+		if err = clients.DBBatchInsertLoanProductRates(ctx, txn, rateModels); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(param.Fees) > 0 {
+		feeModels := make([]dtos.LoanProductFeeModel, 0, len(param.Fees))
+		for _, fee := range param.Fees {
+			feeValue, _ := decimal.NewFromString(fee.Value)
+			feeModels = append(feeModels, dtos.LoanProductFeeModel{
+				ProductID:       productID,
+				FeeType:         constants.FeeType(fee.FeeType),
+				CalculationType: constants.FeeCalculationType(fee.CalculationType),
+				Value:           feeValue,
+				ChargeType:      constants.FeeChargeType(fee.ChargeType),
+				CreatedAt:       now,
+			})
+		}
+		if err = clients.DBBatchInsertLoanProductFees(ctx, txn, feeModels); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return 0, err
+	}
+	return productID, nil
+}
+
+func validateLoanProduct(ctx context.Context, param dtos.CreateLoanProductParam) error {
+	if param.ProductCode == "" {
+		return fmt.Errorf("%w. product_code should not be empty", constants.ErrInvalidValue)
+	}
+	if _, err := clients.DBGetLoanProductByCode(ctx, param.ProductCode); err == nil {
+		return fmt.Errorf("%w. product_code already exists", constants.ErrInvalidValue)
+	} else if err != constants.ErrRecordNotFound {
+		return err
+	}
+
+	minLoanAmount, err := decimal.NewFromString(param.MinLoanAmount)
+	if err != nil {
+		return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+	}
+	maxLoanAmount, err := decimal.NewFromString(param.MaxLoanAmount)
+	if err != nil {
+		return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+	}
+	if minLoanAmount.LessThan(decimal.NewFromInt(1)) || maxLoanAmount.LessThan(minLoanAmount) {
+		return fmt.Errorf("%w. min_loan_amount should be greater than 0 and not greater than max_loan_amount", constants.ErrInvalidValue)
+	}
+
+	if len(param.AllowedTenureUnits) == 0 {
+		return fmt.Errorf("%w. allowed_tenure_units should not be empty", constants.ErrInvalidValue)
+	}
+	for _, tenureUnit := range param.AllowedTenureUnits {
+		if !constants.TenureUnit(tenureUnit).IsValid() {
+			return fmt.Errorf("%w. allowed_tenure_units", constants.ErrInvalidValue)
+		}
+	}
+	if param.MinTenureValue < 1 || param.MaxTenureValue < param.MinTenureValue {
+		return fmt.Errorf("%w. min_tenure_value should be greater than 0 and not greater than max_tenure_value", constants.ErrInvalidValue)
+	}
+
+	if err = validateNonNegativeDecimal("annual_interest_rate", param.AnnualInterestRate); err != nil {
+		return err
+	}
+	if !constants.AmortizationMethod(param.AmortizationMethod).IsValid() {
+		return fmt.Errorf("%w. amortization_method", constants.ErrInvalidValue)
+	}
+
+	if err = validateNonNegativeDecimal("late_penalty_rate", param.LatePenaltyRate); err != nil {
+		return err
+	}
+	if param.LatePenaltyGraceDays < 0 {
+		return fmt.Errorf("%w. late_penalty_grace_days should be greater or equals to 0", constants.ErrInvalidValue)
+	}
+	if param.DelinquentOverdueBillings < 0 {
+		return fmt.Errorf("%w. delinquent_overdue_billings should be greater or equals to 0", constants.ErrInvalidValue)
+	}
+
+	for _, rate := range param.Rates {
+		if !constants.TenureUnit(rate.TenureUnit).IsValid() {
+			return fmt.Errorf("%w. rates' tenure_unit", constants.ErrInvalidValue)
+		}
+		if rate.MinTenureValue < 1 || rate.MaxTenureValue < rate.MinTenureValue {
+			return fmt.Errorf("%w. rates' min_tenure_value should be greater than 0 and not greater than max_tenure_value", constants.ErrInvalidValue)
+		}
+		if err = validateNonNegativeDecimal("rates' annual_interest_rate", rate.AnnualInterestRate); err != nil {
+			return err
+		}
+	}
+
+	for _, fee := range param.Fees {
+		if err = validateFee(fee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFee(fee dtos.FeeParam) error {
+	if !constants.FeeType(fee.FeeType).IsValid() {
+		return fmt.Errorf("%w. fees' fee_type", constants.ErrInvalidValue)
+	}
+	if !constants.FeeCalculationType(fee.CalculationType).IsValid() {
+		return fmt.Errorf("%w. fees' calculation_type", constants.ErrInvalidValue)
+	}
+	if !constants.FeeChargeType(fee.ChargeType).IsValid() {
+		return fmt.Errorf("%w. fees' charge_type", constants.ErrInvalidValue)
+	}
+	return validateNonNegativeDecimal("fees' value", fee.Value)
+}
+
+func validateNonNegativeDecimal(field, value string) error {
+	number, err := decimal.NewFromString(value)
+	if err != nil {
+		return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+	}
+	if number.IsNegative() {
+		return fmt.Errorf("%w. %s should be greater or equals to 0", constants.ErrInvalidValue, field)
+	}
+	return nil
+}
@@ -15,13 +15,16 @@ import (
 )
 
 func CreateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam) (int64, error) {
-	if err := validateLoanRequest(ctx, param); err != nil {
+	product, err := getLoanRequestProduct(ctx, param.ProductCode)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = validateLoanRequest(ctx, param, product); err != nil {
 		return 0, err
 	}
 
 	now := time.Now().UnixMilli()
-	loanAmount, _ := decimal.NewFromString(param.LoanAmount)
-	annualInterestRate, _ := decimal.NewFromString(param.AnnualInterestRate)
 
 	txn, err := clients.DBBeginTransaction(ctx)
 	if err != nil {
@@ -29,29 +32,34 @@ func CreateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam) (
 	}
 	defer clients.DBRollbackTransaction(txn)
 
-	var loanModel = dtos.LoanRequestModel{
-		UserID:              param.UserID,
-		LoanAmount:          loanAmount,
-		PrincipalPaidAmount: decimal.NewFromUint64(0),
-		InterestPaidAmount:  decimal.NewFromUint64(0),
-		DisbursementTime:    now, // assume that all loan request is disbursed that day
-		TenureValue:         param.TenureValue,
-		TenureUnit:          constants.TenureUnit(param.TenureUnit),
-		Status:              constants.LoanStatus_InRepayment,
-		AnnualInterestRate:  annualInterestRate,
+	feeModels := calculateLoanFees(param, product, now)
+	loanModel := newLoanRequestModel(param, product, feeModels, now)
+
+	// the schedule is simulated from the submission time to reject loans breaching the caps early, the simulated
+	// credit cost is kept as disclosed to the borrower. the actual schedule and credit cost are generated on disbursement
+	if _, _, err = scheduleLoanRequest(&loanModel, feeModels, param.Installments); err != nil {
+		return 0, err
 	}
+	loanModel.DisbursementTime = 0
+
 	loanID, err := clients.DBInsertLoanRequest(ctx, txn, &loanModel)
 	if err != nil {
 		return 0, err
 	}
-	loanModel.ID = loanID
+	for i := range feeModels {
+		feeModels[i].LoanID = loanID
+	}
 
-	billingModels, billingHistories, err := createRepaymentSchedule(loanModel)
-	if err != nil {
-		return 0, err
+	if len(feeModels) > 0 {
+		if err = clients.DBBatchInsertLoanFees(ctx, txn, feeModels); err != nil {
+			return 0, err
+		}
 	}
-	if err = clients.DBBatchInsertBillings(ctx, txn, billingModels); err != nil {
-		return 0, err
+
+	if len(param.Installments) > 0 {
+		if err = clients.DBBatchInsertLoanRequestInstallments(ctx, txn, newLoanRequestInstallmentModels(loanID, param.Installments, now)); err != nil {
+			return 0, err
+		}
 	}
 
 	if err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
@@ -59,14 +67,23 @@ func CreateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam) (
 			LoanID:              loanID,
 			PrincipalPaidAmount: decimal.Zero,
 			InterestPaidAmount:  decimal.Zero,
-			Status:              constants.LoanStatus_InRepayment,
+			Status:              constants.LoanStatus_Submitted,
+			Actor:               constants.LoanActor_Borrower,
 			CreatedAt:           now,
 		},
 	}); err != nil {
 		return 0, err
 	}
 
-	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+	if err = addOutboxEvent(ctx, txn, constants.EventType_LoanCreated, loanID, dtos.LoanCreatedEvent{
+		LoanID:      loanID,
+		UserID:      loanModel.UserID,
+		ProductID:   loanModel.ProductID,
+		PartnerID:   loanModel.PartnerID,
+		LoanAmount:  loanModel.LoanAmount,
+		TenureValue: loanModel.TenureValue,
+		TenureUnit:  int8(loanModel.TenureUnit),
+	}); err != nil {
 		return 0, err
 	}
 
@@ -76,25 +93,192 @@ func CreateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam) (
 	return loanID, nil
 }
 
-func createRepaymentSchedule(loanModel dtos.LoanRequestModel) ([]dtos.BillingModel, []dtos.BillingHistoryModel, error) {
+func getLoanRequestProduct(ctx context.Context, productCode string) (*loanProduct, error) {
+	if productCode == "" {
+		return nil, nil
+	}
+
+	product, err := getLoanProductByCode(ctx, productCode)
+	if err == constants.ErrRecordNotFound {
+		return nil, fmt.Errorf("%w. product_code", constants.ErrInvalidValue)
+	}
+	return product, err
+}
+
+func calculateLoanFees(param dtos.CreateLoanRequestParam, product *loanProduct, now int64) []dtos.LoanFeeModel {
+	var (
+		feeModels []dtos.LoanFeeModel
+
+		loanAmount, _ = decimal.NewFromString(param.LoanAmount)
+	)
+
+	if product != nil {
+		for _, fee := range product.fees {
+			feeModels = append(feeModels, dtos.LoanFeeModel{
+				FeeType:         fee.FeeType,
+				CalculationType: fee.CalculationType,
+				Value:           fee.Value,
+				Amount:          calculateFeeAmount(fee.CalculationType, fee.Value, loanAmount),
+				ChargeType:      fee.ChargeType,
+				CreatedAt:       now,
+			})
+		}
+		return feeModels
+	}
+
+	for _, fee := range param.Fees {
+		feeValue, _ := decimal.NewFromString(fee.Value)
+		feeModels = append(feeModels, dtos.LoanFeeModel{
+			FeeType:         constants.FeeType(fee.FeeType),
+			CalculationType: constants.FeeCalculationType(fee.CalculationType),
+			Value:           feeValue,
+			Amount:          calculateFeeAmount(constants.FeeCalculationType(fee.CalculationType), feeValue, loanAmount),
+			ChargeType:      constants.FeeChargeType(fee.ChargeType),
+			CreatedAt:       now,
+		})
+	}
+	return feeModels
+}
+
+func getLoanFeeAmount(feeModels []dtos.LoanFeeModel, chargeTypes ...constants.FeeChargeType) decimal.Decimal {
+	feeAmount := decimal.Zero
+	for _, fee := range feeModels {
+		if len(chargeTypes) > 0 && !containsFeeChargeType(chargeTypes, fee.ChargeType) {
+			continue
+		}
+		feeAmount = feeAmount.Add(fee.Amount)
+	}
+	return feeAmount
+}
+
+func containsFeeChargeType(chargeTypes []constants.FeeChargeType, chargeType constants.FeeChargeType) bool {
+	for _, c := range chargeTypes {
+		if c == chargeType {
+			return true
+		}
+	}
+	return false
+}
+
+func applyFirstInstallmentFees(billingModels []dtos.BillingModel, feeModels []dtos.LoanFeeModel) {
+	feeAmount := getLoanFeeAmount(feeModels, constants.FeeChargeType_BilledWithFirstInstallment)
+	if len(billingModels) == 0 || feeAmount.IsZero() {
+		return
+	}
+	billingModels[0].FeeAmount = billingModels[0].FeeAmount.Add(feeAmount)
+	billingModels[0].TotalAmount = billingModels[0].TotalAmount.Add(feeAmount)
+}
+
+func newLoanRequestModel(param dtos.CreateLoanRequestParam, product *loanProduct, feeModels []dtos.LoanFeeModel, now int64) dtos.LoanRequestModel {
+	var (
+		productID int64
+
+		loanAmount, _         = decimal.NewFromString(param.LoanAmount)
+		annualInterestRate, _ = decimal.NewFromString(param.AnnualInterestRate)
+		balloonAmount, _      = getBalloonAmount(param, loanAmount)
+		amortizationMethod    = constants.AmortizationMethod(param.AmortizationMethod)
+	)
+
+	if product != nil {
+		productID = product.ID
+		annualInterestRate = product.getAnnualInterestRate(param.TenureValue, constants.TenureUnit(param.TenureUnit))
+		amortizationMethod = product.AmortizationMethod
+	}
+	if !amortizationMethod.IsValid() {
+		amortizationMethod = constants.AmortizationMethod_Flat
+	}
+
+	return dtos.LoanRequestModel{
+		UserID:              param.UserID,
+		ProductID:           productID,
+		PartnerID:           param.PartnerID,
+		LoanAmount:          loanAmount.Add(getLoanFeeAmount(feeModels, constants.FeeChargeType_AddedToPrincipal)),
+		DisbursedAmount:     loanAmount.Sub(getLoanFeeAmount(feeModels, constants.FeeChargeType_DeductedFromDisbursement)),
+		PrincipalPaidAmount: decimal.NewFromUint64(0),
+		InterestPaidAmount:  decimal.NewFromUint64(0),
+		PenaltyPaidAmount:   decimal.NewFromUint64(0),
+		FeePaidAmount:       decimal.NewFromUint64(0),
+		DisbursementTime:    now, // expected disbursement time, replaced by the actual one on disbursement
+		TenureValue:         param.TenureValue,
+		TenureUnit:          constants.TenureUnit(param.TenureUnit),
+		Status:              constants.LoanStatus_Submitted,
+		AnnualInterestRate:  annualInterestRate,
+		AmortizationMethod:  amortizationMethod,
+		GracePeriodType:     constants.GracePeriodType(param.GracePeriodType),
+		GracePeriodValue:    param.GracePeriodValue,
+		BalloonAmount:       balloonAmount,
+	}
+}
+
+func newLoanRequestInstallmentModels(loanID int64, installments []dtos.LoanInstallmentParam, now int64) []dtos.LoanRequestInstallmentModel {
+	installmentModels := make([]dtos.LoanRequestInstallmentModel, 0, len(installments))
+	for idx, installment := range installments {
+		principalAmount, _ := decimal.NewFromString(installment.PrincipalAmount)
+		installmentModels = append(installmentModels, dtos.LoanRequestInstallmentModel{
+			LoanID:          loanID,
+			RecurringIndex:  idx + 1,
+			DueTime:         installment.DueTime,
+			PrincipalAmount: principalAmount,
+			CreatedAt:       now,
+		})
+	}
+	return installmentModels
+}
+
+// scheduleLoanRequest generates the repayment schedule from the loan's disbursement time,
+// validates it against the regulatory caps and fills the loan's credit cost
+func scheduleLoanRequest(loanModel *dtos.LoanRequestModel, feeModels []dtos.LoanFeeModel, customInstallments []dtos.LoanInstallmentParam) ([]dtos.BillingModel, []dtos.BillingHistoryModel, error) {
+	billingModels, billingHistories, err := createRepaymentSchedule(*loanModel, customInstallments)
+	if err != nil {
+		return nil, nil, err
+	}
+	applyFirstInstallmentFees(billingModels, feeModels)
+
+	if err = validateDailyEconomicBenefitCap(*loanModel, billingModels, getLoanFeeAmount(feeModels)); err != nil {
+		return nil, nil, err
+	}
+
+	loanModel.EffectiveAPR, loanModel.TotalCostOfCredit, err = calculateCreditCost(*loanModel, billingModels)
+	if err != nil {
+		return nil, nil, err
+	}
+	return billingModels, billingHistories, nil
+}
+
+type repaymentInstallment struct {
+	recurringIndex  int
+	principalAmount decimal.Decimal
+	interestAmount  decimal.Decimal
+	dueTime         int64 // only set by custom installments
+}
+
+func createRepaymentSchedule(loanModel dtos.LoanRequestModel, customInstallments []dtos.LoanInstallmentParam) ([]dtos.BillingModel, []dtos.BillingHistoryModel, error) {
 	var (
 		billingModels    []dtos.BillingModel
 		billingHistories []dtos.BillingHistoryModel
-		nextDueTime      *time.Time
-
-		now              = time.Now().UnixMilli()
-		tenure           = decimal.NewFromInt(int64(loanModel.TenureValue))
-		disbursementDate = time.UnixMilli(loanModel.DisbursementTime)
+		installments     []repaymentInstallment
 
-		principalAmountPerPayment = loanModel.LoanAmount.Div(tenure)
-		interestAmountPerPayment  = loanModel.LoanAmount.Mul(loanModel.AnnualInterestRate).Div(constants.Percent).Div(tenure)
-		totalAmountPerPayment     = principalAmountPerPayment.Add(interestAmountPerPayment)
+		now                = time.Now().UnixMilli()
+		disbursementEndDay = utils.GetEndOfDay(time.UnixMilli(loanModel.DisbursementTime))
 	)
 
-	nextDueTime = utils.GetNextTenureSchedule(disbursementDate, loanModel.TenureUnit)
-	for recurringIdx := 1; recurringIdx <= loanModel.TenureValue; recurringIdx++ {
-		if nextDueTime = utils.GetNextTenureSchedule(*nextDueTime, loanModel.TenureUnit); nextDueTime == nil {
-			return nil, nil, fmt.Errorf("unable to get next tenure schedule from %v", *nextDueTime)
+	if len(customInstallments) > 0 {
+		installments = calculateCustomRepaymentInstallments(loanModel, customInstallments)
+	} else {
+		installments = calculateRepaymentInstallments(loanModel)
+	}
+
+	for _, installment := range installments {
+		// anchored on the end of the disbursement day, each recurring index is one tenure period after it.
+		// periods without installment (e.g. payment holiday) still move the due time forward
+		nextDueTime := utils.GetTenureSchedule(disbursementEndDay, loanModel.TenureUnit, installment.recurringIndex)
+		if nextDueTime == nil {
+			return nil, nil, fmt.Errorf("unable to get tenure schedule %d from %v", installment.recurringIndex, disbursementEndDay)
+		}
+
+		dueTime := nextDueTime.UnixMilli()
+		if installment.dueTime > 0 {
+			dueTime = installment.dueTime
 		}
 
 		billingID := uuid.NewString()
@@ -102,11 +286,11 @@ func createRepaymentSchedule(loanModel dtos.LoanRequestModel) ([]dtos.BillingMod
 			BillingID:          billingID,
 			LoanID:             loanModel.ID,
 			PaymentID:          0,
-			RecurringIndex:     recurringIdx,
-			PrincipalAmount:    principalAmountPerPayment,
-			InterestAmount:     interestAmountPerPayment,
-			TotalAmount:        totalAmountPerPayment,
-			DueTime:            nextDueTime.UnixMilli(),
+			RecurringIndex:     installment.recurringIndex,
+			PrincipalAmount:    installment.principalAmount,
+			InterestAmount:     installment.interestAmount,
+			TotalAmount:        installment.principalAmount.Add(installment.interestAmount),
+			DueTime:            dueTime,
 			PaymentCompletedAt: 0,
 			Status:             constants.PaymentStatus_Pending,
 			CreatedAt:          now,
@@ -114,6 +298,7 @@ func createRepaymentSchedule(loanModel dtos.LoanRequestModel) ([]dtos.BillingMod
 		})
 		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
 			BillingID:          billingID,
+			DueTime:            dueTime,
 			PaymentCompletedAt: 0,
 			Status:             constants.PaymentStatus_Pending,
 			CreatedAt:          now,
@@ -122,11 +307,185 @@ func createRepaymentSchedule(loanModel dtos.LoanRequestModel) ([]dtos.BillingMod
 	return billingModels, billingHistories, nil
 }
 
-func validateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam) error {
+func calculateRepaymentInstallments(loanModel dtos.LoanRequestModel) []repaymentInstallment {
+	var (
+		installments        []repaymentInstallment
+		gracePeriods        int
+		capitalisedInterest = decimal.Zero
+		interestBaseAmount  = loanModel.LoanAmount
+
+		periodInterestRate = getPeriodInterestRate(loanModel)
+	)
+
+	if loanModel.GracePeriodType.IsValid() {
+		gracePeriods = loanModel.GracePeriodValue
+	}
+
+	for recurringIdx := 1; recurringIdx <= gracePeriods; recurringIdx++ {
+		interestAmount := interestBaseAmount.Mul(periodInterestRate).Round(constants.AmountDecimalPlaces)
+
+		switch loanModel.GracePeriodType {
+		case constants.GracePeriodType_InterestOnly:
+			installments = append(installments, repaymentInstallment{
+				recurringIndex:  recurringIdx,
+				principalAmount: decimal.Zero,
+				interestAmount:  interestAmount,
+			})
+		case constants.GracePeriodType_PaymentHoliday:
+			// nothing is billed, the interest is capitalised and bears interest on the following periods
+			capitalisedInterest = capitalisedInterest.Add(interestAmount)
+			interestBaseAmount = interestBaseAmount.Add(interestAmount)
+		}
+	}
+
+	if loanModel.AmortizationMethod == constants.AmortizationMethod_Annuity {
+		return append(installments, calculateAnnuityInstallments(loanModel, gracePeriods+1, interestBaseAmount, capitalisedInterest, periodInterestRate)...)
+	}
+
+	// the amortising principal and the balloon are separate interest bases. the installments bear the interest of the
+	// reduced amortising principal, while the balloon stays outstanding over every repayment period and its own interest
+	// is billed along with it, so the installments before the balloon stay small
+	var (
+		repaymentPeriods = decimal.NewFromInt(int64(loanModel.TenureValue - gracePeriods))
+		amortisingAmount = loanModel.LoanAmount.Sub(loanModel.BalloonAmount)
+
+		principalAmountPerPayment   = amortisingAmount.Div(repaymentPeriods).Round(constants.AmountDecimalPlaces)
+		interestAmountPerPayment    = interestBaseAmount.Sub(loanModel.BalloonAmount).Mul(periodInterestRate).Round(constants.AmountDecimalPlaces)
+		balloonInterestAmount       = loanModel.BalloonAmount.Mul(periodInterestRate).Mul(repaymentPeriods).Round(constants.AmountDecimalPlaces)
+		capitalisedAmountPerPayment = capitalisedInterest.Div(repaymentPeriods).Round(constants.AmountDecimalPlaces)
+		remainingPrincipalAmount    = amortisingAmount
+		remainingCapitalisedAmount  = capitalisedInterest
+	)
+	for recurringIdx := gracePeriods + 1; recurringIdx <= loanModel.TenureValue; recurringIdx++ {
+		principalAmount, capitalisedAmount := principalAmountPerPayment, capitalisedAmountPerPayment
+		// the last installment absorbs the rounding remainder
+		if recurringIdx == loanModel.TenureValue {
+			principalAmount, capitalisedAmount = remainingPrincipalAmount, remainingCapitalisedAmount
+		}
+		remainingPrincipalAmount = remainingPrincipalAmount.Sub(principalAmount)
+		remainingCapitalisedAmount = remainingCapitalisedAmount.Sub(capitalisedAmount)
+
+		// capitalised interest is repaid along with the installment's interest
+		interestAmount := interestAmountPerPayment.Add(capitalisedAmount)
+		if recurringIdx == loanModel.TenureValue {
+			principalAmount = principalAmount.Add(loanModel.BalloonAmount)
+			interestAmount = interestAmount.Add(balloonInterestAmount)
+		}
+
+		installments = append(installments, repaymentInstallment{
+			recurringIndex:  recurringIdx,
+			principalAmount: principalAmount,
+			interestAmount:  interestAmount,
+		})
+	}
+	return installments
+}
+
+// getPeriodInterestRate gives the interest rate of a single tenure period. flat loans charge the annual interest rate
+// once over the whole tenure whatever the tenure unit is (see README, flat interest), so the tenure unit is deliberately
+// not converted: the actual yearly cost of a flat loan is disclosed by its effective APR instead.
+// annuity loans convert it into a rate per tenure period
+func getPeriodInterestRate(loanModel dtos.LoanRequestModel) decimal.Decimal {
+	if loanModel.AmortizationMethod == constants.AmortizationMethod_Annuity {
+		return utils.GetPeriodInterestRate(loanModel.AnnualInterestRate, loanModel.TenureUnit)
+	}
+	if loanModel.TenureValue < 1 {
+		return decimal.Zero
+	}
+	return loanModel.AnnualInterestRate.Div(constants.Percent).Div(decimal.NewFromInt(int64(loanModel.TenureValue)))
+}
+
+// calculateAnnuityInstallments repays the outstanding amount, including the capitalised interest, with equal installments.
+// the capitalised interest is repaid first and reported as interest so the principal still sums up to the loan amount
+func calculateAnnuityInstallments(loanModel dtos.LoanRequestModel, firstRecurringIdx int, outstandingAmount, capitalisedInterest, periodInterestRate decimal.Decimal) []repaymentInstallment {
+	var (
+		installments []repaymentInstallment
+
+		repaymentPeriods           = loanModel.TenureValue - firstRecurringIdx + 1
+		installmentAmount          = getAnnuityInstallmentAmount(outstandingAmount, loanModel.BalloonAmount, periodInterestRate, repaymentPeriods)
+		remainingCapitalisedAmount = capitalisedInterest
+	)
+
+	for recurringIdx := firstRecurringIdx; recurringIdx <= loanModel.TenureValue; recurringIdx++ {
+		interestAmount := outstandingAmount.Mul(periodInterestRate).Round(constants.AmountDecimalPlaces)
+		principalAmount := installmentAmount.Sub(interestAmount)
+		// the last installment repays the balloon and absorbs the rounding remainder
+		if recurringIdx == loanModel.TenureValue {
+			principalAmount = outstandingAmount
+		}
+		outstandingAmount = outstandingAmount.Sub(principalAmount)
+
+		capitalisedAmount := decimal.Min(remainingCapitalisedAmount, principalAmount)
+		remainingCapitalisedAmount = remainingCapitalisedAmount.Sub(capitalisedAmount)
+
+		installments = append(installments, repaymentInstallment{
+			recurringIndex:  recurringIdx,
+			principalAmount: principalAmount.Sub(capitalisedAmount),
+			interestAmount:  interestAmount.Add(capitalisedAmount),
+		})
+	}
+	return installments
+}
+
+func getAnnuityInstallmentAmount(outstandingAmount, balloonAmount, periodInterestRate decimal.Decimal, repaymentPeriods int) decimal.Decimal {
+	periods := decimal.NewFromInt(int64(repaymentPeriods))
+	if periodInterestRate.IsZero() {
+		return outstandingAmount.Sub(balloonAmount).Div(periods).Round(constants.AmountDecimalPlaces)
+	}
+
+	// P = (PV - FV / (1+r)^n) * r / (1 - (1+r)^-n)
+	compoundFactor, _ := decimal.NewFromInt(1).Add(periodInterestRate).PowInt32(int32(repaymentPeriods))
+	presentValue := outstandingAmount.Sub(balloonAmount.Div(compoundFactor))
+	return presentValue.Mul(periodInterestRate).
+		Div(decimal.NewFromInt(1).Sub(decimal.NewFromInt(1).Div(compoundFactor))).
+		Round(constants.AmountDecimalPlaces)
+}
+
+// calculateCustomRepaymentInstallments charges the interest on the outstanding principal
+// for the actual number of days of each period since the installments are irregular
+func calculateCustomRepaymentInstallments(loanModel dtos.LoanRequestModel, customInstallments []dtos.LoanInstallmentParam) []repaymentInstallment {
+	var (
+		installments []repaymentInstallment
+
+		outstandingAmount = loanModel.LoanAmount
+		dailyInterestRate = utils.GetPeriodInterestRate(loanModel.AnnualInterestRate, constants.TenureUnit_Day)
+		lastDueTime       = utils.GetEndOfDay(time.UnixMilli(loanModel.DisbursementTime))
+	)
+
+	for idx, customInstallment := range customInstallments {
+		var (
+			principalAmount, _ = decimal.NewFromString(customInstallment.PrincipalAmount)
+			dueTime            = utils.GetEndOfDay(time.UnixMilli(customInstallment.DueTime))
+			days               = decimal.NewFromFloat(dueTime.Sub(lastDueTime).Hours() / 24).Round(0)
+		)
+		// fees added to principal are repaid with the last installment
+		if idx == len(customInstallments)-1 {
+			principalAmount = outstandingAmount
+		}
+
+		installments = append(installments, repaymentInstallment{
+			recurringIndex:  idx + 1,
+			principalAmount: principalAmount,
+			interestAmount:  outstandingAmount.Mul(dailyInterestRate).Mul(days).Round(constants.AmountDecimalPlaces),
+			dueTime:         dueTime.UnixMilli(),
+		})
+		outstandingAmount = outstandingAmount.Sub(principalAmount)
+		lastDueTime = dueTime
+	}
+	return installments
+}
+
+func validateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam, product *loanProduct) error {
 	if _, err := clients.DBGetUserByID(ctx, param.UserID); err != nil {
 		return err
 	}
+	if param.PartnerID < 0 {
+		return fmt.Errorf("%w. partner_id", constants.ErrInvalidValue)
+	}
+	return validateLoanTerms(param, product)
+}
 
+func validateLoanTerms(param dtos.CreateLoanRequestParam, product *loanProduct) error {
 	loanAmount, err := decimal.NewFromString(param.LoanAmount)
 	if err != nil {
 		return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
@@ -143,13 +502,145 @@ func validateLoanRequest(ctx context.Context, param dtos.CreateLoanRequestParam)
 		return fmt.Errorf("%w. tenure_unit", constants.ErrInvalidValue)
 	}
 
-	annualInterestRate, err := decimal.NewFromString(param.AnnualInterestRate)
+	if param.GracePeriodType != 0 || param.GracePeriodValue != 0 {
+		if !constants.GracePeriodType(param.GracePeriodType).IsValid() {
+			return fmt.Errorf("%w. grace_period_type", constants.ErrInvalidValue)
+		}
+		if param.GracePeriodValue < 1 || param.GracePeriodValue >= param.TenureValue {
+			return fmt.Errorf("%w. grace_period_value should be greater than 0 and less than tenure_value", constants.ErrInvalidValue)
+		}
+	}
+
+	if param.AnnualInterestRate != "" || product == nil {
+		annualInterestRate, err := decimal.NewFromString(param.AnnualInterestRate)
+		if err != nil {
+			return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+		}
+		if annualInterestRate.LessThan(decimal.NewFromInt(0)) {
+			return fmt.Errorf("%w. annual_interest_rate should be greater or equals to 0", constants.ErrInvalidValue)
+		}
+		if product != nil && !annualInterestRate.Equal(product.getAnnualInterestRate(param.TenureValue, constants.TenureUnit(param.TenureUnit))) {
+			return fmt.Errorf("%w. annual_interest_rate does not follow the product", constants.ErrInvalidValue)
+		}
+	}
+
+	if param.AmortizationMethod != 0 {
+		if !constants.AmortizationMethod(param.AmortizationMethod).IsValid() {
+			return fmt.Errorf("%w. amortization_method", constants.ErrInvalidValue)
+		}
+		if product != nil && constants.AmortizationMethod(param.AmortizationMethod) != product.AmortizationMethod {
+			return fmt.Errorf("%w. amortization_method does not follow the product", constants.ErrInvalidValue)
+		}
+	}
+
+	if product != nil {
+		if err = validateLoanProductTerms(param, product, loanAmount); err != nil {
+			return err
+		}
+	}
+
+	balloonAmount, err := getBalloonAmount(param, loanAmount)
 	if err != nil {
-		return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+		return err
 	}
-	if annualInterestRate.LessThan(decimal.NewFromInt(0)) {
-		return fmt.Errorf("%w. annual_interest_rate should be greater or equals to 0", constants.ErrInvalidValue)
+	if balloonAmount.IsNegative() || balloonAmount.GreaterThanOrEqual(loanAmount) {
+		return fmt.Errorf("%w. balloon amount should be greater or equals to 0 and less than loan_amount", constants.ErrInvalidValue)
+	}
+
+	if len(param.Fees) > 0 && product != nil {
+		return fmt.Errorf("%w. fees are defined by the product", constants.ErrInvalidValue)
+	}
+	for _, fee := range param.Fees {
+		if err = validateFee(fee); err != nil {
+			return err
+		}
+	}
+	deductedFeeAmount := getLoanFeeAmount(calculateLoanFees(param, product, 0), constants.FeeChargeType_DeductedFromDisbursement)
+	if deductedFeeAmount.GreaterThanOrEqual(loanAmount) {
+		return fmt.Errorf("%w. fees deducted from disbursement should be less than loan_amount", constants.ErrInvalidValue)
+	}
+
+	if len(param.Installments) > 0 {
+		if param.GracePeriodValue > 0 || balloonAmount.IsPositive() {
+			return fmt.Errorf("%w. installments can not be combined with grace period or balloon", constants.ErrInvalidValue)
+		}
+		if err = validateCustomInstallments(param.Installments, param.TenureValue, loanAmount, time.Now()); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+func validateLoanProductTerms(param dtos.CreateLoanRequestParam, product *loanProduct, loanAmount decimal.Decimal) error {
+	if product.Status != constants.LoanProductStatus_Active {
+		return fmt.Errorf("%w. product is not active", constants.ErrInvalidValue)
+	}
+
+	if loanAmount.LessThan(product.MinLoanAmount) || loanAmount.GreaterThan(product.MaxLoanAmount) {
+		return fmt.Errorf("%w. loan_amount should be between %s and %s", constants.ErrInvalidValue, product.MinLoanAmount.String(), product.MaxLoanAmount.String())
+	}
+
+	if !product.isTenureUnitAllowed(constants.TenureUnit(param.TenureUnit)) {
+		return fmt.Errorf("%w. tenure_unit is not allowed by the product", constants.ErrInvalidValue)
+	}
+
+	if param.TenureValue < product.MinTenureValue || param.TenureValue > product.MaxTenureValue {
+		return fmt.Errorf("%w. tenure_value should be between %d and %d", constants.ErrInvalidValue, product.MinTenureValue, product.MaxTenureValue)
+	}
+	return nil
+}
+
+func validateCustomInstallments(installments []dtos.LoanInstallmentParam, tenureValue int, loanAmount decimal.Decimal, disbursementTime time.Time) error {
+	var (
+		totalPrincipalAmount decimal.Decimal
+
+		lastDueTime = utils.GetEndOfDay(disbursementTime)
+	)
+
+	if len(installments) != tenureValue {
+		return fmt.Errorf("%w. number of installments should be equal to tenure_value", constants.ErrInvalidValue)
+	}
+
+	for _, installment := range installments {
+		dueTime := utils.GetEndOfDay(time.UnixMilli(installment.DueTime))
+		if !dueTime.After(lastDueTime) {
+			return fmt.Errorf("%w. installments' due_time should be ascending and after the disbursement day", constants.ErrInvalidValue)
+		}
+		lastDueTime = dueTime
+
+		principalAmount, err := decimal.NewFromString(installment.PrincipalAmount)
+		if err != nil {
+			return fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+		}
+		if principalAmount.IsNegative() {
+			return fmt.Errorf("%w. installments' principal_amount should be greater or equals to 0", constants.ErrInvalidValue)
+		}
+		totalPrincipalAmount = totalPrincipalAmount.Add(principalAmount)
+	}
+
+	if !totalPrincipalAmount.Equal(loanAmount) {
+		return fmt.Errorf("%w. installments' principal_amount should sum up to loan_amount", constants.ErrInvalidValue)
+	}
+	return nil
+}
+
+func getBalloonAmount(param dtos.CreateLoanRequestParam, loanAmount decimal.Decimal) (decimal.Decimal, error) {
+	switch {
+	case param.BalloonAmount != "" && param.BalloonPercentage != "":
+		return decimal.Zero, fmt.Errorf("%w. only one of balloon_amount or balloon_percentage can be set", constants.ErrInvalidValue)
+	case param.BalloonAmount != "":
+		balloonAmount, err := decimal.NewFromString(param.BalloonAmount)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+		}
+		return balloonAmount, nil
+	case param.BalloonPercentage != "":
+		balloonPercentage, err := decimal.NewFromString(param.BalloonPercentage)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+		}
+		return loanAmount.Mul(balloonPercentage).Div(constants.Percent).Round(constants.AmountDecimalPlaces), nil
+	}
+	return decimal.Zero, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+func sumRepaymentInstallments(installments []repaymentInstallment) (decimal.Decimal, decimal.Decimal) {
+	principalAmount, interestAmount := decimal.Zero, decimal.Zero
+	for _, installment := range installments {
+		principalAmount = principalAmount.Add(installment.principalAmount)
+		interestAmount = interestAmount.Add(installment.interestAmount)
+	}
+	return principalAmount, interestAmount
+}
+
+func TestCalculateFlatBalloonInstallments(t *testing.T) {
+	tests := []struct {
+		name                string
+		gracePeriodType     constants.GracePeriodType
+		gracePeriodValue    int
+		wantRegularInterest decimal.Decimal
+		wantLastInterest    decimal.Decimal
+	}{
+		// the regular installments bear the interest of the 6,000,000 amortising principal,
+		// the balloon's own interest over the 12 periods is billed with it
+		{name: "no grace period", wantRegularInterest: decimal.NewFromInt(60_000), wantLastInterest: decimal.NewFromInt(780_000)},
+		// the interest only periods bear the interest of the whole loan, the balloon bears interest over the 10 repayment periods
+		{name: "interest only", gracePeriodType: constants.GracePeriodType_InterestOnly, gracePeriodValue: 2,
+			wantRegularInterest: decimal.NewFromInt(60_000), wantLastInterest: decimal.NewFromInt(660_000)},
+	}
+
+	for _, tt := range tests {
+		loanModel := dtos.LoanRequestModel{
+			LoanAmount:         decimal.NewFromInt(12_000_000),
+			BalloonAmount:      decimal.NewFromInt(6_000_000),
+			AnnualInterestRate: decimal.NewFromInt(12),
+			TenureValue:        12,
+			TenureUnit:         constants.TenureUnit_Month,
+			AmortizationMethod: constants.AmortizationMethod_Flat,
+			GracePeriodType:    tt.gracePeriodType,
+			GracePeriodValue:   tt.gracePeriodValue,
+		}
+
+		installments := calculateRepaymentInstallments(loanModel)
+		if len(installments) != loanModel.TenureValue {
+			t.Fatalf("%s: got %d installments, want %d", tt.name, len(installments), loanModel.TenureValue)
+		}
+
+		// a balloon only moves principal and its interest to the end, the loan costs the same flat interest
+		principalAmount, interestAmount := sumRepaymentInstallments(installments)
+		if !principalAmount.Equal(loanModel.LoanAmount) {
+			t.Errorf("%s: got principal %s, want %s", tt.name, principalAmount, loanModel.LoanAmount)
+		}
+		if want := decimal.NewFromInt(1_440_000); !interestAmount.Equal(want) {
+			t.Errorf("%s: got interest %s, want %s", tt.name, interestAmount, want)
+		}
+
+		firstRepayment, lastInstallment := installments[tt.gracePeriodValue], installments[len(installments)-1]
+		if !firstRepayment.interestAmount.Equal(tt.wantRegularInterest) {
+			t.Errorf("%s: got regular interest %s, want %s", tt.name, firstRepayment.interestAmount, tt.wantRegularInterest)
+		}
+		if !lastInstallment.interestAmount.Equal(tt.wantLastInterest) {
+			t.Errorf("%s: got last interest %s, want %s", tt.name, lastInstallment.interestAmount, tt.wantLastInterest)
+		}
+		if lastInstallment.principalAmount.LessThan(loanModel.BalloonAmount) {
+			t.Errorf("%s: got last principal %s, want the balloon %s in it", tt.name, lastInstallment.principalAmount, loanModel.BalloonAmount)
+		}
+	}
+}
+
+func TestCalculateRepaymentInstallments(t *testing.T) {
+	var (
+		loanAmount = decimal.NewFromInt(1_200_000)
+		tenure     = 12
+	)
+
+	tenureUnits := []constants.TenureUnit{
+		constants.TenureUnit_Day, constants.TenureUnit_Week, constants.TenureUnit_BiWeek, constants.TenureUnit_SemiMonth,
+		constants.TenureUnit_Month, constants.TenureUnit_Quarter, constants.TenureUnit_Year,
+	}
+	for _, tenureUnit := range tenureUnits {
+		for _, amortizationMethod := range []constants.AmortizationMethod{constants.AmortizationMethod_Flat, constants.AmortizationMethod_Annuity} {
+			loanModel := dtos.LoanRequestModel{
+				LoanAmount:         loanAmount,
+				BalloonAmount:      decimal.Zero,
+				AnnualInterestRate: decimal.NewFromInt(12),
+				TenureValue:        tenure,
+				TenureUnit:         tenureUnit,
+				AmortizationMethod: amortizationMethod,
+			}
+			name := fmt.Sprintf("tenure unit %d, amortization method %d", tenureUnit, amortizationMethod)
+
+			installments := calculateRepaymentInstallments(loanModel)
+			if len(installments) != tenure {
+				t.Fatalf("%s: got %d installments, want %d", name, len(installments), tenure)
+			}
+			principalAmount, interestAmount := sumRepaymentInstallments(installments)
+			if !principalAmount.Equal(loanAmount) {
+				t.Errorf("%s: got principal %s, want %s", name, principalAmount, loanAmount)
+			}
+
+			switch amortizationMethod {
+			case constants.AmortizationMethod_Flat:
+				// the annual rate is charged once over the tenure whatever its unit, 1,200,000 x 12% in 12 equal parts
+				if want := decimal.NewFromInt(144_000); !interestAmount.Equal(want) {
+					t.Errorf("%s: got interest %s, want %s", name, interestAmount, want)
+				}
+				for _, installment := range installments {
+					if !installment.principalAmount.Equal(decimal.NewFromInt(100_000)) || !installment.interestAmount.Equal(decimal.NewFromInt(12_000)) {
+						t.Errorf("%s: got installment %d of principal %s and interest %s, want 100000 and 12000",
+							name, installment.recurringIndex, installment.principalAmount, installment.interestAmount)
+					}
+				}
+			case constants.AmortizationMethod_Annuity:
+				// the first period bears the period rate of the tenure unit on the whole loan
+				periodInterestRate := utils.GetPeriodInterestRate(loanModel.AnnualInterestRate, tenureUnit)
+				if want := loanAmount.Mul(periodInterestRate).Round(constants.AmountDecimalPlaces); !installments[0].interestAmount.Equal(want) {
+					t.Errorf("%s: got first interest %s, want %s", name, installments[0].interestAmount, want)
+				}
+				// the installments are equal, the last one only absorbs the rounding remainder
+				installmentAmount := installments[0].principalAmount.Add(installments[0].interestAmount)
+				for _, installment := range installments[1:] {
+					diff := installment.principalAmount.Add(installment.interestAmount).Sub(installmentAmount).Abs()
+					if diff.GreaterThan(decimal.NewFromInt(1)) {
+						t.Errorf("%s: got installment %d of %s, want %s", name, installment.recurringIndex,
+							installment.principalAmount.Add(installment.interestAmount), installmentAmount)
+					}
+				}
+				// the interest is charged on the outstanding balance, so it decreases as the principal is repaid
+				for i := 1; i < len(installments); i++ {
+					if !installments[i].interestAmount.LessThan(installments[i-1].interestAmount) {
+						t.Errorf("%s: got interest %s after %s, want it decreasing", name, installments[i].interestAmount, installments[i-1].interestAmount)
+					}
+				}
+			}
+		}
+	}
+
+	// 1,200,000 at 1% a month over 12 months
+	installments := calculateRepaymentInstallments(dtos.LoanRequestModel{
+		LoanAmount:         loanAmount,
+		AnnualInterestRate: decimal.NewFromInt(12),
+		TenureValue:        tenure,
+		TenureUnit:         constants.TenureUnit_Month,
+		AmortizationMethod: constants.AmortizationMethod_Annuity,
+	})
+	if got := installments[0].principalAmount.Add(installments[0].interestAmount); !got.Equal(decimal.RequireFromString("106618.55")) {
+		t.Errorf("got monthly annuity installment %s, want 106618.55", got)
+	}
+}
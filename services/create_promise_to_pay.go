@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxPromiseToPayDays = 30 // how far ahead the borrower can promise to pay
+)
+
+// CreatePromiseToPay records the borrower's promise to pay an amount by a date, a case has at most one pending promise.
+// The payments made from now until the end of the date are checked against it by CheckPromisesToPay
+func CreatePromiseToPay(ctx context.Context, param dtos.CreatePromiseToPayParam) (int64, error) {
+	if err := validateActor(param.Actor); err != nil {
+		return 0, err
+	}
+
+	amount, err := decimal.NewFromString(param.Amount)
+	if err != nil {
+		return 0, fmt.Errorf("%w. %s", constants.ErrInvalidValue, err.Error())
+	}
+	if !amount.IsPositive() || !amount.Equal(amount.Round(constants.AmountDecimalPlaces)) {
+		return 0, fmt.Errorf("%w. amount should be greater than 0 with at most %d decimal places", constants.ErrInvalidValue, constants.AmountDecimalPlaces)
+	}
+
+	date, err := time.ParseInLocation(constants.DateLayout, param.Date, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("%w. date should be in YYYY-MM-DD format", constants.ErrInvalidValue)
+	}
+	today := utils.GetStartOfDay(time.Now())
+	if date.Before(today) || date.After(today.AddDate(0, 0, maxPromiseToPayDays)) {
+		return 0, fmt.Errorf("%w. date should be within %d days from today", constants.ErrInvalidValue, maxPromiseToPayDays)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	caseModel, err := getOpenCollectionCaseForUpdate(ctx, txn, param.CaseID)
+	if err != nil {
+		return 0, err
+	}
+
+	promiseModels, err := clients.DBGetPromisesToPayByCaseID(ctx, txn, caseModel.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, promise := range promiseModels {
+		if promise.Status == constants.PromiseToPayStatus_Pending {
+			return 0, fmt.Errorf("%w. collection case already has a pending promise to pay", constants.ErrInvalidValue)
+		}
+	}
+
+	promiseID, err := clients.DBInsertPromiseToPay(ctx, txn, &dtos.PromiseToPayModel{
+		CaseID:          caseModel.ID,
+		LoanID:          caseModel.LoanID,
+		PromisedAmount:  amount,
+		PromisedDueTime: date.AddDate(0, 0, 1).UnixMilli(),
+		Actor:           param.Actor,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = clients.DBBatchInsertCollectionActivities(ctx, txn, []dtos.CollectionActivityModel{
+		{
+			CaseID:       caseModel.ID,
+			LoanID:       caseModel.LoanID,
+			ActivityType: constants.CollectionActivityType_PromiseToPay,
+			Actor:        param.Actor,
+			Notes:        fmt.Sprintf("promised %s by %s", amount.StringFixed(constants.AmountDecimalPlaces), param.Date),
+		},
+	}); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return 0, err
+	}
+	return promiseID, nil
+}
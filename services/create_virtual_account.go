@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateVirtualAccount returns the virtual account the borrower pays the loan to. Without billing_id the account takes
+// any amount and is reused for the loan's every payment, with billing_id it only takes the billing's unpaid amount once.
+// The external id is derived from the loan or the billing amount, so a retried creation returns the provider's same account
+func CreateVirtualAccount(ctx context.Context, param dtos.CreateVirtualAccountParam) (*dtos.VirtualAccountResponse, error) {
+	userModel, err := clients.DBGetUserByID(ctx, param.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig := configs.Get().PaymentProvider
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent creating the same account twice with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDAndUserIDForUpdate(ctx, txn, param.LoanID, param.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !loanRequestModel.Status.IsDisbursed() || loanRequestModel.Status == constants.LoanStatus_Completed {
+		return nil, fmt.Errorf("%w. loan request is not in repayment", constants.ErrInvalidValue)
+	}
+
+	externalID := fmt.Sprintf("loan-%d", loanRequestModel.ID)
+	expectedAmount := decimal.Zero
+	if param.BillingID != "" {
+		billingModels, err := clients.DBGetBillingsByBillingIDsForUpdate(ctx, txn, loanRequestModel.ID, []string{param.BillingID})
+		if err != nil {
+			return nil, err
+		}
+		if len(billingModels) == 0 {
+			return nil, constants.ErrRecordNotFound
+		}
+		if !billingModels[0].Status.IsOpen() {
+			return nil, fmt.Errorf("%w. billing is not open", constants.ErrInvalidValue)
+		}
+		expectedAmount = billingModels[0].GetUnpaidAmount()
+		externalID = fmt.Sprintf("billing-%s-%s", param.BillingID, expectedAmount.StringFixed(constants.AmountDecimalPlaces))
+	}
+
+	virtualAccountModels, err := clients.DBGetActiveVirtualAccountsByLoanIDForUpdate(ctx, txn, providerConfig.Name, loanRequestModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, virtualAccount := range virtualAccountModels {
+		if virtualAccount.BillingID != param.BillingID {
+			continue
+		}
+		if virtualAccount.ExpectedAmount.Equal(expectedAmount) {
+			response := newVirtualAccountResponse(virtualAccount)
+			return &response, nil
+		}
+
+		// the billing amount has changed since, e.g. by a late penalty
+		if err = clients.DBUpdateVirtualAccountStatusByID(ctx, txn, virtualAccount.ID, constants.VirtualAccountStatus_Inactive); err != nil {
+			return nil, err
+		}
+	}
+
+	providerAccount, err := clients.GetPaymentProvider().CreateVirtualAccount(ctx, dtos.CreateVirtualAccountRequest{
+		ExternalID:     externalID,
+		BankCode:       providerConfig.BankCode,
+		Name:           userModel.Name,
+		ExpectedAmount: expectedAmount,
+		IsSingleUse:    param.BillingID != "",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	virtualAccountModel := dtos.VirtualAccountModel{
+		Provider:       providerConfig.Name,
+		ExternalID:     externalID,
+		BankCode:       providerAccount.BankCode,
+		VANumber:       providerAccount.VANumber,
+		UserID:         loanRequestModel.UserID,
+		LoanID:         loanRequestModel.ID,
+		BillingID:      param.BillingID,
+		ExpectedAmount: expectedAmount,
+		Status:         constants.VirtualAccountStatus_Active,
+	}
+	if virtualAccountModel.ID, err = clients.DBInsertVirtualAccount(ctx, txn, &virtualAccountModel); err != nil {
+		return nil, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, err
+	}
+	response := newVirtualAccountResponse(virtualAccountModel)
+	return &response, nil
+}
+
+func newVirtualAccountResponse(virtualAccount dtos.VirtualAccountModel) dtos.VirtualAccountResponse {
+	return dtos.VirtualAccountResponse{
+		VirtualAccountID: virtualAccount.ID,
+		BankCode:         virtualAccount.BankCode,
+		VANumber:         virtualAccount.VANumber,
+		LoanID:           virtualAccount.LoanID,
+		BillingID:        virtualAccount.BillingID,
+		ExpectedAmount:   virtualAccount.ExpectedAmount,
+	}
+}
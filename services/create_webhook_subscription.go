@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+const (
+	minWebhookSecretLength = 32
+)
+
+func CreateWebhookSubscription(ctx context.Context, param dtos.CreateWebhookSubscriptionParam) (int64, error) {
+	if err := validateWebhookSubscription(param); err != nil {
+		return 0, err
+	}
+
+	return clients.DBInsertWebhookSubscription(ctx, nil, &dtos.WebhookSubscriptionModel{
+		PartnerID:  param.PartnerID,
+		URL:        param.URL,
+		EventTypes: strings.Join(param.EventTypes, ","),
+		Secret:     param.Secret,
+		Status:     constants.WebhookSubscriptionStatus_Active,
+	})
+}
+
+func validateWebhookSubscription(param dtos.CreateWebhookSubscriptionParam) error {
+	if param.PartnerID < 1 {
+		return fmt.Errorf("%w. partner_id", constants.ErrInvalidValue)
+	}
+
+	webhookURL, err := url.Parse(param.URL)
+	// the payloads carry loan data and their signatures, they are only sent over tls
+	if err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("%w. url should be an absolute https url", constants.ErrInvalidValue)
+	}
+
+	if len(param.EventTypes) == 0 {
+		return fmt.Errorf("%w. event_types should not be empty", constants.ErrInvalidValue)
+	}
+	for _, eventType := range param.EventTypes {
+		if !constants.EventType(eventType).IsValid() {
+			return fmt.Errorf("%w. event_types' %s", constants.ErrInvalidValue, eventType)
+		}
+	}
+
+	if len(param.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("%w. secret should be at least %d characters", constants.ErrInvalidValue, minWebhookSecretLength)
+	}
+	return nil
+}
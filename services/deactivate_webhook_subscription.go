@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// DeactivateWebhookSubscription stops enqueueing the subscription's deliveries, the pending ones are dead lettered
+func DeactivateWebhookSubscription(ctx context.Context, param dtos.DeactivateWebhookSubscriptionParam) error {
+	if _, err := clients.DBGetWebhookSubscriptionByID(ctx, param.SubscriptionID); err != nil {
+		return err
+	}
+	return clients.DBUpdateWebhookSubscriptionStatusByID(ctx, nil, param.SubscriptionID, constants.WebhookSubscriptionStatus_Inactive)
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// DefaultLoanRequest marks a disbursed loan as defaulted, the loan is cured back into repayment once its overdue billings are paid
+func DefaultLoanRequest(ctx context.Context, param dtos.DefaultLoanRequestParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+	return updateLoanRequestStatus(ctx, param.LoanID, 0, constants.LoanStatus_Defaulted, param.Actor, param.Reason)
+}
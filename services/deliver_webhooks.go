@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookDeliveryBatchSize = 100
+)
+
+// DeliverWebhooks attempts the pending deliveries whose next attempt is due.
+// A failed delivery is retried with exponential backoff until the max attempts, then dead lettered.
+// Each batch is claimed before it is sent, so concurrent runs never send the same delivery
+func DeliverWebhooks(ctx context.Context) (*dtos.DeliverWebhooksResponse, error) {
+	var (
+		lastDeliveryID int64
+		response       dtos.DeliverWebhooksResponse
+
+		now           = time.Now()
+		subscriptions = make(map[int64]*dtos.WebhookSubscriptionModel)
+	)
+
+	for {
+		deliveries, err := claimDueWebhookDeliveries(ctx, now, lastDeliveryID)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			lastDeliveryID = delivery.ID
+
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if !ok {
+				subscription, err = clients.DBGetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+				if err != nil && err != constants.ErrRecordNotFound {
+					return nil, err
+				}
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+
+			attemptWebhookDelivery(ctx, subscription, delivery)
+			isUpdated, err := clients.DBUpdateWebhookDeliveryAttemptByID(ctx, nil, delivery)
+			if err != nil {
+				return nil, err
+			}
+			if !isUpdated {
+				logrus.Warnf("webhook delivery %d is attempted after its claim expired", delivery.ID)
+			}
+
+			switch delivery.Status {
+			case constants.WebhookDeliveryStatus_Delivered:
+				response.DeliveredCount++
+			case constants.WebhookDeliveryStatus_DeadLettered:
+				logrus.Errorf("dead lettered webhook delivery %d of event %s. %s", delivery.ID, delivery.EventID, delivery.LastError)
+				response.DeadLetteredCount++
+			default:
+				response.RetryingCount++
+			}
+		}
+
+		if len(deliveries) < webhookDeliveryBatchSize {
+			return &response, nil
+		}
+	}
+}
+
+// claimDueWebhookDeliveries locks the next batch of due deliveries until the run is expected to have attempted them
+func claimDueWebhookDeliveries(ctx context.Context, now time.Time, lastDeliveryID int64) ([]dtos.WebhookDeliveryModel, error) {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	deliveries, err := clients.DBGetDueWebhookDeliveriesForUpdate(ctx, txn, now.UnixMilli(), lastDeliveryID, webhookDeliveryBatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	var (
+		lockedUntil = getWebhookDeliveryLockedUntil(time.Now(), len(deliveries))
+		ids         = make([]int64, 0, len(deliveries))
+	)
+	for i := range deliveries {
+		deliveries[i].LockedUntil = lockedUntil
+		ids = append(ids, deliveries[i].ID)
+	}
+	if err = clients.DBUpdateWebhookDeliveryLockedUntilByIDs(ctx, txn, ids, lockedUntil); err != nil {
+		return nil, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// DisburseLoanRequest disburses an approved loan and generates its repayment schedule from the disbursement time
+func DisburseLoanRequest(ctx context.Context, param dtos.DisburseLoanRequestParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	if param.DisbursementTime == 0 {
+		param.DisbursementTime = now
+	}
+	if param.DisbursementTime > now {
+		return fmt.Errorf("%w. disbursement_time should not be in the future", constants.ErrInvalidValue)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, param.LoanID)
+	if err != nil {
+		return err
+	}
+	if loanRequestModel.Status != constants.LoanStatus_Approved {
+		return fmt.Errorf("%w. only approved loan request can be disbursed", constants.ErrInvalidValue)
+	}
+	if param.DisbursementTime < loanRequestModel.CreatedAt {
+		return fmt.Errorf("%w. disbursement_time should be after the loan request is submitted", constants.ErrInvalidValue)
+	}
+
+	feeModels, err := clients.DBGetLoanFeesByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+
+	installmentModels, err := clients.DBGetLoanRequestInstallmentsByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+	customInstallments := make([]dtos.LoanInstallmentParam, 0, len(installmentModels))
+	for _, installment := range installmentModels {
+		customInstallments = append(customInstallments, dtos.LoanInstallmentParam{
+			DueTime:         installment.DueTime,
+			PrincipalAmount: installment.PrincipalAmount.String(),
+		})
+	}
+	if len(customInstallments) > 0 {
+		// the installments were submitted against the requested amount, without the fees added to principal
+		requestedAmount := loanRequestModel.LoanAmount.Sub(getLoanFeeAmount(feeModels, constants.FeeChargeType_AddedToPrincipal))
+		// the installments due by the disbursement day are amended with AmendLoanRequestInstallments first,
+		// otherwise the borrower cancels the request and submits it again
+		if err = validateCustomInstallments(customInstallments, loanRequestModel.TenureValue, requestedAmount, time.UnixMilli(param.DisbursementTime)); err != nil {
+			return fmt.Errorf("%w. amend the installments before disbursing", err)
+		}
+	}
+
+	loanRequestModel.DisbursementTime = param.DisbursementTime
+	billingModels, billingHistories, err := scheduleLoanRequest(loanRequestModel, feeModels, customInstallments)
+	if err != nil {
+		return err
+	}
+	for i := range billingModels {
+		billingModels[i].LoanID = loanRequestModel.ID
+	}
+
+	if err = clients.DBUpdateLoanRequestDisbursementByID(ctx, txn, loanRequestModel); err != nil {
+		return err
+	}
+
+	if err = clients.DBBatchInsertBillings(ctx, txn, billingModels); err != nil {
+		return err
+	}
+
+	// the fees deducted from disbursement or added to principal are earned upfront
+	journalEntry := newJournalEntry(constants.JournalEntryType_Disbursement, loanRequestModel.ID, loanRequestModel.ID, "loan disbursement").
+		debit(constants.LedgerAccount_LoanReceivable, loanRequestModel.LoanAmount).
+		credit(constants.LedgerAccount_Cash, loanRequestModel.DisbursedAmount).
+		credit(constants.LedgerAccount_FeeIncome, loanRequestModel.LoanAmount.Sub(loanRequestModel.DisbursedAmount)).
+		scheduleBillings(billingModels)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return err
+	}
+
+	if err = transitionLoanStatus(ctx, txn, loanRequestModel, constants.LoanStatus_InRepayment, param.Actor, ""); err != nil {
+		return err
+	}
+
+	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
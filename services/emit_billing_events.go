@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EmitBillingEvents records billing.due for the open billings due today and billing.overdue for the ones past their due time.
+// Each event is emitted once per due time
+func EmitBillingEvents(ctx context.Context) error {
+	now := time.Now()
+	endOfToday := utils.GetStartOfDay(now).AddDate(0, 0, 1)
+
+	if err := emitBillingEvents(ctx, constants.EventType_BillingDue, endOfToday.UnixMilli(),
+		clients.DBGetOpenBillingsWithoutDueEvent, clients.DBUpdateBillingDueEventAtByIDs); err != nil {
+		return err
+	}
+	return emitBillingEvents(ctx, constants.EventType_BillingOverdue, now.UnixMilli(),
+		clients.DBGetOpenBillingsWithoutOverdueEvent, clients.DBUpdateBillingOverdueEventAtByIDs)
+}
+
+func emitBillingEvents(
+	ctx context.Context,
+	eventType constants.EventType,
+	dueTime int64,
+	getBillings func(ctx context.Context, dueTime, lastID int64, limit int) ([]dtos.BillingEventModel, error),
+	markBillings func(ctx context.Context, tx *sqlx.Tx, ids []int64) error,
+) error {
+	var lastBillingID int64
+	for {
+		billingModels, err := getBillings(ctx, dueTime, lastBillingID, loanBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(billingModels) == 0 {
+			return nil
+		}
+
+		var (
+			loanIDs      = make([]int64, 0)
+			loanBillings = make(map[int64][]dtos.BillingEventModel)
+		)
+		for _, billing := range billingModels {
+			lastBillingID = billing.ID
+			if _, ok := loanBillings[billing.LoanID]; !ok {
+				loanIDs = append(loanIDs, billing.LoanID)
+			}
+			loanBillings[billing.LoanID] = append(loanBillings[billing.LoanID], billing)
+		}
+
+		for _, loanID := range loanIDs {
+			if err = addLoanBillingEvents(ctx, eventType, loanID, loanBillings[loanID], markBillings); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addLoanBillingEvents holds the loan lock like the payments do, so a billing event is sequenced before or after the payment
+// settling the billing, and a billing settled in the meantime gets no event
+func addLoanBillingEvents(
+	ctx context.Context,
+	eventType constants.EventType,
+	loanID int64,
+	billingEventModels []dtos.BillingEventModel,
+	markBillings func(ctx context.Context, tx *sqlx.Tx, ids []int64) error,
+) error {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	if _, err = clients.DBGetLoanRequestByIDForUpdate(ctx, txn, loanID); err != nil {
+		return err
+	}
+
+	billingIDs := make([]string, 0, len(billingEventModels))
+	for _, billing := range billingEventModels {
+		billingIDs = append(billingIDs, billing.BillingID)
+	}
+	billingModels, err := clients.DBGetBillingsByBillingIDsForUpdate(ctx, txn, loanID, billingIDs)
+	if err != nil {
+		return err
+	}
+	openBillings := make(map[string]dtos.BillingModel)
+	for _, billing := range billingModels {
+		if billing.Status.IsOpen() {
+			openBillings[billing.BillingID] = billing
+		}
+	}
+
+	var (
+		ids          = make([]int64, 0, len(billingEventModels))
+		outboxEvents = make([]dtos.OutboxEventModel, 0, len(billingEventModels))
+	)
+	for _, billingEvent := range billingEventModels {
+		billing, ok := openBillings[billingEvent.BillingID]
+		if !ok {
+			continue
+		}
+
+		outboxEvent, err := newOutboxEvent(eventType, loanID, dtos.BillingDueEvent{
+			BillingID:      billing.BillingID,
+			LoanID:         loanID,
+			UserID:         billingEvent.UserID,
+			RecurringIndex: billing.RecurringIndex,
+			DueTime:        billing.DueTime,
+			UnpaidAmount: billing.TotalAmount.
+				Sub(billing.PrincipalPaidAmount).
+				Sub(billing.InterestPaidAmount).
+				Sub(billing.PenaltyPaidAmount).
+				Sub(billing.FeePaidAmount),
+		})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, billing.ID)
+		outboxEvents = append(outboxEvents, outboxEvent)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err = markBillings(ctx, txn, ids); err != nil {
+		return err
+	}
+
+	if err = insertOutboxEvents(ctx, txn, outboxEvents); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
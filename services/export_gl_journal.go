@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+var glJournalCSVHeader = []string{"journal_id", "date", "entry_type", "account_code", "account_name", "debit_amount", "credit_amount"}
+
+// ExportGLJournal aggregates the ledger's journal entries of a day into GL lines following the chart of accounts mapping,
+// and writes them as CSV and JSON files. The journal lines are never updated, so rerunning a date produces identical files
+func ExportGLJournal(ctx context.Context, param dtos.ExportGLJournalParam) (*dtos.GLJournalExportResponse, error) {
+	glExport := configs.Get().GLExport
+
+	date, err := time.ParseInLocation(constants.DateLayout, param.Date, glExport.Location)
+	if err != nil {
+		return nil, fmt.Errorf("%w. date should be in YYYY-MM-DD format", constants.ErrInvalidValue)
+	}
+	nextDate := date.AddDate(0, 0, 1)
+	if nextDate.After(time.Now()) {
+		return nil, fmt.Errorf("%w. only a past date can be exported", constants.ErrInvalidValue)
+	}
+
+	summaryModels, err := clients.DBGetJournalLineSummariesByCreatedTime(ctx, date.UnixMilli(), nextDate.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := newGLJournal(param.Date, summaryModels, glExport.ChartOfAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.GLJournalExportResponse{
+		Journal:      *journal,
+		CSVFilePath:  filepath.Join(glExport.OutputDir, fmt.Sprintf("gl_journal_%s.csv", param.Date)),
+		JSONFilePath: filepath.Join(glExport.OutputDir, fmt.Sprintf("gl_journal_%s.json", param.Date)),
+	}
+	if err = os.MkdirAll(glExport.OutputDir, 0o755); err != nil {
+		return nil, err
+	}
+	if err = writeGLJournalCSV(response.CSVFilePath, journal); err != nil {
+		return nil, err
+	}
+	if err = writeGLJournalJSON(response.JSONFilePath, journal); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// newGLJournal maps the ledger accounts into GL accounts, the amounts of an entry type are netted per GL account
+func newGLJournal(date string, summaryModels []dtos.JournalLineSummaryModel, chartOfAccounts map[string]configs.GLAccount) (*dtos.GLJournal, error) {
+	type glLineKey struct {
+		entryType   constants.JournalEntryType
+		accountCode string
+	}
+
+	var (
+		netAmounts   = make(map[glLineKey]decimal.Decimal)
+		accountNames = make(map[string]string)
+		keys         []glLineKey
+	)
+	for _, summaryModel := range summaryModels {
+		glAccount, ok := chartOfAccounts[summaryModel.Account.Name()]
+		if !ok || glAccount.Code == "" {
+			return nil, fmt.Errorf("%w. chart of accounts has no mapping for ledger account %s", constants.ErrInvalidValue, summaryModel.Account.Name())
+		}
+		accountNames[glAccount.Code] = glAccount.Name
+
+		key := glLineKey{entryType: summaryModel.EntryType, accountCode: glAccount.Code}
+		if _, ok = netAmounts[key]; !ok {
+			keys = append(keys, key)
+		}
+		netAmounts[key] = netAmounts[key].Add(summaryModel.DebitAmount).Sub(summaryModel.CreditAmount)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].entryType != keys[j].entryType {
+			return keys[i].entryType < keys[j].entryType
+		}
+		return keys[i].accountCode < keys[j].accountCode
+	})
+
+	journal := dtos.GLJournal{
+		Date:              date,
+		TotalDebitAmount:  decimal.Zero,
+		TotalCreditAmount: decimal.Zero,
+		Lines:             make([]dtos.GLJournalLine, 0, len(keys)),
+	}
+	for _, key := range keys {
+		netAmount := netAmounts[key]
+		if netAmount.IsZero() {
+			continue
+		}
+
+		line := dtos.GLJournalLine{
+			JournalID:    fmt.Sprintf("GL-%s-%s", strings.ReplaceAll(date, "-", ""), key.entryType.Name()),
+			Date:         date,
+			EntryType:    key.entryType.Name(),
+			AccountCode:  key.accountCode,
+			AccountName:  accountNames[key.accountCode],
+			DebitAmount:  decimal.Max(netAmount, decimal.Zero),
+			CreditAmount: decimal.Max(netAmount.Neg(), decimal.Zero),
+		}
+		journal.TotalDebitAmount = journal.TotalDebitAmount.Add(line.DebitAmount)
+		journal.TotalCreditAmount = journal.TotalCreditAmount.Add(line.CreditAmount)
+		journal.Lines = append(journal.Lines, line)
+	}
+	if !journal.TotalDebitAmount.Equal(journal.TotalCreditAmount) {
+		return nil, fmt.Errorf("%w. gl journal of %s is not balanced, debit %s and credit %s", constants.ErrInvalidValue, date, journal.TotalDebitAmount.String(), journal.TotalCreditAmount.String())
+	}
+	return &journal, nil
+}
+
+func writeGLJournalCSV(path string, journal *dtos.GLJournal) error {
+	var content strings.Builder
+	writer := csv.NewWriter(&content)
+	if err := writer.Write(glJournalCSVHeader); err != nil {
+		return err
+	}
+	for _, line := range journal.Lines {
+		if err := writer.Write([]string{
+			line.JournalID,
+			line.Date,
+			line.EntryType,
+			line.AccountCode,
+			line.AccountName,
+			line.DebitAmount.StringFixed(constants.AmountDecimalPlaces),
+			line.CreditAmount.StringFixed(constants.AmountDecimalPlaces),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return writeFileAtomically(path, []byte(content.String()))
+}
+
+func writeGLJournalJSON(path string, journal *dtos.GLJournal) error {
+	content, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, append(content, '\n'))
+}
+
+// writeFileAtomically replaces the file in one go, so the importer never reads a half written export
+func writeFileAtomically(path string, content []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/dtos"
+)
+
+// GetCollectionCase returns the case along with its activity log and promises to pay
+func GetCollectionCase(ctx context.Context, param dtos.GetCollectionCaseParam) (*dtos.CollectionCaseDetailResponse, error) {
+	caseModel, err := clients.DBGetCollectionCaseByID(ctx, param.CaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	activityModels, err := clients.DBGetCollectionActivitiesByCaseID(ctx, caseModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	promiseModels, err := clients.DBGetPromisesToPayByCaseID(ctx, nil, caseModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.CollectionCaseDetailResponse{
+		CollectionCaseResponse: newCollectionCaseResponse(*caseModel),
+		Activities:             make([]dtos.CollectionActivityResponse, 0, len(activityModels)),
+		PromisesToPay:          make([]dtos.PromiseToPayResponse, 0, len(promiseModels)),
+	}
+	for _, activity := range activityModels {
+		response.Activities = append(response.Activities, dtos.CollectionActivityResponse{
+			ActivityID:   activity.ID,
+			ActivityType: int8(activity.ActivityType),
+			Actor:        activity.Actor,
+			Notes:        activity.Notes,
+			ActivityTime: activity.ActivityTime,
+		})
+	}
+	for _, promise := range promiseModels {
+		response.PromisesToPay = append(response.PromisesToPay, dtos.PromiseToPayResponse{
+			PromiseID:       promise.ID,
+			PromisedAmount:  promise.PromisedAmount,
+			PromisedDueTime: promise.PromisedDueTime,
+			PaidAmount:      promise.PaidAmount,
+			Status:          int8(promise.Status),
+			Actor:           promise.Actor,
+			CreatedAt:       promise.CreatedAt,
+		})
+	}
+	return &response, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// GetECLReport returns the stored provision of the reporting date, yesterday by default. The provision is only run
+// by RunECLReport for yesterday, an earlier date without a stored run is not found
+func GetECLReport(ctx context.Context, param dtos.GetECLReportParam) (*dtos.ECLReportResponse, error) {
+	asOfDate := utils.GetStartOfDay(time.Now()).AddDate(0, 0, -1)
+	if param.AsOf != "" {
+		date, err := time.ParseInLocation(constants.DateLayout, param.AsOf, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%w. as of should be in YYYY-MM-DD format", constants.ErrInvalidValue)
+		}
+		if date.After(asOfDate) {
+			return nil, fmt.Errorf("%w. only a past date can be reported", constants.ErrInvalidValue)
+		}
+		asOfDate = date
+	}
+
+	runModel, err := clients.DBGetECLRunByAsOfDate(ctx, asOfDate.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	return getStoredECLReport(ctx, runModel)
+}
+
+func getStoredECLReport(ctx context.Context, runModel *dtos.ECLRunModel) (*dtos.ECLReportResponse, error) {
+	runLoanModels, err := clients.DBGetECLRunLoansByRunID(ctx, runModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reportLoans := make([]dtos.ECLReportLoan, 0, len(runLoanModels))
+	for _, runLoanModel := range runLoanModels {
+		reportLoans = append(reportLoans, dtos.ECLReportLoan{
+			LoanID:         runLoanModel.LoanID,
+			UserID:         runLoanModel.UserID,
+			ProductCode:    runLoanModel.ProductCode,
+			DaysPastDue:    runLoanModel.DaysPastDue,
+			IsRestructured: runLoanModel.IsRestructured,
+			Stage:          runLoanModel.Stage,
+			ExposureAmount: runLoanModel.ExposureAmount,
+			PD:             runLoanModel.PD,
+			LGD:            runLoanModel.LGD,
+			ECLAmount:      runLoanModel.ECLAmount,
+		})
+	}
+
+	response := newECLReportResponse(time.UnixMilli(runModel.AsOfDate), reportLoans)
+	response.ECLRunID = runModel.ID
+	response.CreatedAt = runModel.CreatedAt
+	return response, nil
+}
+
+func newECLReportResponse(asOfDate time.Time, reportLoans []dtos.ECLReportLoan) *dtos.ECLReportResponse {
+	var (
+		stages   = make(map[int8]*dtos.ECLReportStage)
+		response = dtos.ECLReportResponse{
+			AsOf:                asOfDate.Format(constants.DateLayout),
+			TotalExposureAmount: decimal.Zero,
+			TotalECLAmount:      decimal.Zero,
+			Stages:              make([]dtos.ECLReportStage, 0),
+			Loans:               reportLoans,
+		}
+	)
+	for stage := constants.ECLStage_Performing; stage <= constants.ECLStage_CreditImpaired; stage++ {
+		stages[int8(stage)] = &dtos.ECLReportStage{
+			Stage:          int8(stage),
+			ExposureAmount: decimal.Zero,
+			ECLAmount:      decimal.Zero,
+		}
+	}
+
+	for _, reportLoan := range reportLoans {
+		stages[reportLoan.Stage].LoanCount++
+		stages[reportLoan.Stage].ExposureAmount = stages[reportLoan.Stage].ExposureAmount.Add(reportLoan.ExposureAmount)
+		stages[reportLoan.Stage].ECLAmount = stages[reportLoan.Stage].ECLAmount.Add(reportLoan.ECLAmount)
+		response.TotalExposureAmount = response.TotalExposureAmount.Add(reportLoan.ExposureAmount)
+		response.TotalECLAmount = response.TotalECLAmount.Add(reportLoan.ECLAmount)
+	}
+
+	for stage := constants.ECLStage_Performing; stage <= constants.ECLStage_CreditImpaired; stage++ {
+		response.Stages = append(response.Stages, *stages[int8(stage)])
+	}
+	return &response
+}
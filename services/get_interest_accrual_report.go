@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// GetInterestAccrualReport reports the interest accrued so far and how much of it is not paid yet, of a loan or of every active loan
+func GetInterestAccrualReport(ctx context.Context, param dtos.GetInterestAccrualReportParam) (*dtos.InterestAccrualReportResponse, error) {
+	var (
+		lastLoanID int64
+
+		now      = time.Now()
+		response = dtos.InterestAccrualReportResponse{
+			TotalAccruedInterestAmount:       decimal.Zero,
+			TotalAccruedUnpaidInterestAmount: decimal.Zero,
+			Loans:                            make([]dtos.InterestAccrualReportLoan, 0),
+		}
+	)
+
+	addReportLoan := func(loanRequestModel dtos.LoanRequestModel) error {
+		billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+		if err != nil {
+			return err
+		}
+
+		reportLoan := dtos.InterestAccrualReportLoan{
+			LoanID:                      loanRequestModel.ID,
+			DaysPastDue:                 getDaysPastDue(billingModels, now),
+			IsNonAccrual:                isNonAccrualLoan(billingModels, now),
+			AccruedInterestAmount:       decimal.Zero,
+			InterestPaidAmount:          decimal.Zero,
+			AccruedUnpaidInterestAmount: decimal.Zero,
+		}
+		for _, billing := range billingModels {
+			if !isAccruableBilling(billing) {
+				continue
+			}
+			reportLoan.AccruedInterestAmount = reportLoan.AccruedInterestAmount.Add(billing.InterestAccruedAmount)
+			reportLoan.InterestPaidAmount = reportLoan.InterestPaidAmount.Add(billing.InterestPaidAmount)
+			// the interest paid in advance of a billing does not offset the unpaid interest of another one
+			reportLoan.AccruedUnpaidInterestAmount = reportLoan.AccruedUnpaidInterestAmount.Add(
+				decimal.Max(decimal.Zero, billing.InterestAccruedAmount.Sub(billing.InterestPaidAmount)))
+		}
+
+		response.TotalAccruedInterestAmount = response.TotalAccruedInterestAmount.Add(reportLoan.AccruedInterestAmount)
+		response.TotalAccruedUnpaidInterestAmount = response.TotalAccruedUnpaidInterestAmount.Add(reportLoan.AccruedUnpaidInterestAmount)
+		response.Loans = append(response.Loans, reportLoan)
+		return nil
+	}
+
+	if param.LoanID != 0 {
+		loanRequestModel, err := clients.DBGetLoanRequestByID(ctx, param.LoanID)
+		if err != nil {
+			return nil, err
+		}
+		if err = addReportLoan(*loanRequestModel); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(loanRequestModels) == 0 {
+			return &response, nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			if err = addReportLoan(loanRequestModel); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/dtos"
+)
+
+// GetLedgerBalances derives the account balances from the journal lines of a loan, or of every loan
+func GetLedgerBalances(ctx context.Context, param dtos.GetLedgerBalancesParam) (*dtos.LedgerBalancesResponse, error) {
+	balanceModels, err := clients.DBGetLedgerAccountBalances(ctx, param.LoanID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.LedgerBalancesResponse{
+		Accounts: make([]dtos.LedgerAccountBalance, 0, len(balanceModels)),
+	}
+	for _, balanceModel := range balanceModels {
+		balance := balanceModel.DebitAmount.Sub(balanceModel.CreditAmount)
+		if !balanceModel.Account.IsDebitNormal() {
+			balance = balance.Neg()
+		}
+		response.Accounts = append(response.Accounts, dtos.LedgerAccountBalance{
+			Account:      int8(balanceModel.Account),
+			DebitAmount:  balanceModel.DebitAmount,
+			CreditAmount: balanceModel.CreditAmount,
+			Balance:      balance,
+		})
+	}
+	return &response, nil
+}
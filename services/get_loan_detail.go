@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+func GetLoanDetail(ctx context.Context, param dtos.GetLoanDetailParam) (*dtos.LoanDetailResponse, error) {
+	if _, err := clients.DBGetUserByID(ctx, param.UserID); err != nil {
+		return nil, err
+	}
+
+	loanRequestModel, err := clients.DBGetLoanRequestByID(ctx, param.LoanID)
+	if err != nil {
+		return nil, err
+	}
+	if loanRequestModel.UserID != param.UserID {
+		return nil, constants.ErrRecordNotFound
+	}
+
+	billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeModels, err := clients.DBGetLoanFeesByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	restructureModels, err := clients.DBGetLoanRestructuresByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	restructureBillings, err := clients.DBGetLoanRestructureBillingsByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return nil, err
+	}
+	cancelledByRestructureIDs := make(map[string]int64, len(restructureBillings))
+	for _, restructureBilling := range restructureBillings {
+		cancelledByRestructureIDs[restructureBilling.BillingID] = restructureBilling.RestructureID
+	}
+
+	response := dtos.LoanDetailResponse{
+		LoanID:              loanRequestModel.ID,
+		UserID:              loanRequestModel.UserID,
+		LoanAmount:          loanRequestModel.LoanAmount,
+		DisbursedAmount:     loanRequestModel.DisbursedAmount,
+		PrincipalPaidAmount: loanRequestModel.PrincipalPaidAmount,
+		InterestPaidAmount:  loanRequestModel.InterestPaidAmount,
+		PenaltyPaidAmount:   loanRequestModel.PenaltyPaidAmount,
+		FeePaidAmount:       loanRequestModel.FeePaidAmount,
+		DisbursementTime:    loanRequestModel.DisbursementTime,
+		TenureValue:         loanRequestModel.TenureValue,
+		TenureUnit:          loanRequestModel.TenureUnit,
+		Status:              loanRequestModel.Status,
+		AnnualInterestRate:  loanRequestModel.AnnualInterestRate,
+		EffectiveAPR:        loanRequestModel.EffectiveAPR,
+		TotalCostOfCredit:   loanRequestModel.TotalCostOfCredit,
+		Fees:                make([]dtos.LoanDetailFee, 0, len(feeModels)),
+		Restructures:        make([]dtos.LoanDetailRestructure, 0, len(restructureModels)),
+		Billings:            make([]dtos.LoanDetailBilling, 0, len(billingModels)),
+	}
+	for _, fee := range feeModels {
+		response.Fees = append(response.Fees, dtos.LoanDetailFee{
+			FeeType:    fee.FeeType,
+			Amount:     fee.Amount,
+			ChargeType: fee.ChargeType,
+		})
+	}
+	for _, restructure := range restructureModels {
+		response.Restructures = append(response.Restructures, dtos.LoanDetailRestructure{
+			RestructureID:              restructure.ID,
+			TenureValue:                restructure.TenureValue,
+			TenureUnit:                 restructure.TenureUnit,
+			AnnualInterestRate:         restructure.AnnualInterestRate,
+			AmortizationMethod:         restructure.AmortizationMethod,
+			OutstandingPrincipalAmount: restructure.OutstandingPrincipalAmount,
+			CapitalisedArrearsAmount:   restructure.CapitalisedArrearsAmount,
+			FirstRecurringIndex:        restructure.FirstRecurringIndex,
+			Reason:                     restructure.Reason,
+			CreatedAt:                  restructure.CreatedAt,
+		})
+	}
+	for _, billing := range billingModels {
+		response.Billings = append(response.Billings, dtos.LoanDetailBilling{
+			BillingID:                billing.BillingID,
+			RecurringIndex:           billing.RecurringIndex,
+			RestructureID:            billing.RestructureID,
+			CancelledByRestructureID: cancelledByRestructureIDs[billing.BillingID],
+			PrincipalAmount:          billing.PrincipalAmount,
+			InterestAmount:           billing.InterestAmount,
+			PenaltyAmount:            billing.PenaltyAmount,
+			FeeAmount:                billing.FeeAmount,
+			TotalAmount:              billing.TotalAmount,
+			PaidAmount:               billing.GetPaidAmount(),
+			DueTime:                  billing.DueTime,
+			PaymentCompletedAt:       billing.PaymentCompletedAt,
+			Status:                   billing.Status,
+		})
+	}
+	return &response, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+type loanProduct struct {
+	dtos.LoanProductModel
+	rates []dtos.LoanProductRateModel
+	fees  []dtos.LoanProductFeeModel
+}
+
+func GetLoanProduct(ctx context.Context, param dtos.GetLoanProductParam) (*dtos.LoanProductResponse, error) {
+	product, err := getLoanProductByCode(ctx, param.ProductCode)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.LoanProductResponse{
+		ProductCode:               product.ProductCode,
+		Name:                      product.Name,
+		MinLoanAmount:             product.MinLoanAmount,
+		MaxLoanAmount:             product.MaxLoanAmount,
+		AllowedTenureUnits:        product.getAllowedTenureUnits(),
+		MinTenureValue:            product.MinTenureValue,
+		MaxTenureValue:            product.MaxTenureValue,
+		AnnualInterestRate:        product.AnnualInterestRate,
+		AmortizationMethod:        product.AmortizationMethod,
+		LatePenaltyRate:           product.LatePenaltyRate,
+		LatePenaltyGraceDays:      product.LatePenaltyGraceDays,
+		DelinquentOverdueBillings: product.DelinquentOverdueBillings,
+		Status:                    product.Status,
+		Rates:                     make([]dtos.LoanProductRate, 0, len(product.rates)),
+		Fees:                      make([]dtos.LoanProductFee, 0, len(product.fees)),
+	}
+	for _, rate := range product.rates {
+		response.Rates = append(response.Rates, dtos.LoanProductRate{
+			TenureUnit:         rate.TenureUnit,
+			MinTenureValue:     rate.MinTenureValue,
+			MaxTenureValue:     rate.MaxTenureValue,
+			AnnualInterestRate: rate.AnnualInterestRate,
+		})
+	}
+	for _, fee := range product.fees {
+		response.Fees = append(response.Fees, dtos.LoanProductFee{
+			FeeType:         fee.FeeType,
+			CalculationType: fee.CalculationType,
+			Value:           fee.Value,
+			ChargeType:      fee.ChargeType,
+		})
+	}
+	return &response, nil
+}
+
+func getLoanProductByCode(ctx context.Context, productCode string) (*loanProduct, error) {
+	productModel, err := clients.DBGetLoanProductByCode(ctx, productCode)
+	if err != nil {
+		return nil, err
+	}
+	return completeLoanProduct(ctx, productModel)
+}
+
+func getLoanProductByID(ctx context.Context, productID int64) (*loanProduct, error) {
+	productModel, err := clients.DBGetLoanProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return completeLoanProduct(ctx, productModel)
+}
+
+func completeLoanProduct(ctx context.Context, productModel *dtos.LoanProductModel) (*loanProduct, error) {
+	rateModels, err := clients.DBGetLoanProductRatesByProductID(ctx, productModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeModels, err := clients.DBGetLoanProductFeesByProductID(ctx, productModel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loanProduct{
+		LoanProductModel: *productModel,
+		rates:            rateModels,
+		fees:             feeModels,
+	}, nil
+}
+
+func (p *loanProduct) getAllowedTenureUnits() []constants.TenureUnit {
+	var tenureUnits []constants.TenureUnit
+	for _, value := range strings.Split(p.AllowedTenureUnits, ",") {
+		tenureUnit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 8)
+		if err != nil {
+			continue
+		}
+		tenureUnits = append(tenureUnits, constants.TenureUnit(tenureUnit))
+	}
+	return tenureUnits
+}
+
+func (p *loanProduct) isTenureUnitAllowed(tenureUnit constants.TenureUnit) bool {
+	for _, allowedTenureUnit := range p.getAllowedTenureUnits() {
+		if allowedTenureUnit == tenureUnit {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *loanProduct) getAnnualInterestRate(tenureValue int, tenureUnit constants.TenureUnit) decimal.Decimal {
+	for _, rate := range p.rates {
+		if rate.TenureUnit == tenureUnit && rate.MinTenureValue <= tenureValue && tenureValue <= rate.MaxTenureValue {
+			return rate.AnnualInterestRate
+		}
+	}
+	return p.AnnualInterestRate
+}
+
+func (p *loanProduct) getDelinquentOverdueBillings() int {
+	if p == nil {
+		return constants.DefaultDelinquentOverdueBillings
+	}
+	return p.DelinquentOverdueBillings
+}
+
+func calculateFeeAmount(calculationType constants.FeeCalculationType, value, loanAmount decimal.Decimal) decimal.Decimal {
+	if calculationType == constants.FeeCalculationType_Percentage {
+		return loanAmount.Mul(value).Div(constants.Percent).Round(constants.AmountDecimalPlaces)
+	}
+	return value
+}
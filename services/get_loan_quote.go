@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// GetLoanQuote simulates a loan request disbursed now without persisting anything, user_id is not required
+func GetLoanQuote(ctx context.Context, param dtos.CreateLoanRequestParam) (*dtos.LoanQuoteResponse, error) {
+	product, err := getLoanRequestProduct(ctx, param.ProductCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateLoanTerms(param, product); err != nil {
+		return nil, err
+	}
+
+	var (
+		now       = time.Now().UnixMilli()
+		feeModels = calculateLoanFees(param, product, now)
+		loanModel = newLoanRequestModel(param, product, feeModels, now)
+	)
+	billingModels, _, err := scheduleLoanRequest(&loanModel, feeModels, param.Installments)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.LoanQuoteResponse{
+		LoanAmount:        loanModel.LoanAmount,
+		DisbursedAmount:   loanModel.DisbursedAmount,
+		TotalFeeAmount:    getLoanFeeAmount(feeModels),
+		EffectiveAPR:      loanModel.EffectiveAPR,
+		TotalCostOfCredit: loanModel.TotalCostOfCredit,
+		Installments:      make([]dtos.LoanQuoteInstallment, 0, len(billingModels)),
+	}
+	for _, billing := range billingModels {
+		response.TotalInterestAmount = response.TotalInterestAmount.Add(billing.InterestAmount)
+		response.TotalRepaymentAmount = response.TotalRepaymentAmount.Add(billing.TotalAmount)
+		response.Installments = append(response.Installments, dtos.LoanQuoteInstallment{
+			RecurringIndex:  billing.RecurringIndex,
+			DueTime:         billing.DueTime,
+			PrincipalAmount: billing.PrincipalAmount,
+			InterestAmount:  billing.InterestAmount,
+			FeeAmount:       billing.FeeAmount,
+			TotalAmount:     billing.TotalAmount,
+		})
+	}
+	return &response, nil
+}
+
+// calculateCreditCost returns the effective APR (inflated by 10^2) and the total cost of credit,
+// which is everything the borrower repays on top of the money actually received
+func calculateCreditCost(loanModel dtos.LoanRequestModel, billingModels []dtos.BillingModel) (decimal.Decimal, decimal.Decimal, error) {
+	var (
+		totalRepaymentAmount decimal.Decimal
+
+		netDisbursementAmount = loanModel.DisbursedAmount
+		cashFlows             = []utils.CashFlow{
+			{
+				Time:   time.UnixMilli(loanModel.DisbursementTime),
+				Amount: netDisbursementAmount.Neg(),
+			},
+		}
+	)
+
+	for _, billing := range billingModels {
+		totalRepaymentAmount = totalRepaymentAmount.Add(billing.TotalAmount)
+		cashFlows = append(cashFlows, utils.CashFlow{
+			Time:   time.UnixMilli(billing.DueTime),
+			Amount: billing.TotalAmount,
+		})
+	}
+
+	effectiveAPR, err := utils.CalculateEffectiveAnnualRate(cashFlows)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	return effectiveAPR, totalRepaymentAmount.Sub(netDisbursementAmount), nil
+}
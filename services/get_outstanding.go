@@ -20,11 +20,21 @@ func GetOutstanding(ctx context.Context, param dtos.GetOutstandingParam) (decima
 		return decimal.Zero, err
 	}
 
-	if loanRequestModel.Status == constants.LoanStatus_Completed {
+	if !loanRequestModel.Status.IsDisbursed() || loanRequestModel.Status == constants.LoanStatus_Completed {
 		return decimal.Zero, nil
 	}
 
-	outstandingPrincipal := loanRequestModel.LoanAmount.Sub(loanRequestModel.PrincipalPaidAmount)
-	outstandingInterest := outstandingPrincipal.Mul(loanRequestModel.AnnualInterestRate).Div(constants.Percent)
-	return outstandingPrincipal.Add(outstandingInterest), nil
+	billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	// waived, cancelled and written off billings are not collected anymore
+	outstandingAmount := decimal.Zero
+	for _, billing := range billingModels {
+		if billing.Status.IsOpen() {
+			outstandingAmount = outstandingAmount.Add(billing.GetUnpaidAmount())
+		}
+	}
+	return outstandingAmount, nil
 }
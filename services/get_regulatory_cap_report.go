@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// GetRegulatoryCapReport lists the active loans whose interest, fees or penalties are close to the regulatory caps
+func GetRegulatoryCapReport(ctx context.Context) (*dtos.RegulatoryCapReportResponse, error) {
+	var (
+		lastLoanID int64
+
+		regulatoryCap = configs.Get().RegulatoryCap
+		response      = dtos.RegulatoryCapReportResponse{
+			MaxDailyEconomicBenefitRate: regulatoryCap.MaxDailyEconomicBenefitRate,
+			MaxTotalPenaltyRate:         regulatoryCap.MaxTotalPenaltyRate,
+			NearCapThreshold:            regulatoryCap.NearCapThreshold,
+			Loans:                       make([]dtos.RegulatoryCapReportLoan, 0),
+		}
+	)
+
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(loanRequestModels) == 0 {
+			return &response, nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+
+			feeModels, err := clients.DBGetLoanFeesByLoanID(ctx, loanRequestModel.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			reportLoan := dtos.RegulatoryCapReportLoan{
+				LoanID:                   loanRequestModel.ID,
+				UserID:                   loanRequestModel.UserID,
+				DailyEconomicBenefitRate: getDailyEconomicBenefitRate(loanRequestModel, billingModels, getLoanFeeAmount(feeModels)),
+			}
+			for _, billing := range billingModels {
+				reportLoan.PenaltyAmount = reportLoan.PenaltyAmount.Add(billing.PenaltyAmount)
+			}
+			reportLoan.PenaltyCapAmount, _ = getTotalPenaltyCapAmount(loanRequestModel)
+
+			reportLoan.DailyEconomicBenefitUsage = getCapUsage(reportLoan.DailyEconomicBenefitRate, regulatoryCap.MaxDailyEconomicBenefitRate)
+			reportLoan.PenaltyUsage = getCapUsage(reportLoan.PenaltyAmount, reportLoan.PenaltyCapAmount)
+			if isNearCap(reportLoan.DailyEconomicBenefitUsage, regulatoryCap.NearCapThreshold) ||
+				isNearCap(reportLoan.PenaltyUsage, regulatoryCap.NearCapThreshold) {
+				response.Loans = append(response.Loans, reportLoan)
+			}
+		}
+	}
+}
+
+// getCapUsage returns the usage (inflated by 10^2) of the cap, zero when the cap is disabled
+func getCapUsage(value, capValue decimal.Decimal) decimal.Decimal {
+	if !capValue.IsPositive() {
+		return decimal.Zero
+	}
+	return value.Div(capValue).Mul(constants.Percent).Round(constants.AmountDecimalPlaces)
+}
+
+func isNearCap(usage, threshold decimal.Decimal) bool {
+	return usage.IsPositive() && usage.GreaterThanOrEqual(threshold)
+}
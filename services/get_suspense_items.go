@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+const (
+	defaultSuspenseItemsLimit = 20
+	maxSuspenseItemsLimit     = 100
+)
+
+// GetSuspenseItems lists the suspense items from the latest for reconciliation, paged by the last item id
+func GetSuspenseItems(ctx context.Context, param dtos.GetSuspenseItemsParam) (*dtos.SuspenseItemsResponse, error) {
+	if param.Status != 0 && !constants.SuspenseItemStatus(param.Status).IsValid() {
+		return nil, fmt.Errorf("%w. status", constants.ErrInvalidValue)
+	}
+	if param.Limit < 0 || param.Limit > maxSuspenseItemsLimit {
+		return nil, fmt.Errorf("%w. limit should be between 0 and %d", constants.ErrInvalidValue, maxSuspenseItemsLimit)
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultSuspenseItemsLimit
+	}
+
+	itemModels, err := clients.DBGetSuspenseItems(ctx, constants.SuspenseItemStatus(param.Status), param.LastItemID, param.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.SuspenseItemsResponse{
+		Items: make([]dtos.SuspenseItemResponse, 0, len(itemModels)),
+	}
+	for _, itemModel := range itemModels {
+		response.Items = append(response.Items, newSuspenseItemResponse(itemModel))
+	}
+	return &response, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 20
+	maxWebhookDeliveriesLimit     = 100
+)
+
+// GetWebhookDeliveries lists a subscription's deliveries from the latest, paged by the last delivery id
+func GetWebhookDeliveries(ctx context.Context, param dtos.GetWebhookDeliveriesParam) (*dtos.WebhookDeliveriesResponse, error) {
+	if param.Status != 0 && !constants.WebhookDeliveryStatus(param.Status).IsValid() {
+		return nil, fmt.Errorf("%w. status", constants.ErrInvalidValue)
+	}
+	if param.Limit < 0 || param.Limit > maxWebhookDeliveriesLimit {
+		return nil, fmt.Errorf("%w. limit should be between 0 and %d", constants.ErrInvalidValue, maxWebhookDeliveriesLimit)
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultWebhookDeliveriesLimit
+	}
+
+	if _, err := clients.DBGetWebhookSubscriptionByID(ctx, param.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := clients.DBGetWebhookDeliveriesBySubscriptionID(ctx, param.SubscriptionID, constants.WebhookDeliveryStatus(param.Status), param.LastDeliveryID, param.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dtos.WebhookDeliveriesResponse{
+		Deliveries: make([]dtos.WebhookDeliveryResponse, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, newWebhookDeliveryResponse(delivery))
+	}
+	return &response, nil
+}
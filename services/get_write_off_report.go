@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// GetWriteOffReport lists the loans written off within the period along with their recoveries so far
+func GetWriteOffReport(ctx context.Context, param dtos.GetWriteOffReportParam) (*dtos.WriteOffReportResponse, error) {
+	if param.EndTime <= param.StartTime {
+		return nil, fmt.Errorf("%w. end_time should be after start_time", constants.ErrInvalidValue)
+	}
+
+	var (
+		lastWriteOffID int64
+
+		response = dtos.WriteOffReportResponse{
+			TotalWrittenOffAmount: decimal.Zero,
+			TotalRecoveredAmount:  decimal.Zero,
+			Loans:                 make([]dtos.WriteOffReportLoan, 0),
+		}
+	)
+
+	for {
+		writeOffModels, err := clients.DBGetLoanWriteOffsByCreatedTime(ctx, param.StartTime, param.EndTime, lastWriteOffID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(writeOffModels) == 0 {
+			return &response, nil
+		}
+
+		for _, writeOffModel := range writeOffModels {
+			lastWriteOffID = writeOffModel.ID
+
+			recoveredAmount, err := clients.DBGetLoanRecoveredAmount(ctx, nil, writeOffModel.LoanID)
+			if err != nil {
+				return nil, err
+			}
+
+			reportLoan := dtos.WriteOffReportLoan{
+				LoanID:           writeOffModel.LoanID,
+				PrincipalAmount:  writeOffModel.PrincipalAmount,
+				InterestAmount:   writeOffModel.InterestAmount,
+				PenaltyAmount:    writeOffModel.PenaltyAmount,
+				FeeAmount:        writeOffModel.FeeAmount,
+				WrittenOffAmount: writeOffModel.GetTotalAmount(),
+				RecoveredAmount:  recoveredAmount,
+				WrittenOffAt:     writeOffModel.CreatedAt,
+			}
+			response.TotalWrittenOffAmount = response.TotalWrittenOffAmount.Add(reportLoan.WrittenOffAmount)
+			response.TotalRecoveredAmount = response.TotalRecoveredAmount.Add(reportLoan.RecoveredAmount)
+			response.Loans = append(response.Loans, reportLoan)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// HandleVirtualAccountCallback verifies the provider's paid callback and posts it as a payment of the account's loan.
+// The provider has settled the cash already, so the part that can not be posted, e.g. exceeding the outstanding amount
+// or paid to an unknown account, is held in suspense for reconciliation and the callback is still acknowledged.
+// A redelivered callback is posted once and returns the recorded result
+func HandleVirtualAccountCallback(ctx context.Context, param dtos.VirtualAccountCallbackParam) (*dtos.VirtualAccountPaymentResponse, error) {
+	callback, err := clients.GetPaymentProvider().ParsePaidCallback(param.Timestamp, param.Signature, param.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateVirtualAccountCallback(callback); err != nil {
+		return nil, err
+	}
+
+	paymentModel := dtos.VirtualAccountPaymentModel{
+		Provider:   configs.Get().PaymentProvider.Name,
+		CallbackID: callback.CallbackID,
+		Amount:     callback.Amount,
+		PaidAt:     callback.PaidAt,
+		Payload:    string(param.Body),
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	if err = postVirtualAccountPayment(ctx, txn, callback, &paymentModel); err != nil {
+		return nil, err
+	}
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, err
+	}
+
+	if paymentModel.Status != constants.VirtualAccountPaymentStatus_Posted {
+		logrus.Warnf("virtual account payment %s of %s is held in suspense item %d. %s",
+			callback.CallbackID, callback.VANumber, paymentModel.SuspenseItemID, paymentModel.SuspenseReason)
+	}
+	response := newVirtualAccountPaymentResponse(paymentModel)
+	return &response, nil
+}
+
+func postVirtualAccountPayment(ctx context.Context, txn *sqlx.Tx, callback *dtos.VirtualAccountPaidCallback, paymentModel *dtos.VirtualAccountPaymentModel) error {
+	// serialize the account's callbacks with pessimistic lock
+	virtualAccount, err := clients.DBGetVirtualAccountByNumberForUpdate(ctx, txn, paymentModel.Provider, callback.BankCode, callback.VANumber)
+	if err != nil && err != constants.ErrRecordNotFound {
+		return err
+	}
+
+	if storedModel, err := clients.DBGetVirtualAccountPaymentByCallbackID(ctx, txn, paymentModel.Provider, paymentModel.CallbackID); err == nil {
+		*paymentModel = *storedModel
+		return nil
+	} else if err != constants.ErrRecordNotFound {
+		return err
+	}
+
+	var (
+		userID       int64
+		postedAmount = decimal.Zero
+	)
+	if virtualAccount == nil {
+		paymentModel.Status = constants.VirtualAccountPaymentStatus_Unmatched
+		paymentModel.SuspenseReason = "virtual account is not found"
+	} else {
+		userID = virtualAccount.UserID
+		paymentModel.VirtualAccountID = virtualAccount.ID
+		paymentModel.LoanID = virtualAccount.LoanID
+
+		if postedAmount, paymentModel.SuspenseReason, err = getVirtualAccountPostableAmount(ctx, txn, virtualAccount, callback.Amount); err != nil {
+			return err
+		}
+		if postedAmount.IsPositive() {
+			if paymentModel.PaymentID, err = makePayment(ctx, txn, dtos.MakePaymentParam{
+				UserID: virtualAccount.UserID,
+				LoanID: virtualAccount.LoanID,
+				Amount: postedAmount.String(),
+			}); err != nil {
+				return err
+			}
+		}
+
+		paymentModel.Status = constants.VirtualAccountPaymentStatus_Posted
+		if postedAmount.LessThan(callback.Amount) {
+			paymentModel.Status = constants.VirtualAccountPaymentStatus_Suspended
+		}
+
+		// a billing's account takes a single payment
+		if virtualAccount.BillingID != "" && virtualAccount.Status == constants.VirtualAccountStatus_Active {
+			if err = clients.DBUpdateVirtualAccountStatusByID(ctx, txn, virtualAccount.ID, constants.VirtualAccountStatus_Inactive); err != nil {
+				return err
+			}
+		}
+	}
+
+	if suspendedAmount := callback.Amount.Sub(postedAmount); suspendedAmount.IsPositive() {
+		if paymentModel.SuspenseItemID, err = holdInSuspense(ctx, txn, dtos.SuspenseItemModel{
+			Source:      constants.SuspenseSource_VirtualAccount,
+			ReferenceID: paymentModel.CallbackID,
+			UserID:      userID,
+			LoanID:      paymentModel.LoanID,
+			Amount:      suspendedAmount,
+			Reason:      paymentModel.SuspenseReason,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// an unmatched callback is not serialized by the account lock, its concurrent redelivery fails on the unique keys
+	// and is answered from the recorded payment once the provider redelivers it again
+	inserted, err := clients.DBInsertVirtualAccountPayment(ctx, txn, paymentModel)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return fmt.Errorf("virtual account payment %s is recorded concurrently", paymentModel.CallbackID)
+	}
+	return nil
+}
+
+// getVirtualAccountPostableAmount returns the part of the amount the account's loan takes as a payment,
+// and the reason the rest of it is held in suspense
+func getVirtualAccountPostableAmount(ctx context.Context, tx *sqlx.Tx, virtualAccount *dtos.VirtualAccountModel, amount decimal.Decimal) (decimal.Decimal, string, error) {
+	if virtualAccount.Status != constants.VirtualAccountStatus_Active {
+		return decimal.Zero, "virtual account is inactive", nil
+	}
+	if virtualAccount.ExpectedAmount.IsPositive() && !amount.Equal(virtualAccount.ExpectedAmount) {
+		return decimal.Zero, fmt.Sprintf("amount should be %s", virtualAccount.ExpectedAmount.String()), nil
+	}
+
+	// prevent update racing with pessimistic lock, in the same order as the payment takes the locks
+	loanRequestModel, err := clients.DBGetLoanRequestByIDAndUserIDForUpdate(ctx, tx, virtualAccount.LoanID, virtualAccount.UserID)
+	if err != nil {
+		return decimal.Zero, "", err
+	}
+	if !loanRequestModel.Status.IsDisbursed() {
+		return decimal.Zero, "loan request is not disbursed yet", nil
+	}
+
+	var payableAmount decimal.Decimal
+	if loanRequestModel.Status == constants.LoanStatus_WrittenOff {
+		if payableAmount, err = getUnrecoveredAmount(ctx, tx, loanRequestModel.ID); err != nil {
+			return decimal.Zero, "", err
+		}
+	} else {
+		openBillings, err := getPayableBillingsForUpdate(ctx, tx, loanRequestModel.ID)
+		if err != nil {
+			return decimal.Zero, "", err
+		}
+		for _, billing := range openBillings {
+			payableAmount = payableAmount.Add(billing.GetUnpaidAmount())
+		}
+	}
+
+	if amount.GreaterThan(payableAmount) {
+		return decimal.Max(payableAmount, decimal.Zero), fmt.Sprintf("amount exceeds the outstanding amount %s", payableAmount.String()), nil
+	}
+	return amount, "", nil
+}
+
+// validateVirtualAccountCallback rejects the malformed callbacks, they carry no cash the ledger can hold
+func validateVirtualAccountCallback(callback *dtos.VirtualAccountPaidCallback) error {
+	if callback.CallbackID == "" || callback.VANumber == "" {
+		return fmt.Errorf("%w. callback_id and va_number are required", constants.ErrInvalidValue)
+	}
+	if !callback.Amount.IsPositive() || !callback.Amount.Equal(callback.Amount.Round(constants.AmountDecimalPlaces)) {
+		return fmt.Errorf("%w. amount should be greater than 0 with at most %d decimal places", constants.ErrInvalidValue, constants.AmountDecimalPlaces)
+	}
+	return nil
+}
+
+func newVirtualAccountPaymentResponse(paymentModel dtos.VirtualAccountPaymentModel) dtos.VirtualAccountPaymentResponse {
+	return dtos.VirtualAccountPaymentResponse{
+		CallbackID:       paymentModel.CallbackID,
+		VirtualAccountID: paymentModel.VirtualAccountID,
+		LoanID:           paymentModel.LoanID,
+		Amount:           paymentModel.Amount,
+		PaymentID:        paymentModel.PaymentID,
+		SuspenseItemID:   paymentModel.SuspenseItemID,
+		Status:           int8(paymentModel.Status),
+		SuspenseReason:   paymentModel.SuspenseReason,
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// getDaysPastDue counts the days since the oldest billing open as of now is due,
+// a billing paid off after now was still open then, e.g. when an earlier date is accrued again
+func getDaysPastDue(billingModels []dtos.BillingModel, now time.Time) int {
+	daysPastDue := 0
+	for _, billing := range billingModels {
+		isOpen := billing.Status.IsOpen() ||
+			(billing.Status == constants.PaymentStatus_Completed && billing.PaymentCompletedAt >= now.UnixMilli())
+		if !isOpen || billing.DueTime >= now.UnixMilli() {
+			continue
+		}
+		if days := utils.GetDaysBetween(time.UnixMilli(billing.DueTime), now); days > daysPastDue {
+			daysPastDue = days
+		}
+	}
+	return daysPastDue
+}
+
+// isNonAccrualLoan tells whether the loan is non-performing, its interest is no longer accrued daily but recognised once paid
+func isNonAccrualLoan(billingModels []dtos.BillingModel, now time.Time) bool {
+	nonAccrualDaysPastDue := configs.Get().InterestAccrual.NonAccrualDaysPastDue
+	return nonAccrualDaysPastDue > 0 && getDaysPastDue(billingModels, now) > nonAccrualDaysPastDue
+}
+
+// isAccruableBilling tells whether the billing's interest is still earned, the closed unpaid billings are settled on closing
+func isAccruableBilling(billing dtos.BillingModel) bool {
+	return billing.Status.IsOpen() || billing.Status == constants.PaymentStatus_Completed
+}
+
+// getBillingPeriodStartTimes maps the billings to the start of their interest period: the previous due time of the same schedule,
+// or when the schedule starts, i.e. the disbursement or the restructuring time
+func getBillingPeriodStartTimes(loanModel dtos.LoanRequestModel, billingModels []dtos.BillingModel, restructureModels []dtos.LoanRestructureModel) map[int64]int64 {
+	scheduleStartTimes := map[int64]int64{0: loanModel.DisbursementTime}
+	for _, restructure := range restructureModels {
+		scheduleStartTimes[restructure.ID] = restructure.CreatedAt
+	}
+
+	// the billings are ordered by due time
+	periodStartTimes := make(map[int64]int64, len(billingModels))
+	lastDueTimes := make(map[int64]int64)
+	for _, billing := range billingModels {
+		startTime, ok := lastDueTimes[billing.RestructureID]
+		if !ok {
+			startTime = scheduleStartTimes[billing.RestructureID]
+		}
+		periodStartTimes[billing.ID] = startTime
+		lastDueTimes[billing.RestructureID] = billing.DueTime
+	}
+	return periodStartTimes
+}
+
+// getEarnedInterestAmount spreads the billing's interest evenly over the days of its period, up to the end of accrualDate.
+// the period's start date is its first earned day and the due date is not, so the interest is fully earned the day before it is due
+func getEarnedInterestAmount(billing dtos.BillingModel, periodStartTime int64, accrualDate time.Time) decimal.Decimal {
+	periodStart := time.UnixMilli(periodStartTime).In(accrualDate.Location())
+	periodDays := utils.GetDaysBetween(periodStart, time.UnixMilli(billing.DueTime))
+	earnedDays := utils.GetDaysBetween(periodStart, accrualDate) + 1
+	if periodDays <= 0 || earnedDays >= periodDays {
+		return billing.InterestAmount
+	}
+	if earnedDays <= 0 {
+		return decimal.Zero
+	}
+	return billing.InterestAmount.
+		Mul(decimal.NewFromInt(int64(earnedDays))).
+		Div(decimal.NewFromInt(int64(periodDays))).
+		Round(constants.AmountDecimalPlaces)
+}
+
+// settleBillingAccruals recognises exactly the paid interest of the closed billings, it reverses the accrued interest
+// that is no longer receivable and recognises the interest paid in advance.
+// It has to be called after the billings' journal entry is built, since that relies on the accrued amount before settling
+func settleBillingAccruals(ctx context.Context, tx *sqlx.Tx, billingModels []dtos.BillingModel, now time.Time) error {
+	accrualModels := make([]dtos.InterestAccrualModel, 0, len(billingModels))
+	for _, billing := range billingModels {
+		amount := billing.InterestPaidAmount.Sub(billing.InterestAccruedAmount)
+		if amount.IsZero() {
+			continue
+		}
+
+		if err := clients.DBUpdateBillingInterestAccruedByID(ctx, tx, billing.ID, billing.InterestPaidAmount); err != nil {
+			return err
+		}
+		accrualModels = append(accrualModels, dtos.InterestAccrualModel{
+			LoanID:      billing.LoanID,
+			BillingID:   billing.BillingID,
+			AccrualDate: utils.GetStartOfDay(now).UnixMilli(),
+			Amount:      amount,
+			Source:      constants.InterestAccrualSource_Settlement,
+			CreatedAt:   now.UnixMilli(),
+		})
+	}
+
+	if len(accrualModels) == 0 {
+		return nil
+	}
+	return clients.DBBatchInsertInterestAccruals(ctx, tx, accrualModels)
+}
+
+// completeLoanRequest closes a loan without any open billing left,
+// the interest paid in advance of the upcoming due times is recognised at once
+func completeLoanRequest(ctx context.Context, tx *sqlx.Tx, loanRequestModel *dtos.LoanRequestModel, actor, reason string) error {
+	billingModels, err := clients.DBGetBillingsByLoanIDForUpdate(ctx, tx, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now = time.Now()
+
+		completedBillings []dtos.BillingModel
+		journalEntry      = newJournalEntry(constants.JournalEntryType_InterestAccrual, loanRequestModel.ID, loanRequestModel.ID, "loan completion")
+	)
+	for _, billing := range billingModels {
+		if billing.Status != constants.PaymentStatus_Completed {
+			continue
+		}
+		completedBillings = append(completedBillings, billing)
+		journalEntry.transfer(constants.LedgerAccount_UnearnedInterest, constants.LedgerAccount_InterestIncome, billing.InterestPaidAmount.Sub(billing.InterestAccruedAmount))
+	}
+
+	if err = postJournalEntry(ctx, tx, journalEntry); err != nil {
+		return err
+	}
+
+	if err = settleBillingAccruals(ctx, tx, completedBillings, now); err != nil {
+		return err
+	}
+	return transitionLoanStatus(ctx, tx, loanRequestModel, constants.LoanStatus_Completed, actor, reason)
+}
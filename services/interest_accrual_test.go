@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetEarnedInterestAmount(t *testing.T) {
+	var (
+		location     = time.FixedZone("WIB", 7*60*60)
+		periodStart  = time.Date(2026, 1, 5, 10, 0, 0, 0, location)
+		billingModel = dtos.BillingModel{
+			InterestAmount: decimal.NewFromInt(3100),
+			DueTime:        utils.GetEndOfDay(time.Date(2026, 2, 5, 0, 0, 0, 0, location)).UnixMilli(), // 31 days period
+		}
+	)
+
+	tests := []struct {
+		name        string
+		accrualDate time.Time
+		want        string
+	}{
+		{name: "day before the period", accrualDate: time.Date(2026, 1, 4, 0, 0, 0, 0, location), want: "0"},
+		{name: "first day of the period", accrualDate: time.Date(2026, 1, 5, 0, 0, 0, 0, location), want: "100"},
+		{name: "second day of the period", accrualDate: time.Date(2026, 1, 6, 0, 0, 0, 0, location), want: "200"},
+		{name: "day before the due date", accrualDate: time.Date(2026, 2, 4, 0, 0, 0, 0, location), want: "3100"},
+		{name: "due date", accrualDate: time.Date(2026, 2, 5, 0, 0, 0, 0, location), want: "3100"},
+		{name: "after the due date", accrualDate: time.Date(2026, 3, 1, 0, 0, 0, 0, location), want: "3100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getEarnedInterestAmount(billingModel, periodStart.UnixMilli(), tt.accrualDate)
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("getEarnedInterestAmount() = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEarnedInterestAmountFollowsPreviousDueTime(t *testing.T) {
+	var (
+		previousDueTime = utils.GetEndOfDay(time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC))
+		billingModel    = dtos.BillingModel{
+			InterestAmount: decimal.NewFromInt(2800),
+			DueTime:        utils.GetEndOfDay(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)).UnixMilli(), // 28 days period
+		}
+	)
+
+	// the previous period is fully earned the day before its due date, so the due date is the next period's first day
+	got := getEarnedInterestAmount(billingModel, previousDueTime.UnixMilli(), time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC))
+	if !got.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("getEarnedInterestAmount() = %s, want 100", got.String())
+	}
+}
+
+func TestIsNonAccrualLoanAsOfAccrualDate(t *testing.T) {
+	initTestConfig(t)
+
+	var (
+		dueTime      = utils.GetEndOfDay(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC))
+		paidAt       = time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+		billingModel = dtos.BillingModel{
+			DueTime:            dueTime.UnixMilli(),
+			PaymentCompletedAt: paidAt.UnixMilli(),
+			Status:             constants.PaymentStatus_Completed,
+		}
+	)
+
+	tests := []struct {
+		name             string
+		endOfAccrualDate time.Time
+		want             bool
+	}{
+		{name: "within the threshold", endOfAccrualDate: dueTime.AddDate(0, 0, 30), want: false},
+		// re-accruing a date when the billing was still unpaid keeps the loan non-accrual
+		{name: "past the threshold before the payment", endOfAccrualDate: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "after the payment", endOfAccrualDate: time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNonAccrualLoan([]dtos.BillingModel{billingModel}, tt.endOfAccrualDate); got != tt.want {
+				t.Errorf("isNonAccrualLoan() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,12 +8,31 @@ import (
 )
 
 func IsDelinquent(ctx context.Context, param dtos.IsDelinquentParam) (bool, error) {
+	loanRequestModel, err := clients.DBGetLoanRequestByID(ctx, param.LoanID)
+	if err != nil {
+		return false, err
+	}
+
 	overdueBillings, err := clients.DBGetOverdueBillings(ctx, param.LoanID)
 	if err != nil {
 		return false, err
 	}
+	return isDelinquentLoan(ctx, *loanRequestModel, overdueBillings)
+}
+
+// isDelinquentLoan tells whether the loan has more overdue billings than its product tolerates
+func isDelinquentLoan(ctx context.Context, loanRequestModel dtos.LoanRequestModel, overdueBillings []dtos.BillingModel) (bool, error) {
+	var (
+		product *loanProduct
+		err     error
+	)
+	if loanRequestModel.ProductID > 0 {
+		if product, err = getLoanProductByID(ctx, loanRequestModel.ProductID); err != nil {
+			return false, err
+		}
+	}
 
-	if len(overdueBillings) > 2 {
+	if len(overdueBillings) > product.getDelinquentOverdueBillings() {
 		return true, nil
 	}
 	return false, nil
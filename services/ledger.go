@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+type journalEntry struct {
+	model dtos.JournalEntryModel
+	lines []dtos.JournalLineModel
+}
+
+func newJournalEntry(entryType constants.JournalEntryType, loanID, referenceID int64, description string) *journalEntry {
+	return &journalEntry{
+		model: dtos.JournalEntryModel{
+			EntryType:   entryType,
+			LoanID:      loanID,
+			ReferenceID: referenceID,
+			Description: description,
+		},
+	}
+}
+
+func (e *journalEntry) debit(account constants.LedgerAccount, amount decimal.Decimal) *journalEntry {
+	return e.addLine(account, amount, decimal.Zero)
+}
+
+func (e *journalEntry) credit(account constants.LedgerAccount, amount decimal.Decimal) *journalEntry {
+	return e.addLine(account, decimal.Zero, amount)
+}
+
+// transfer debits debitAccount and credits creditAccount, a negative amount reverses the sides
+func (e *journalEntry) transfer(debitAccount, creditAccount constants.LedgerAccount, amount decimal.Decimal) *journalEntry {
+	if amount.IsNegative() {
+		debitAccount, creditAccount, amount = creditAccount, debitAccount, amount.Neg()
+	}
+	return e.debit(debitAccount, amount).credit(creditAccount, amount)
+}
+
+func (e *journalEntry) addLine(account constants.LedgerAccount, debitAmount, creditAmount decimal.Decimal) *journalEntry {
+	if debitAmount.IsZero() && creditAmount.IsZero() {
+		return e
+	}
+	e.lines = append(e.lines, dtos.JournalLineModel{
+		LoanID:       e.model.LoanID,
+		Account:      account,
+		DebitAmount:  debitAmount,
+		CreditAmount: creditAmount,
+	})
+	return e
+}
+
+func (e *journalEntry) validate() error {
+	debitAmount, creditAmount := decimal.Zero, decimal.Zero
+	for _, line := range e.lines {
+		if !line.Account.IsValid() || line.DebitAmount.IsNegative() || line.CreditAmount.IsNegative() {
+			return fmt.Errorf("%w. journal line of account %d", constants.ErrInvalidValue, line.Account)
+		}
+		debitAmount = debitAmount.Add(line.DebitAmount)
+		creditAmount = creditAmount.Add(line.CreditAmount)
+	}
+	if !debitAmount.Equal(creditAmount) {
+		return fmt.Errorf("%w. journal entry is not balanced, debit %s and credit %s", constants.ErrInvalidValue, debitAmount.String(), creditAmount.String())
+	}
+	return nil
+}
+
+// postJournalEntry persists the entry once it is balanced, an entry without any line is skipped
+func postJournalEntry(ctx context.Context, tx *sqlx.Tx, entry *journalEntry) error {
+	if len(entry.lines) == 0 {
+		return nil
+	}
+	if err := entry.validate(); err != nil {
+		return err
+	}
+
+	journalEntryID, err := clients.DBInsertJournalEntry(ctx, tx, &entry.model)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	for i := range entry.lines {
+		entry.lines[i].JournalEntryID = journalEntryID
+		entry.lines[i].CreatedAt = now
+	}
+	return clients.DBBatchInsertJournalLines(ctx, tx, entry.lines)
+}
+
+// scheduleBillings books the billed interest, fees and penalties (only capitalised by a restructure) as receivables,
+// the principal is booked on disbursement
+func (e *journalEntry) scheduleBillings(billingModels []dtos.BillingModel) *journalEntry {
+	interestAmount, feeAmount, penaltyAmount := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, billing := range billingModels {
+		interestAmount = interestAmount.Add(billing.InterestAmount)
+		feeAmount = feeAmount.Add(billing.FeeAmount)
+		penaltyAmount = penaltyAmount.Add(billing.PenaltyAmount)
+	}
+	return e.
+		transfer(constants.LedgerAccount_InterestReceivable, constants.LedgerAccount_UnearnedInterest, interestAmount).
+		transfer(constants.LedgerAccount_FeeReceivable, constants.LedgerAccount_FeeIncome, feeAmount).
+		transfer(constants.LedgerAccount_PenaltyReceivable, constants.LedgerAccount_PenaltyIncome, penaltyAmount)
+}
+
+// writeDownBillings takes the unpaid amount of the closed billings off the receivables.
+// the principal, fee and penalty are expensed to expenseAccount while the unpaid interest is reversed
+func (e *journalEntry) writeDownBillings(billingModels []dtos.BillingModel, expenseAccount constants.LedgerAccount) *journalEntry {
+	for _, billing := range billingModels {
+		e.transfer(expenseAccount, constants.LedgerAccount_LoanReceivable, billing.PrincipalAmount.Sub(billing.PrincipalPaidAmount)).
+			closeBillingInterest(billing).
+			transfer(expenseAccount, constants.LedgerAccount_FeeReceivable, billing.FeeAmount.Sub(billing.FeePaidAmount)).
+			transfer(expenseAccount, constants.LedgerAccount_PenaltyReceivable, billing.PenaltyAmount.Sub(billing.PenaltyPaidAmount))
+	}
+	return e
+}
+
+// cancelBillings reverses the unpaid interest, fee and penalty of the cancelled billings,
+// the unpaid principal stays receivable since it is rescheduled
+func (e *journalEntry) cancelBillings(billingModels []dtos.BillingModel) *journalEntry {
+	for _, billing := range billingModels {
+		e.closeBillingInterest(billing).
+			transfer(constants.LedgerAccount_FeeIncome, constants.LedgerAccount_FeeReceivable, billing.FeeAmount.Sub(billing.FeePaidAmount)).
+			transfer(constants.LedgerAccount_PenaltyIncome, constants.LedgerAccount_PenaltyReceivable, billing.PenaltyAmount.Sub(billing.PenaltyPaidAmount))
+	}
+	return e
+}
+
+// closeBillingInterest reverses the unpaid interest of a closed billing, only its paid interest stays recognised as income:
+// the accrued but unpaid interest is taken back while the interest paid in advance is recognised
+func (e *journalEntry) closeBillingInterest(billing dtos.BillingModel) *journalEntry {
+	return e.
+		transfer(constants.LedgerAccount_UnearnedInterest, constants.LedgerAccount_InterestReceivable, billing.InterestAmount.Sub(billing.InterestPaidAmount)).
+		transfer(constants.LedgerAccount_UnearnedInterest, constants.LedgerAccount_InterestIncome, billing.InterestPaidAmount.Sub(billing.InterestAccruedAmount))
+}
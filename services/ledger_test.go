@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// getJournalEntryBalances nets the debits and credits of the entry by account, a debit balance is positive
+func getJournalEntryBalances(entry *journalEntry) map[constants.LedgerAccount]decimal.Decimal {
+	balances := make(map[constants.LedgerAccount]decimal.Decimal)
+	for _, line := range entry.lines {
+		balances[line.Account] = balances[line.Account].Add(line.DebitAmount).Sub(line.CreditAmount)
+	}
+	return balances
+}
+
+func TestJournalEntryBillings(t *testing.T) {
+	billingModels := []dtos.BillingModel{
+		// partly paid, with accrued interest not paid yet
+		{
+			PrincipalAmount: decimal.NewFromInt(100_000), PrincipalPaidAmount: decimal.NewFromInt(40_000),
+			InterestAmount: decimal.NewFromInt(12_000), InterestPaidAmount: decimal.NewFromInt(5_000), InterestAccruedAmount: decimal.NewFromInt(9_000),
+			FeeAmount: decimal.NewFromInt(2_000), FeePaidAmount: decimal.Zero,
+			PenaltyAmount: decimal.NewFromInt(1_000), PenaltyPaidAmount: decimal.NewFromInt(500),
+		},
+		// interest paid in advance of its accrual
+		{
+			PrincipalAmount: decimal.NewFromInt(100_000), PrincipalPaidAmount: decimal.NewFromInt(100_000),
+			InterestAmount: decimal.NewFromInt(12_000), InterestPaidAmount: decimal.NewFromInt(12_000), InterestAccruedAmount: decimal.NewFromInt(3_000),
+			FeeAmount: decimal.Zero, FeePaidAmount: decimal.Zero,
+			PenaltyAmount: decimal.Zero, PenaltyPaidAmount: decimal.Zero,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		entry        *journalEntry
+		wantBalances map[constants.LedgerAccount]int64
+	}{
+		{
+			name:  "schedule billings",
+			entry: newJournalEntry(constants.JournalEntryType_Disbursement, 1, 1, "").scheduleBillings(billingModels),
+			wantBalances: map[constants.LedgerAccount]int64{
+				constants.LedgerAccount_InterestReceivable: 24_000,
+				constants.LedgerAccount_UnearnedInterest:   -24_000,
+				constants.LedgerAccount_FeeReceivable:      2_000,
+				constants.LedgerAccount_FeeIncome:          -2_000,
+				constants.LedgerAccount_PenaltyReceivable:  1_000,
+				constants.LedgerAccount_PenaltyIncome:      -1_000,
+			},
+		},
+		{
+			// the unpaid principal stays receivable, only the paid interest stays recognised
+			name:  "cancel billings",
+			entry: newJournalEntry(constants.JournalEntryType_Restructure, 1, 1, "").cancelBillings(billingModels),
+			wantBalances: map[constants.LedgerAccount]int64{
+				constants.LedgerAccount_InterestReceivable: -7_000,
+				constants.LedgerAccount_UnearnedInterest:   12_000,
+				constants.LedgerAccount_InterestIncome:     -5_000,
+				constants.LedgerAccount_FeeReceivable:      -2_000,
+				constants.LedgerAccount_FeeIncome:          2_000,
+				constants.LedgerAccount_PenaltyReceivable:  -500,
+				constants.LedgerAccount_PenaltyIncome:      500,
+			},
+		},
+		{
+			name:  "write down billings",
+			entry: newJournalEntry(constants.JournalEntryType_WriteOff, 1, 1, "").writeDownBillings(billingModels, constants.LedgerAccount_WriteOffExpense),
+			wantBalances: map[constants.LedgerAccount]int64{
+				constants.LedgerAccount_LoanReceivable:     -60_000,
+				constants.LedgerAccount_InterestReceivable: -7_000,
+				constants.LedgerAccount_UnearnedInterest:   12_000,
+				constants.LedgerAccount_InterestIncome:     -5_000,
+				constants.LedgerAccount_FeeReceivable:      -2_000,
+				constants.LedgerAccount_PenaltyReceivable:  -500,
+				constants.LedgerAccount_WriteOffExpense:    62_500,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.entry.validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			balances := getJournalEntryBalances(tt.entry)
+			for account := constants.LedgerAccount_Cash; account <= constants.LedgerAccount_Suspense; account++ {
+				if want := decimal.NewFromInt(tt.wantBalances[account]); !balances[account].Equal(want) {
+					t.Errorf("account %d: got balance %s, want %s", account, balances[account], want)
+				}
+			}
+		})
+	}
+}
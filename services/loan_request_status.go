@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+)
+
+// updateLoanRequestStatus locks the loan and moves it into toStatus, userID is only checked when it is set
+func updateLoanRequestStatus(ctx context.Context, loanID, userID int64, toStatus constants.LoanStatus, actor, reason string) error {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, loanID)
+	if err != nil {
+		return err
+	}
+	if userID != 0 && loanRequestModel.UserID != userID {
+		return constants.ErrRecordNotFound
+	}
+
+	if err = transitionLoanStatus(ctx, txn, loanRequestModel, toStatus, actor, reason); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
+
+// transitionLoanStatus moves the locked loan into toStatus when the state machine allows it,
+// and records the transition with its actor and reason
+func transitionLoanStatus(ctx context.Context, tx *sqlx.Tx, loanRequestModel *dtos.LoanRequestModel, toStatus constants.LoanStatus, actor, reason string) error {
+	if !loanRequestModel.Status.CanTransitionTo(toStatus) {
+		return fmt.Errorf("%w. loan request status can not be changed from %d to %d", constants.ErrInvalidValue, loanRequestModel.Status, toStatus)
+	}
+
+	if err := clients.DBUpdateLoanRequestStatusByID(ctx, tx, loanRequestModel.ID, toStatus); err != nil {
+		return err
+	}
+
+	if err := clients.DBBatchInsertLoanRequestHistories(ctx, tx, []dtos.LoanRequestHistory{
+		{
+			LoanID:              loanRequestModel.ID,
+			PrincipalPaidAmount: decimal.Zero,
+			InterestPaidAmount:  decimal.Zero,
+			Status:              toStatus,
+			Actor:               actor,
+			Reason:              reason,
+			CreatedAt:           time.Now().UnixMilli(),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := addOutboxEvent(ctx, tx, constants.EventType_LoanStatusChanged, loanRequestModel.ID, dtos.LoanStatusChangedEvent{
+		LoanID:     loanRequestModel.ID,
+		UserID:     loanRequestModel.UserID,
+		FromStatus: int8(loanRequestModel.Status),
+		ToStatus:   int8(toStatus),
+		Actor:      actor,
+		Reason:     reason,
+	}); err != nil {
+		return err
+	}
+
+	if toStatus == constants.LoanStatus_Completed {
+		if err := addOutboxEvent(ctx, tx, constants.EventType_LoanCompleted, loanRequestModel.ID, dtos.LoanCompletedEvent{
+			LoanID: loanRequestModel.ID,
+			UserID: loanRequestModel.UserID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	switch toStatus {
+	case constants.LoanStatus_Completed:
+		if err := closeLoanCollectionCase(ctx, tx, loanRequestModel.ID, constants.CollectionCaseCloseReason_Completed, actor); err != nil {
+			return err
+		}
+	case constants.LoanStatus_WrittenOff:
+		if err := closeLoanCollectionCase(ctx, tx, loanRequestModel.ID, constants.CollectionCaseCloseReason_WrittenOff, actor); err != nil {
+			return err
+		}
+	}
+
+	loanRequestModel.Status = toStatus
+	return nil
+}
+
+func validateActor(actor string) error {
+	if actor == "" {
+		return fmt.Errorf("%w. actor is required", constants.ErrInvalidValue)
+	}
+	return nil
+}
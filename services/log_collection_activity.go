@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+const (
+	maxCollectionActivityNotesLength = 1024 // follows the notes column's length
+)
+
+// LogCollectionActivity records a collector's call, visit, message or note on an open case
+func LogCollectionActivity(ctx context.Context, param dtos.LogCollectionActivityParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if !constants.CollectionActivityType(param.ActivityType).IsLoggable() {
+		return fmt.Errorf("%w. activity_type", constants.ErrInvalidValue)
+	}
+	if len(param.Notes) > maxCollectionActivityNotesLength {
+		return fmt.Errorf("%w. notes should be at most %d characters", constants.ErrInvalidValue, maxCollectionActivityNotesLength)
+	}
+	if param.ActivityTime < 0 || param.ActivityTime > time.Now().UnixMilli() {
+		return fmt.Errorf("%w. activity_time should not be in the future", constants.ErrInvalidValue)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	caseModel, err := getOpenCollectionCaseForUpdate(ctx, txn, param.CaseID)
+	if err != nil {
+		return err
+	}
+
+	if err = clients.DBBatchInsertCollectionActivities(ctx, txn, []dtos.CollectionActivityModel{
+		{
+			CaseID:       caseModel.ID,
+			LoanID:       caseModel.LoanID,
+			ActivityType: constants.CollectionActivityType(param.ActivityType),
+			Actor:        param.Actor,
+			Notes:        param.Notes,
+			ActivityTime: param.ActivityTime,
+		},
+	}); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -10,6 +10,7 @@ import (
 	"loan-payment/dtos"
 	"loan-payment/utils"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/shopspring/decimal"
 )
 
@@ -24,128 +25,317 @@ func MakePayment(ctx context.Context, param dtos.MakePaymentParam) error {
 	}
 	defer clients.DBRollbackTransaction(txn)
 
-	// prevent update racing with pessimistic lock
-	loanRequestModel, err := clients.DBGetLoanRequestByIDAndUserIDForUpdate(ctx, txn, param.LoanID, param.UserID)
-	if err != nil {
+	if _, err = makePayment(ctx, txn, param); err != nil {
 		return err
 	}
+	return clients.DBCommitTransaction(txn)
+}
 
-	nearestBillingSchedule, err := getNextNearestBillingSchedule(time.UnixMilli(loanRequestModel.DisbursementTime), loanRequestModel.TenureUnit)
+// makePayment posts the validated payment within the transaction and returns the payment id
+func makePayment(ctx context.Context, txn *sqlx.Tx, param dtos.MakePaymentParam) (int64, error) {
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDAndUserIDForUpdate(ctx, txn, param.LoanID, param.UserID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if !loanRequestModel.Status.IsDisbursed() {
+		return 0, fmt.Errorf("%w. loan request is not disbursed yet", constants.ErrInvalidValue)
+	}
+	if loanRequestModel.Status == constants.LoanStatus_WrittenOff {
+		return recoverWrittenOffLoan(ctx, txn, *loanRequestModel, param)
 	}
 
-	// prevent update racing with pessimistic lock
-	pendingBillings, err := clients.DBGetPendingBillingsWithDueTimeByLoanIdForUpdate(ctx, txn, param.LoanID, nearestBillingSchedule.UnixMilli())
+	openBillings, err := getPayableBillingsForUpdate(ctx, txn, param.LoanID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	var (
 		now = time.Now().UnixMilli()
 
-		includeLastBilling bool
-		billingIDs         []int64
-		outstandingAmount  decimal.Decimal
-		principalAmount    decimal.Decimal
-		interestAmount     decimal.Decimal
-		billingHistories   []dtos.BillingHistoryModel
+		outstandingAmount decimal.Decimal
+		totalAllocation   paymentAllocation
+		billingHistories  []dtos.BillingHistoryModel
+
+		paymentAmount, _ = decimal.NewFromString(param.Amount)
+	)
+	for _, billing := range openBillings {
+		outstandingAmount = outstandingAmount.Add(billing.GetUnpaidAmount())
+	}
+	if paymentAmount.GreaterThan(outstandingAmount) {
+		return 0, fmt.Errorf("%w. payment amount should not be greater than the outstanding amount %s", constants.ErrInvalidValue, outstandingAmount.String())
+	}
+
+	paymentID, err := clients.DBInsertPayment(ctx, txn, &dtos.PaymentModel{
+		UserID: param.UserID,
+		LoanID: loanRequestModel.ID,
+		Amount: paymentAmount,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// the interest of a non-accrual loan is recognised once it is paid
+	var (
+		isNonAccrual          = isNonAccrualLoan(openBillings, time.UnixMilli(now))
+		recognisedInterest    = decimal.Zero
+		interestAccrualModels []dtos.InterestAccrualModel
 	)
-	for _, billing := range pendingBillings {
-		outstandingAmount = outstandingAmount.Add(billing.TotalAmount)
-		principalAmount = principalAmount.Add(billing.PrincipalAmount)
-		interestAmount = interestAmount.Add(billing.InterestAmount)
 
-		billingIDs = append(billingIDs, billing.ID)
+	// billings are paid from the oldest due time, partially paid billings stay open
+	remainingAmount := paymentAmount
+	for i := range openBillings {
+		if !remainingAmount.IsPositive() {
+			break
+		}
+
+		billing := &openBillings[i]
+		var allocation paymentAllocation
+		allocation, remainingAmount = allocateBillingPayment(billing, remainingAmount)
+		totalAllocation = totalAllocation.add(allocation)
+
+		billing.PaymentID = paymentID
+		billing.Status = constants.PaymentStatus_PartiallyPaid
+		if !billing.GetUnpaidAmount().IsPositive() {
+			billing.Status = constants.PaymentStatus_Completed
+			billing.PaymentCompletedAt = now
+		}
+		if err = clients.DBUpdateBillingPaymentByID(ctx, txn, billing); err != nil {
+			return 0, err
+		}
+
+		if isNonAccrual && billing.InterestPaidAmount.GreaterThan(billing.InterestAccruedAmount) {
+			accruedAmount := billing.InterestPaidAmount.Sub(billing.InterestAccruedAmount)
+			billing.InterestAccruedAmount = billing.InterestPaidAmount
+			if err = clients.DBUpdateBillingInterestAccruedByID(ctx, txn, billing.ID, billing.InterestAccruedAmount); err != nil {
+				return 0, err
+			}
+
+			recognisedInterest = recognisedInterest.Add(accruedAmount)
+			interestAccrualModels = append(interestAccrualModels, dtos.InterestAccrualModel{
+				LoanID:      loanRequestModel.ID,
+				BillingID:   billing.BillingID,
+				AccrualDate: utils.GetStartOfDay(time.UnixMilli(now)).UnixMilli(),
+				Amount:      accruedAmount,
+				Source:      constants.InterestAccrualSource_Payment,
+				CreatedAt:   now,
+			})
+		}
+
 		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
 			BillingID:          billing.BillingID,
-			PaymentCompletedAt: now,
-			Status:             constants.PaymentStatus_Completed,
+			DueTime:            billing.DueTime,
+			PaymentCompletedAt: billing.PaymentCompletedAt,
+			Status:             billing.Status,
 			CreatedAt:          now,
 		})
+	}
 
-		if billing.RecurringIndex == loanRequestModel.TenureValue {
-			includeLastBilling = true
+	if err = clients.DBUpdateLoanRequestPaymentByID(ctx, txn, loanRequestModel.ID, totalAllocation.principalAmount, totalAllocation.interestAmount, totalAllocation.penaltyAmount, totalAllocation.feeAmount); err != nil {
+		return 0, err
+	}
+
+	if len(interestAccrualModels) > 0 {
+		if err = clients.DBBatchInsertInterestAccruals(ctx, txn, interestAccrualModels); err != nil {
+			return 0, err
 		}
 	}
 
+	// the interest is earned by the daily accrual, paying it in advance does not recognise it
+	journalEntry := newJournalEntry(constants.JournalEntryType_Payment, loanRequestModel.ID, paymentID, "loan payment").
+		debit(constants.LedgerAccount_Cash, paymentAmount).
+		credit(constants.LedgerAccount_LoanReceivable, totalAllocation.principalAmount).
+		credit(constants.LedgerAccount_InterestReceivable, totalAllocation.interestAmount).
+		credit(constants.LedgerAccount_PenaltyReceivable, totalAllocation.penaltyAmount).
+		credit(constants.LedgerAccount_FeeReceivable, totalAllocation.feeAmount).
+		transfer(constants.LedgerAccount_UnearnedInterest, constants.LedgerAccount_InterestIncome, recognisedInterest)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
+		{
+			LoanID:              loanRequestModel.ID,
+			PrincipalPaidAmount: totalAllocation.principalAmount,
+			InterestPaidAmount:  totalAllocation.interestAmount,
+			Status:              loanRequestModel.Status,
+			Actor:               constants.LoanActor_Borrower,
+			CreatedAt:           now,
+		},
+	}); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+		return 0, err
+	}
+
+	if err = addOutboxEvent(ctx, txn, constants.EventType_PaymentReceived, loanRequestModel.ID, dtos.PaymentReceivedEvent{
+		PaymentID:       paymentID,
+		LoanID:          loanRequestModel.ID,
+		UserID:          loanRequestModel.UserID,
+		Amount:          paymentAmount,
+		PrincipalAmount: totalAllocation.principalAmount,
+		InterestAmount:  totalAllocation.interestAmount,
+		PenaltyAmount:   totalAllocation.penaltyAmount,
+		FeeAmount:       totalAllocation.feeAmount,
+	}); err != nil {
+		return 0, err
+	}
+
+	remainingBillings, err := clients.DBCountOpenBillingsByLoanID(ctx, txn, loanRequestModel.ID)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case remainingBillings == 0 && loanRequestModel.Status != constants.LoanStatus_Completed:
+		err = completeLoanRequest(ctx, txn, loanRequestModel, constants.LoanActor_System, "all billings are paid")
+	case loanRequestModel.Status == constants.LoanStatus_Defaulted && !hasOverdueBilling(openBillings, now):
+		err = transitionLoanStatus(ctx, txn, loanRequestModel, constants.LoanStatus_InRepayment, constants.LoanActor_System, "overdue billings are paid")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return paymentID, nil
+}
+
+// recoverWrittenOffLoan records the payment as recovery, the written off billings stay closed
+func recoverWrittenOffLoan(ctx context.Context, tx *sqlx.Tx, loanRequestModel dtos.LoanRequestModel, param dtos.MakePaymentParam) (int64, error) {
+	unrecoveredAmount, err := getUnrecoveredAmount(ctx, tx, loanRequestModel.ID)
+	if err != nil {
+		return 0, err
+	}
+
 	paymentAmount, _ := decimal.NewFromString(param.Amount)
-	if !paymentAmount.Equal(outstandingAmount) {
-		return fmt.Errorf("%w. incorrect payment amount", constants.ErrInvalidValue)
+	if paymentAmount.GreaterThan(unrecoveredAmount) {
+		return 0, fmt.Errorf("%w. payment amount should not be greater than the unrecovered amount %s", constants.ErrInvalidValue, unrecoveredAmount.String())
 	}
 
-	paymentID, err := clients.DBInsertPayment(ctx, txn, &dtos.PaymentModel{
+	paymentID, err := clients.DBInsertPayment(ctx, tx, &dtos.PaymentModel{
 		UserID: param.UserID,
+		LoanID: loanRequestModel.ID,
 		Amount: paymentAmount,
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if err = clients.DBBulkPayBillingsByIDs(ctx, txn, billingIDs, paymentID); err != nil {
-		return err
+	if err = clients.DBInsertLoanRecovery(ctx, tx, &dtos.LoanRecoveryModel{
+		LoanID:    loanRequestModel.ID,
+		PaymentID: paymentID,
+		Amount:    paymentAmount,
+	}); err != nil {
+		return 0, err
 	}
 
-	loanRequestStatus := loanRequestModel.Status
-	if includeLastBilling {
-		loanRequestStatus = constants.LoanStatus_Completed
-	}
-	if err = clients.DBUpdateLoanRequestPaymentByID(ctx, txn, loanRequestModel.ID, principalAmount, interestAmount, loanRequestStatus); err != nil {
-		return err
+	journalEntry := newJournalEntry(constants.JournalEntryType_Recovery, loanRequestModel.ID, paymentID, "written off loan recovery").
+		transfer(constants.LedgerAccount_Cash, constants.LedgerAccount_RecoveryIncome, paymentAmount)
+	if err = postJournalEntry(ctx, tx, journalEntry); err != nil {
+		return 0, err
 	}
 
-	if err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
-		{
-			LoanID:              loanRequestModel.ID,
-			PrincipalPaidAmount: principalAmount,
-			InterestPaidAmount:  interestAmount,
-			Status:              loanRequestStatus,
-			CreatedAt:           now,
-		},
+	if err = addOutboxEvent(ctx, tx, constants.EventType_PaymentReceived, loanRequestModel.ID, dtos.PaymentReceivedEvent{
+		PaymentID:       paymentID,
+		LoanID:          loanRequestModel.ID,
+		UserID:          loanRequestModel.UserID,
+		Amount:          paymentAmount,
+		PrincipalAmount: decimal.Zero,
+		InterestAmount:  decimal.Zero,
+		PenaltyAmount:   decimal.Zero,
+		FeeAmount:       decimal.Zero,
+		IsRecovery:      true,
 	}); err != nil {
-		return err
+		return 0, err
 	}
+	return paymentID, nil
+}
 
-	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
-		return err
+// getPayableBillingsForUpdate returns the open billings a payment is allocated to,
+// overdue billings are paid together with the nearest upcoming one
+func getPayableBillingsForUpdate(ctx context.Context, tx *sqlx.Tx, loanID int64) ([]dtos.BillingModel, error) {
+	nearestBillingDueTime, err := clients.DBGetNearestOpenBillingDueTime(ctx, tx, loanID, time.Now().UnixMilli())
+	if err == constants.ErrRecordNotFound {
+		nearestBillingDueTime = time.Now().UnixMilli()
+	} else if err != nil {
+		return nil, err
 	}
-	return clients.DBCommitTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	return clients.DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx, tx, loanID, nearestBillingDueTime)
+}
+
+// getUnrecoveredAmount returns the written off amount that is not recovered yet
+func getUnrecoveredAmount(ctx context.Context, tx *sqlx.Tx, loanID int64) (decimal.Decimal, error) {
+	writeOffModel, err := clients.DBGetLoanWriteOffByLoanID(ctx, tx, loanID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	recoveredAmount, err := clients.DBGetLoanRecoveredAmount(ctx, tx, loanID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return writeOffModel.GetTotalAmount().Sub(recoveredAmount), nil
 }
 
 func validatePayment(param dtos.MakePaymentParam) error {
 	paymentAmount, err := decimal.NewFromString(param.Amount)
 	if err != nil {
 		return fmt.Errorf("%w. unable to parse payment's amount", constants.ErrInvalidValue)
-	} else if paymentAmount.LessThan(decimal.Zero) {
+	} else if !paymentAmount.IsPositive() {
 		return fmt.Errorf("%w. payment's amount should be greater than 0", constants.ErrInvalidValue)
 	}
 	return nil
 }
 
-func getNextNearestBillingSchedule(disbursementTime time.Time, tenureUnit constants.TenureUnit) (*time.Time, error) {
-	var (
-		now                 = time.Now()
-		nextNearestSchedule = utils.GetNextTenureSchedule(
-			time.Date(
-				disbursementTime.Year(),
-				disbursementTime.Month(),
-				disbursementTime.Day(),
-				23,
-				59,
-				59,
-				0,
-				disbursementTime.Location(),
-			),
-			tenureUnit,
-		)
-	)
+func hasOverdueBilling(billingModels []dtos.BillingModel, now int64) bool {
+	for _, billing := range billingModels {
+		if billing.Status.IsOpen() && billing.DueTime < now {
+			return true
+		}
+	}
+	return false
+}
 
-	if nextNearestSchedule == nil {
-		return nil, fmt.Errorf("unable to get next repayment schedule")
+type paymentAllocation struct {
+	principalAmount decimal.Decimal
+	interestAmount  decimal.Decimal
+	penaltyAmount   decimal.Decimal
+	feeAmount       decimal.Decimal
+}
+
+func (a paymentAllocation) add(other paymentAllocation) paymentAllocation {
+	return paymentAllocation{
+		principalAmount: a.principalAmount.Add(other.principalAmount),
+		interestAmount:  a.interestAmount.Add(other.interestAmount),
+		penaltyAmount:   a.penaltyAmount.Add(other.penaltyAmount),
+		feeAmount:       a.feeAmount.Add(other.feeAmount),
 	}
+}
 
-	for nextNearestSchedule.Before(now) {
-		nextNearestSchedule = utils.GetNextTenureSchedule(*nextNearestSchedule, tenureUnit)
+// allocateBillingPayment pays the billing's unpaid components in a deterministic order:
+// penalty, fee, interest then principal. It returns the allocation and the remaining amount
+func allocateBillingPayment(billing *dtos.BillingModel, amount decimal.Decimal) (paymentAllocation, decimal.Decimal) {
+	var allocation paymentAllocation
+
+	components := []struct {
+		billedAmount    decimal.Decimal
+		paidAmount      *decimal.Decimal
+		allocatedAmount *decimal.Decimal
+	}{
+		{billing.PenaltyAmount, &billing.PenaltyPaidAmount, &allocation.penaltyAmount},
+		{billing.FeeAmount, &billing.FeePaidAmount, &allocation.feeAmount},
+		{billing.InterestAmount, &billing.InterestPaidAmount, &allocation.interestAmount},
+		{billing.PrincipalAmount, &billing.PrincipalPaidAmount, &allocation.principalAmount},
+	}
+	for _, component := range components {
+		paidAmount := decimal.Min(amount, component.billedAmount.Sub(*component.paidAmount))
+		if !paidAmount.IsPositive() {
+			continue
+		}
+		*component.paidAmount = component.paidAmount.Add(paidAmount)
+		*component.allocatedAmount = paidAmount
+		amount = amount.Sub(paidAmount)
 	}
-	return nextNearestSchedule, nil
+	return allocation, amount
 }
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"loan-payment/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template // also the sms text, so kept short
+}
+
+type notificationTemplateData struct {
+	Name           string
+	LoanID         int64
+	RecurringIndex int
+	DueDate        string
+	Amount         string
+}
+
+var notificationTemplates = map[constants.Locale]map[constants.NotificationType]notificationTemplate{
+	constants.Locale_ID: {
+		constants.NotificationType_ReminderH3: newNotificationTemplate(
+			"Tagihan Anda jatuh tempo dalam 3 hari",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} jatuh tempo pada {{.DueDate}}. Mohon siapkan pembayaran Anda."),
+		constants.NotificationType_ReminderH1: newNotificationTemplate(
+			"Tagihan Anda jatuh tempo besok",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} jatuh tempo besok, {{.DueDate}}. Bayar tepat waktu untuk menghindari denda."),
+		constants.NotificationType_ReminderDue: newNotificationTemplate(
+			"Tagihan Anda jatuh tempo hari ini",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} jatuh tempo hari ini, {{.DueDate}}. Segera lakukan pembayaran."),
+		constants.NotificationType_OverdueD1: newNotificationTemplate(
+			"Tagihan Anda telah melewati jatuh tempo",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} telah melewati jatuh tempo {{.DueDate}}. Segera bayar untuk menghindari denda keterlambatan."),
+		constants.NotificationType_OverdueD7: newNotificationTemplate(
+			"Tagihan Anda terlambat 7 hari",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} belum dibayar 7 hari sejak jatuh tempo {{.DueDate}}. Denda keterlambatan terus bertambah, segera lakukan pembayaran."),
+		constants.NotificationType_OverdueD30: newNotificationTemplate(
+			"Peringatan terakhir: tagihan terlambat 30 hari",
+			"Halo {{.Name}}, cicilan ke-{{.RecurringIndex}} pinjaman #{{.LoanID}} sebesar {{.Amount}} telah terlambat 30 hari sejak {{.DueDate}}. Jika tidak segera dibayar, pinjaman Anda dapat dinyatakan gagal bayar dan diteruskan ke proses penagihan."),
+	},
+	constants.Locale_EN: {
+		constants.NotificationType_ReminderH3: newNotificationTemplate(
+			"Your bill is due in 3 days",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} is due on {{.DueDate}}. Please get your payment ready."),
+		constants.NotificationType_ReminderH1: newNotificationTemplate(
+			"Your bill is due tomorrow",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} is due tomorrow, {{.DueDate}}. Pay on time to avoid late fees."),
+		constants.NotificationType_ReminderDue: newNotificationTemplate(
+			"Your bill is due today",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} is due today, {{.DueDate}}. Please make your payment."),
+		constants.NotificationType_OverdueD1: newNotificationTemplate(
+			"Your bill is overdue",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} was due on {{.DueDate}}. Please pay now to avoid late fees."),
+		constants.NotificationType_OverdueD7: newNotificationTemplate(
+			"Your bill is 7 days overdue",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} is unpaid 7 days after its due date, {{.DueDate}}. Late fees keep adding up, please pay now."),
+		constants.NotificationType_OverdueD30: newNotificationTemplate(
+			"Final notice: your bill is 30 days overdue",
+			"Hi {{.Name}}, installment {{.RecurringIndex}} of loan #{{.LoanID}} of {{.Amount}} is 30 days overdue since {{.DueDate}}. If it stays unpaid, your loan may be declared in default and passed on to collections."),
+	},
+}
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+func newNotificationTemplate(subject, body string) notificationTemplate {
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+func renderNotification(locale constants.Locale, notificationType constants.NotificationType, data notificationTemplateData) (string, string, error) {
+	tmpl, ok := notificationTemplates[locale][notificationType]
+	if !ok {
+		return "", "", fmt.Errorf("no %s template of %s notification", locale, notificationType)
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
+
+func formatNotificationDate(locale constants.Locale, date time.Time) string {
+	if locale == constants.Locale_ID {
+		return fmt.Sprintf("%d %s %d", date.Day(), indonesianMonths[date.Month()-1], date.Year())
+	}
+	return date.Format("2 January 2006")
+}
+
+// formatNotificationAmount groups the thousands by the locale, e.g. Rp1.500.000,50 in id and IDR 1,500,000.50 in en
+func formatNotificationAmount(locale constants.Locale, amount decimal.Decimal) string {
+	var (
+		prefix             = "IDR "
+		thousandsSeparator = ","
+		decimalSeparator   = "."
+	)
+	if locale == constants.Locale_ID {
+		prefix, thousandsSeparator, decimalSeparator = "Rp", ".", ","
+	}
+
+	amount = amount.Round(constants.AmountDecimalPlaces)
+	integer := amount.Truncate(0)
+	digits := integer.Abs().String()
+
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteString(thousandsSeparator)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	formatted := prefix + grouped.String()
+	if fraction := amount.Sub(integer).Abs(); !fraction.IsZero() {
+		formatted += decimalSeparator + fraction.StringFixed(constants.AmountDecimalPlaces)[2:]
+	}
+	if amount.IsNegative() {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
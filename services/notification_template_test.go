@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRenderNotification(t *testing.T) {
+	data := notificationTemplateData{
+		Name:           "Budi",
+		LoanID:         42,
+		RecurringIndex: 3,
+		DueDate:        "5 Februari 2026",
+		Amount:         "Rp221.500",
+	}
+
+	for _, locale := range []constants.Locale{constants.Locale_ID, constants.Locale_EN} {
+		for _, notificationType := range constants.NotificationTypes {
+			subject, body, err := renderNotification(locale, notificationType, data)
+			if err != nil {
+				t.Fatalf("%s %s: %v", locale, notificationType, err)
+			}
+			if subject == "" {
+				t.Errorf("%s %s: empty subject", locale, notificationType)
+			}
+			for _, value := range []string{data.Name, "#42", data.DueDate, data.Amount} {
+				if !strings.Contains(body, value) {
+					t.Errorf("%s %s: body %q does not contain %q", locale, notificationType, body, value)
+				}
+			}
+			if strings.Contains(subject+body, "<no value>") || strings.Contains(subject+body, "{{") {
+				t.Errorf("%s %s: unrendered field in %q %q", locale, notificationType, subject, body)
+			}
+		}
+	}
+
+	_, body, err := renderNotification(constants.Locale_EN, constants.NotificationType_ReminderH1, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Hi Budi, installment 3 of loan #42 of Rp221.500 is due tomorrow, 5 Februari 2026. Pay on time to avoid late fees."
+	if body != want {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+
+	if _, _, err = renderNotification(constants.Locale("fr"), constants.NotificationType_ReminderH1, data); err == nil {
+		t.Error("a locale without templates should fail")
+	}
+}
+
+func TestFormatNotificationAmount(t *testing.T) {
+	tests := []struct {
+		locale constants.Locale
+		amount string
+		want   string
+	}{
+		{locale: constants.Locale_ID, amount: "1500000.5", want: "Rp1.500.000,50"},
+		{locale: constants.Locale_EN, amount: "1500000.5", want: "IDR 1,500,000.50"},
+		{locale: constants.Locale_ID, amount: "221500", want: "Rp221.500"},
+		{locale: constants.Locale_EN, amount: "999", want: "IDR 999"},
+		{locale: constants.Locale_EN, amount: "1000.005", want: "IDR 1,000.01"},
+		{locale: constants.Locale_ID, amount: "-2500", want: "-Rp2.500"},
+	}
+
+	for _, tt := range tests {
+		if got := formatNotificationAmount(tt.locale, decimal.RequireFromString(tt.amount)); got != tt.want {
+			t.Errorf("%s %s: got %s, want %s", tt.locale, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFormatNotificationDate(t *testing.T) {
+	date := time.Date(2026, 8, 17, 23, 59, 59, 0, time.UTC)
+	if got := formatNotificationDate(constants.Locale_ID, date); got != "17 Agustus 2026" {
+		t.Errorf("got %s, want 17 Agustus 2026", got)
+	}
+	if got := formatNotificationDate(constants.Locale_EN, date); got != "17 August 2026" {
+		t.Errorf("got %s, want 17 August 2026", got)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newOutboxEvent(eventType constants.EventType, loanID int64, payload interface{}) (dtos.OutboxEventModel, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return dtos.OutboxEventModel{}, err
+	}
+
+	return dtos.OutboxEventModel{
+		EventID:   uuid.NewString(),
+		EventType: eventType,
+		LoanID:    loanID,
+		Payload:   string(content),
+		CreatedAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+// addOutboxEvent records the event within the business change's transaction, the relay publishes it once committed
+func addOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventType constants.EventType, loanID int64, payload interface{}) error {
+	outboxEvent, err := newOutboxEvent(eventType, loanID, payload)
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvents(ctx, tx, []dtos.OutboxEventModel{outboxEvent})
+}
+
+// insertOutboxEvents records the events and enqueues their webhook deliveries in the same transaction,
+// so the partners' webhooks do not wait for the broker
+func insertOutboxEvents(ctx context.Context, tx *sqlx.Tx, outboxEvents []dtos.OutboxEventModel) error {
+	if err := clients.DBBatchInsertOutboxEvents(ctx, tx, outboxEvents); err != nil {
+		return err
+	}
+
+	webhookSubscriptions := make(map[int64][]dtos.WebhookSubscriptionModel)
+	for _, outboxEvent := range outboxEvents {
+		subscriptions, ok := webhookSubscriptions[outboxEvent.LoanID]
+		if !ok {
+			var err error
+			if subscriptions, err = clients.DBGetActiveWebhookSubscriptionsByLoanID(ctx, tx, outboxEvent.LoanID); err != nil {
+				return err
+			}
+			webhookSubscriptions[outboxEvent.LoanID] = subscriptions
+		}
+
+		if err := enqueueWebhookDeliveries(ctx, tx, subscriptions, newEvent(outboxEvent)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newEvent(outboxEvent dtos.OutboxEventModel) dtos.Event {
+	return dtos.Event{
+		EventID:    outboxEvent.EventID,
+		EventType:  string(outboxEvent.EventType),
+		LoanID:     outboxEvent.LoanID,
+		Payload:    json.RawMessage(outboxEvent.Payload),
+		OccurredAt: outboxEvent.CreatedAt,
+	}
+}
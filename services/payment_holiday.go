@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxPaymentHolidayCodeLength = 64
+)
+
+// errPaymentHolidayApplied skips a loan that already has the holiday code
+var errPaymentHolidayApplied = errors.New("payment holiday is already applied")
+
+type paymentHolidayFunc func(ctx context.Context, loanRequestModel dtos.LoanRequestModel) (*dtos.PaymentHolidayLoan, error)
+
+// runPaymentHoliday runs fn for every loan targeted by the payment holiday and reports the result per loan,
+// a failing loan does not stop the other loans. When listing the loans fails, the loans processed so far
+// are returned along with the error
+func runPaymentHoliday(ctx context.Context, param dtos.PaymentHolidayParam, fn paymentHolidayFunc) (*dtos.PaymentHolidayResponse, error) {
+	if err := validatePaymentHoliday(param); err != nil {
+		return nil, err
+	}
+
+	response := dtos.PaymentHolidayResponse{
+		TotalHolidayInterestAmount: decimal.Zero,
+		TotalReversedPenaltyAmount: decimal.Zero,
+		Loans:                      make([]dtos.PaymentHolidayLoan, 0),
+		SkippedLoanIDs:             make([]int64, 0),
+		FailedLoanIDs:              make([]int64, 0),
+	}
+	runLoan := func(loanRequestModel dtos.LoanRequestModel) {
+		loan, err := fn(ctx, loanRequestModel)
+		if errors.Is(err, errPaymentHolidayApplied) {
+			response.SkippedLoanIDs = append(response.SkippedLoanIDs, loanRequestModel.ID)
+			return
+		} else if err != nil {
+			logrus.Errorf("failed to run payment holiday of loan %d. %+v", loanRequestModel.ID, err)
+			response.FailedLoanIDs = append(response.FailedLoanIDs, loanRequestModel.ID)
+			return
+		}
+		response.TotalHolidayInterestAmount = response.TotalHolidayInterestAmount.Add(loan.HolidayInterestAmount)
+		response.TotalReversedPenaltyAmount = response.TotalReversedPenaltyAmount.Add(loan.ReversedPenaltyAmount)
+		response.Loans = append(response.Loans, *loan)
+	}
+
+	if len(param.LoanIDs) > 0 {
+		for _, loanID := range param.LoanIDs {
+			loanRequestModel, err := clients.DBGetLoanRequestByID(ctx, loanID)
+			if err != nil {
+				logrus.Errorf("failed to get loan %d of payment holiday. %+v", loanID, err)
+				response.FailedLoanIDs = append(response.FailedLoanIDs, loanID)
+				continue
+			}
+			runLoan(*loanRequestModel)
+		}
+		return &response, nil
+	}
+
+	var productID int64
+	if param.ProductCode != "" {
+		product, err := getLoanRequestProduct(ctx, param.ProductCode)
+		if err != nil {
+			return nil, err
+		}
+		productID = product.ID
+	}
+
+	var lastLoanID int64
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return &response, err
+		}
+		if len(loanRequestModels) == 0 {
+			return &response, nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			if productID != 0 && loanRequestModel.ProductID != productID {
+				continue
+			}
+			runLoan(loanRequestModel)
+		}
+	}
+}
+
+// calculatePaymentHoliday shifts the open billings by the holiday periods of the loan's tenure unit.
+// the interest accrued during the holiday is spread over the shifted billings, and the unpaid late penalty
+// of a billing that is no longer overdue after the shift is reversed
+func calculatePaymentHoliday(loanRequestModel dtos.LoanRequestModel, openBillings []dtos.BillingModel, param dtos.PaymentHolidayParam, now int64) (*dtos.PaymentHolidayLoan, []dtos.BillingModel, error) {
+	if loanRequestModel.Status != constants.LoanStatus_InRepayment && loanRequestModel.Status != constants.LoanStatus_Defaulted {
+		return nil, nil, fmt.Errorf("%w. only loan in repayment or defaulted can have payment holiday", constants.ErrInvalidValue)
+	}
+	if len(openBillings) == 0 {
+		return nil, nil, fmt.Errorf("%w. loan has no open billing", constants.ErrInvalidValue)
+	}
+
+	holidayInterestAmount := decimal.Zero
+	if param.AccrueInterest {
+		outstandingPrincipalAmount := decimal.Zero
+		for _, billing := range openBillings {
+			outstandingPrincipalAmount = outstandingPrincipalAmount.Add(billing.PrincipalAmount.Sub(billing.PrincipalPaidAmount))
+		}
+		holidayInterestAmount = outstandingPrincipalAmount.
+			Mul(getPeriodInterestRate(loanRequestModel)).
+			Mul(decimal.NewFromInt(int64(param.Periods))).
+			Round(constants.AmountDecimalPlaces)
+	}
+
+	var (
+		loan = dtos.PaymentHolidayLoan{
+			LoanID:                loanRequestModel.ID,
+			UserID:                loanRequestModel.UserID,
+			HolidayInterestAmount: holidayInterestAmount,
+			ReversedPenaltyAmount: decimal.Zero,
+			Billings:              make([]dtos.PaymentHolidayBilling, 0, len(openBillings)),
+		}
+		shiftedBillings = make([]dtos.BillingModel, 0, len(openBillings))
+
+		interestAmountPerBilling = holidayInterestAmount.Div(decimal.NewFromInt(int64(len(openBillings)))).Round(constants.AmountDecimalPlaces)
+		remainingInterestAmount  = holidayInterestAmount
+	)
+	for i, billing := range openBillings {
+		nextDueTime := utils.AddTenurePeriods(time.UnixMilli(billing.DueTime), getBillingAnchorDay(billing, loanRequestModel), loanRequestModel.TenureUnit, param.Periods)
+		if nextDueTime == nil {
+			return nil, nil, fmt.Errorf("unable to get next tenure schedule from %v", time.UnixMilli(billing.DueTime))
+		}
+		dueTime := *nextDueTime
+
+		// the last billing absorbs the rounding remainder
+		interestAmount := interestAmountPerBilling
+		if i == len(openBillings)-1 {
+			interestAmount = remainingInterestAmount
+		}
+		remainingInterestAmount = remainingInterestAmount.Sub(interestAmount)
+
+		// a paid or capitalised penalty is never taken back
+		reversedPenaltyAmount := decimal.Zero
+		if billing.DueTime < now && dueTime.UnixMilli() >= now {
+			reversedPenaltyAmount = decimal.Max(decimal.Zero,
+				billing.PenaltyAmount.Sub(decimal.Max(billing.PenaltyPaidAmount, billing.CapitalisedPenaltyAmount)))
+		}
+		loan.ReversedPenaltyAmount = loan.ReversedPenaltyAmount.Add(reversedPenaltyAmount)
+
+		loan.Billings = append(loan.Billings, dtos.PaymentHolidayBilling{
+			BillingID:         billing.BillingID,
+			RecurringIndex:    billing.RecurringIndex,
+			DueTime:           billing.DueTime,
+			NewDueTime:        dueTime.UnixMilli(),
+			InterestAmount:    billing.InterestAmount,
+			NewInterestAmount: billing.InterestAmount.Add(interestAmount),
+			PenaltyAmount:     billing.PenaltyAmount,
+			NewPenaltyAmount:  billing.PenaltyAmount.Sub(reversedPenaltyAmount),
+		})
+
+		billing.DueTime = dueTime.UnixMilli()
+		billing.InterestAmount = billing.InterestAmount.Add(interestAmount)
+		billing.PenaltyAmount = billing.PenaltyAmount.Sub(reversedPenaltyAmount)
+		billing.TotalAmount = billing.TotalAmount.Add(interestAmount).Sub(reversedPenaltyAmount)
+		shiftedBillings = append(shiftedBillings, billing)
+	}
+	return &loan, shiftedBillings, nil
+}
+
+func validatePaymentHoliday(param dtos.PaymentHolidayParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.HolidayCode == "" || len(param.HolidayCode) > maxPaymentHolidayCodeLength {
+		return fmt.Errorf("%w. holiday_code is required and at most %d characters", constants.ErrInvalidValue, maxPaymentHolidayCodeLength)
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+	if param.Periods < 1 {
+		return fmt.Errorf("%w. periods should be greater than 0", constants.ErrInvalidValue)
+	}
+	return nil
+}
+
+// getBillingAnchorDay returns the day the billing's schedule is anchored on. a billing on the last day of a month shorter than
+// the disbursement day is clamped, so it moves back to the disbursement day in a longer month
+func getBillingAnchorDay(billing dtos.BillingModel, loanRequestModel dtos.LoanRequestModel) int {
+	dueTime := time.UnixMilli(billing.DueTime)
+	disbursementDay := time.UnixMilli(loanRequestModel.DisbursementTime).Day()
+	if isLastDayOfMonth := dueTime.AddDate(0, 0, 1).Day() == 1; isLastDayOfMonth && disbursementDay > dueTime.Day() {
+		return disbursementDay
+	}
+	return dueTime.Day()
+}
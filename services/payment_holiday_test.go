@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"loan-payment/dtos"
+)
+
+func TestGetBillingAnchorDay(t *testing.T) {
+	tests := []struct {
+		name             string
+		disbursementTime time.Time
+		dueTime          time.Time
+		want             int
+	}{
+		{name: "clamped in february", disbursementTime: time.Date(2026, 1, 31, 10, 0, 0, 0, time.Local), dueTime: time.Date(2026, 2, 28, 23, 59, 0, 0, time.Local), want: 31},
+		{name: "clamped in a 30 day month", disbursementTime: time.Date(2026, 1, 31, 10, 0, 0, 0, time.Local), dueTime: time.Date(2026, 4, 30, 23, 59, 0, 0, time.Local), want: 31},
+		{name: "on the disbursement day", disbursementTime: time.Date(2026, 1, 31, 10, 0, 0, 0, time.Local), dueTime: time.Date(2026, 3, 31, 23, 59, 0, 0, time.Local), want: 31},
+		{name: "month end after a mid-month disbursement", disbursementTime: time.Date(2026, 1, 15, 10, 0, 0, 0, time.Local), dueTime: time.Date(2026, 4, 30, 23, 59, 0, 0, time.Local), want: 30},
+		{name: "custom due day", disbursementTime: time.Date(2026, 1, 31, 10, 0, 0, 0, time.Local), dueTime: time.Date(2026, 2, 10, 23, 59, 0, 0, time.Local), want: 10},
+	}
+
+	for _, tt := range tests {
+		billing := dtos.BillingModel{DueTime: tt.dueTime.UnixMilli()}
+		loanRequestModel := dtos.LoanRequestModel{DisbursementTime: tt.disbursementTime.UnixMilli()}
+		if got := getBillingAnchorDay(billing, loanRequestModel); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// PreviewPaymentHoliday calculates the impact of ApplyPaymentHoliday without persisting anything
+func PreviewPaymentHoliday(ctx context.Context, param dtos.PaymentHolidayParam) (*dtos.PaymentHolidayResponse, error) {
+	return runPaymentHoliday(ctx, param, func(ctx context.Context, loanRequestModel dtos.LoanRequestModel) (*dtos.PaymentHolidayLoan, error) {
+		if _, err := clients.DBGetLoanPaymentHoliday(ctx, nil, loanRequestModel.ID, param.HolidayCode); err == nil {
+			return nil, errPaymentHolidayApplied
+		} else if err != constants.ErrRecordNotFound {
+			return nil, err
+		}
+
+		billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var openBillings []dtos.BillingModel
+		for _, billing := range billingModels {
+			if billing.Status.IsOpen() {
+				openBillings = append(openBillings, billing)
+			}
+		}
+
+		loan, _, err := calculatePaymentHoliday(loanRequestModel, openBillings, param, time.Now().UnixMilli())
+		return loan, err
+	})
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// getDailyEconomicBenefitRate returns the interest and fees charged per day (inflated by 10^2) of the loan amount
+func getDailyEconomicBenefitRate(loanModel dtos.LoanRequestModel, billingModels []dtos.BillingModel, feeAmount decimal.Decimal) decimal.Decimal {
+	var (
+		interestAmount decimal.Decimal
+		lastDueTime    = loanModel.DisbursementTime
+	)
+
+	for _, billing := range billingModels {
+		interestAmount = interestAmount.Add(billing.InterestAmount)
+		if billing.DueTime > lastDueTime {
+			lastDueTime = billing.DueTime
+		}
+	}
+
+	days := math.Max(1, math.Ceil(time.UnixMilli(lastDueTime).Sub(time.UnixMilli(loanModel.DisbursementTime)).Hours()/24))
+	return interestAmount.Add(feeAmount).
+		Div(loanModel.LoanAmount).
+		Div(decimal.NewFromFloat(days)).
+		Mul(constants.Percent)
+}
+
+func validateDailyEconomicBenefitCap(loanModel dtos.LoanRequestModel, billingModels []dtos.BillingModel, feeAmount decimal.Decimal) error {
+	maxRate := configs.Get().RegulatoryCap.MaxDailyEconomicBenefitRate
+	if !maxRate.IsPositive() {
+		return nil
+	}
+
+	if rate := getDailyEconomicBenefitRate(loanModel, billingModels, feeAmount); rate.GreaterThan(maxRate) {
+		return fmt.Errorf("%w. daily interest and fees of %s%% exceed the cap of %s%% of the loan amount",
+			constants.ErrRegulatoryCapExceeded, rate.StringFixed(4), maxRate.String())
+	}
+	return nil
+}
+
+// getTotalPenaltyCapAmount returns false when penalties are not capped
+func getTotalPenaltyCapAmount(loanModel dtos.LoanRequestModel) (decimal.Decimal, bool) {
+	maxRate := configs.Get().RegulatoryCap.MaxTotalPenaltyRate
+	if !maxRate.IsPositive() {
+		return decimal.Zero, false
+	}
+	return loanModel.LoanAmount.Mul(maxRate).Div(constants.Percent).Round(constants.AmountDecimalPlaces), true
+}
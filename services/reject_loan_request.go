@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+func RejectLoanRequest(ctx context.Context, param dtos.RejectLoanRequestParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+	return updateLoanRequestStatus(ctx, param.LoanID, 0, constants.LoanStatus_Rejected, param.Actor, param.Reason)
+}
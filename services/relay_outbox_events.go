@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	outboxEventBatchSize = 100
+	// a run publishing past its claim may get its events published again by another run, they are at least once anyway
+	outboxEventClaimDuration = 5 * time.Minute
+)
+
+// RelayOutboxEvents publishes the pending outbox events to the broker in insertion order, at least once.
+// Once an event of a loan fails, or is claimed by another run, the loan's later events wait for the next run to keep their order.
+// An event failing the max attempts is dead lettered so it no longer holds its loan's events back.
+// The webhook deliveries are enqueued along with the outbox events, so they do not wait for the broker
+func RelayOutboxEvents(ctx context.Context) error {
+	publisher, err := clients.GetEventPublisher()
+	if err != nil {
+		return err
+	}
+
+	var (
+		lastEventID int64
+
+		runID          = uuid.NewString()
+		skippedLoanIDs = make(map[int64]bool)
+	)
+
+	for {
+		count, err := relayOutboxEventBatch(ctx, publisher, runID, &lastEventID, skippedLoanIDs)
+		if err != nil {
+			return err
+		}
+		if count < outboxEventBatchSize {
+			return nil
+		}
+	}
+}
+
+// relayOutboxEventBatch commits the batch's claim before publishing, so no row lock is held over the broker calls
+func relayOutboxEventBatch(ctx context.Context, publisher clients.EventPublisher, runID string, lastEventID *int64, skippedLoanIDs map[int64]bool) (int, error) {
+	outboxEvents, count, err := claimOutboxEvents(ctx, runID, lastEventID, skippedLoanIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	result := publishOutboxEvents(ctx, publisher, outboxEvents, skippedLoanIDs, configs.Get().EventPublisher.MaxAttempts)
+	for _, outboxEvent := range result.published {
+		isMarked, err := clients.DBUpdateOutboxEventPublishedByID(ctx, nil, outboxEvent.ID, runID)
+		if err != nil {
+			return 0, err
+		}
+		if !isMarked {
+			// the claim expired and another run took the event over, it is published again by that run
+			logrus.Warnf("event %s of loan %d is no longer claimed by relay run %s", outboxEvent.EventID, outboxEvent.LoanID, runID)
+		}
+	}
+
+	for _, failure := range result.failures {
+		if failure.status == constants.OutboxEventStatus_DeadLettered {
+			logrus.Errorf("dead lettered event %s of loan %d after %d attempts. %+v",
+				failure.outboxEvent.EventID, failure.outboxEvent.LoanID, failure.outboxEvent.Attempts+1, failure.err)
+		} else {
+			logrus.Errorf("failed to publish event %s of loan %d. %+v", failure.outboxEvent.EventID, failure.outboxEvent.LoanID, failure.err)
+		}
+		if err = clients.DBUpdateOutboxEventFailureByID(ctx, nil, failure.outboxEvent.ID, runID, failure.status, failure.err.Error()); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(result.releasedIDs) > 0 {
+		if err = clients.DBReleaseOutboxEventClaimByIDs(ctx, nil, result.releasedIDs, runID); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// claimOutboxEvents claims the batch's events for the run, it returns the claimed events and the number of pending events read.
+// Concurrent claims are serialised by the row locks, and an event claimed by another run holds its loan's later events back,
+// so a loan's events are published by one run at a time, in order
+func claimOutboxEvents(ctx context.Context, runID string, lastEventID *int64, skippedLoanIDs map[int64]bool) ([]dtos.OutboxEventModel, int, error) {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	pendingEvents, err := clients.DBGetPendingOutboxEventsForUpdate(ctx, txn, *lastEventID, outboxEventBatchSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(pendingEvents) > 0 {
+		*lastEventID = pendingEvents[len(pendingEvents)-1].ID
+	}
+
+	now := time.Now()
+	outboxEvents := selectClaimableOutboxEvents(pendingEvents, runID, now.UnixMilli(), skippedLoanIDs)
+	if len(outboxEvents) > 0 {
+		ids := make([]int64, 0, len(outboxEvents))
+		for _, outboxEvent := range outboxEvents {
+			ids = append(ids, outboxEvent.ID)
+		}
+		if err = clients.DBUpdateOutboxEventClaimByIDs(ctx, txn, ids, runID, now.Add(outboxEventClaimDuration).UnixMilli()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, 0, err
+	}
+	return outboxEvents, len(pendingEvents), nil
+}
+
+// selectClaimableOutboxEvents returns the pending events the run can claim, in order. An event claimed by another run
+// skips its loan, so the loan's later events are not published ahead of it
+func selectClaimableOutboxEvents(pendingEvents []dtos.OutboxEventModel, runID string, now int64, skippedLoanIDs map[int64]bool) []dtos.OutboxEventModel {
+	outboxEvents := make([]dtos.OutboxEventModel, 0, len(pendingEvents))
+	for _, outboxEvent := range pendingEvents {
+		if skippedLoanIDs[outboxEvent.LoanID] {
+			continue
+		}
+		if outboxEvent.ClaimedBy != runID && outboxEvent.ClaimedUntil > now {
+			skippedLoanIDs[outboxEvent.LoanID] = true
+			continue
+		}
+		outboxEvents = append(outboxEvents, outboxEvent)
+	}
+	return outboxEvents
+}
+
+type outboxEventFailure struct {
+	outboxEvent dtos.OutboxEventModel
+	status      constants.OutboxEventStatus // pending to be retried, or dead lettered
+	err         error
+}
+
+type outboxPublishResult struct {
+	published   []dtos.OutboxEventModel
+	failures    []outboxEventFailure
+	releasedIDs []int64 // held back behind a failed event of their loan
+}
+
+// publishOutboxEvents publishes the claimed events in order. A failed event skips its loan's later events,
+// unless it reaches the max attempts and is dead lettered
+func publishOutboxEvents(ctx context.Context, publisher clients.EventPublisher, outboxEvents []dtos.OutboxEventModel, skippedLoanIDs map[int64]bool, maxAttempts int) outboxPublishResult {
+	var result outboxPublishResult
+	for _, outboxEvent := range outboxEvents {
+		if skippedLoanIDs[outboxEvent.LoanID] {
+			result.releasedIDs = append(result.releasedIDs, outboxEvent.ID)
+			continue
+		}
+
+		if err := publisher.Publish(ctx, newEvent(outboxEvent)); err != nil {
+			failure := outboxEventFailure{outboxEvent: outboxEvent, status: constants.OutboxEventStatus_Pending, err: err}
+			if outboxEvent.Attempts+1 >= maxAttempts {
+				failure.status = constants.OutboxEventStatus_DeadLettered
+			}
+			result.failures = append(result.failures, failure)
+			skippedLoanIDs[outboxEvent.LoanID] = true
+			continue
+		}
+		result.published = append(result.published, outboxEvent)
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// failingEventPublisher fails the events in failedEventIDs and keeps the others in publish order
+type failingEventPublisher struct {
+	*clients.MemoryEventPublisher
+	failedEventIDs map[string]bool
+}
+
+func (p *failingEventPublisher) Publish(ctx context.Context, event dtos.Event) error {
+	if p.failedEventIDs[event.EventID] {
+		return errors.New("broker unavailable")
+	}
+	return p.MemoryEventPublisher.Publish(ctx, event)
+}
+
+func newTestOutboxEvent(id, loanID int64) dtos.OutboxEventModel {
+	return dtos.OutboxEventModel{
+		ID:        id,
+		EventID:   fmt.Sprintf("event-%d", id),
+		EventType: constants.EventType_PaymentReceived,
+		LoanID:    loanID,
+		Payload:   "{}",
+		Status:    constants.OutboxEventStatus_Pending,
+	}
+}
+
+func getEventIDs(events []dtos.Event) []string {
+	eventIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+	return eventIDs
+}
+
+func getOutboxEventIDs(outboxEvents []dtos.OutboxEventModel) []string {
+	eventIDs := make([]string, 0, len(outboxEvents))
+	for _, outboxEvent := range outboxEvents {
+		eventIDs = append(eventIDs, outboxEvent.EventID)
+	}
+	return eventIDs
+}
+
+func assertStrings(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+	}
+}
+
+func TestSelectClaimableOutboxEvents(t *testing.T) {
+	var (
+		now            int64 = 1_000_000
+		runID                = "run-1"
+		skippedLoanIDs       = make(map[int64]bool)
+
+		claimedByOtherRun = newTestOutboxEvent(1, 1)
+		expiredClaim      = newTestOutboxEvent(3, 2)
+		claimedByThisRun  = newTestOutboxEvent(4, 3)
+	)
+	claimedByOtherRun.ClaimedBy, claimedByOtherRun.ClaimedUntil = "run-2", now+1
+	expiredClaim.ClaimedBy, expiredClaim.ClaimedUntil = "run-2", now
+	claimedByThisRun.ClaimedBy, claimedByThisRun.ClaimedUntil = runID, now+1
+
+	outboxEvents := selectClaimableOutboxEvents([]dtos.OutboxEventModel{
+		claimedByOtherRun,
+		newTestOutboxEvent(2, 1), // behind the other run's event of its loan
+		expiredClaim,
+		claimedByThisRun,
+		newTestOutboxEvent(5, 2),
+	}, runID, now, skippedLoanIDs)
+
+	assertStrings(t, "claimed events", getOutboxEventIDs(outboxEvents), []string{"event-3", "event-4", "event-5"})
+	if !skippedLoanIDs[1] || skippedLoanIDs[2] || skippedLoanIDs[3] {
+		t.Errorf("got skipped loans %v, want loan 1 only", skippedLoanIDs)
+	}
+}
+
+func TestPublishOutboxEvents(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		maxAttempts = 3
+		publisher   = &failingEventPublisher{
+			MemoryEventPublisher: clients.NewMemoryEventPublisher(),
+			failedEventIDs:       map[string]bool{"event-1": true, "event-4": true},
+		}
+
+		lastAttempt = newTestOutboxEvent(4, 3)
+	)
+	lastAttempt.Attempts = maxAttempts - 1
+	// loan 1 fails first, loan 2 is published in order, loan 3 fails its last attempt
+	outboxEvents := []dtos.OutboxEventModel{
+		newTestOutboxEvent(1, 1),
+		newTestOutboxEvent(2, 2),
+		newTestOutboxEvent(3, 1),
+		lastAttempt,
+		newTestOutboxEvent(5, 2),
+		newTestOutboxEvent(6, 3),
+	}
+
+	skippedLoanIDs := make(map[int64]bool)
+	result := publishOutboxEvents(ctx, publisher, outboxEvents, skippedLoanIDs, maxAttempts)
+
+	assertStrings(t, "published", getOutboxEventIDs(result.published), []string{"event-2", "event-5"})
+	assertStrings(t, "broker", getEventIDs(publisher.Events()), []string{"event-2", "event-5"})
+	if len(result.failures) != 2 {
+		t.Fatalf("got %d failures, want 2", len(result.failures))
+	}
+	if failure := result.failures[0]; failure.outboxEvent.EventID != "event-1" || failure.status != constants.OutboxEventStatus_Pending {
+		t.Errorf("got failure %s %d, want event-1 to be retried", failure.outboxEvent.EventID, failure.status)
+	}
+	if failure := result.failures[1]; failure.outboxEvent.EventID != "event-4" || failure.status != constants.OutboxEventStatus_DeadLettered {
+		t.Errorf("got failure %s %d, want event-4 dead lettered", failure.outboxEvent.EventID, failure.status)
+	}
+	// the later events of a failed loan are released to keep their order, even behind a dead lettered event within the run
+	if fmt.Sprint(result.releasedIDs) != fmt.Sprint([]int64{3, 6}) {
+		t.Errorf("got released %v, want [3 6]", result.releasedIDs)
+	}
+
+	// the next run retries the failed event ahead of its loan's later events, the dead lettered one is no longer pending
+	publisher.failedEventIDs = nil
+	retriedEvent := newTestOutboxEvent(1, 1)
+	retriedEvent.Attempts = 1
+	result = publishOutboxEvents(ctx, publisher, selectClaimableOutboxEvents([]dtos.OutboxEventModel{
+		retriedEvent,
+		newTestOutboxEvent(3, 1),
+		newTestOutboxEvent(6, 3),
+	}, "run-2", 0, make(map[int64]bool)), make(map[int64]bool), maxAttempts)
+
+	assertStrings(t, "retried", getOutboxEventIDs(result.published), []string{"event-1", "event-3", "event-6"})
+	assertStrings(t, "broker", getEventIDs(publisher.Events()), []string{"event-2", "event-5", "event-1", "event-3", "event-6"})
+	if len(result.failures) != 0 || len(result.releasedIDs) != 0 {
+		t.Errorf("got failures %v and released %v, want none", result.failures, result.releasedIDs)
+	}
+}
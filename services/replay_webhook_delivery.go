@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// ReplayWebhookDelivery attempts a delivered or dead lettered delivery again right away.
+// The delivery's outcome before the replay is recorded and its attempts keep counting,
+// when the attempt fails the delivery goes back to the retries with the full retry budget
+func ReplayWebhookDelivery(ctx context.Context, param dtos.ReplayWebhookDeliveryParam) (*dtos.WebhookDeliveryResponse, error) {
+	if err := validateActor(param.Actor); err != nil {
+		return nil, err
+	}
+
+	delivery, err := clients.DBGetWebhookDeliveryByID(ctx, param.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == constants.WebhookDeliveryStatus_Pending {
+		return nil, fmt.Errorf("%w. delivery is still pending", constants.ErrInvalidValue)
+	}
+
+	subscription, err := clients.DBGetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status != constants.WebhookSubscriptionStatus_Active {
+		return nil, fmt.Errorf("%w. subscription is inactive", constants.ErrInvalidValue)
+	}
+
+	lockedUntil := getWebhookDeliveryLockedUntil(time.Now(), 1)
+	if err = claimWebhookDeliveryReplay(ctx, delivery, param.Actor, lockedUntil); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = constants.WebhookDeliveryStatus_Pending
+	delivery.ReplayCount++
+	delivery.ReplayedAttempts = delivery.Attempts
+	delivery.DeliveredAt = 0
+	delivery.LockedUntil = lockedUntil
+	attemptWebhookDelivery(ctx, subscription, delivery)
+	if _, err = clients.DBUpdateWebhookDeliveryAttemptByID(ctx, nil, delivery); err != nil {
+		return nil, err
+	}
+
+	response := newWebhookDeliveryResponse(*delivery)
+	return &response, nil
+}
+
+func claimWebhookDeliveryReplay(ctx context.Context, delivery *dtos.WebhookDeliveryModel, actor string, lockedUntil int64) error {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	isClaimed, err := clients.DBClaimWebhookDeliveryReplayByID(ctx, txn, delivery, lockedUntil)
+	if err != nil {
+		return err
+	}
+	if !isClaimed {
+		return fmt.Errorf("%w. delivery is being replayed", constants.ErrInvalidValue)
+	}
+
+	if err = clients.DBInsertWebhookDeliveryReplay(ctx, txn, &dtos.WebhookDeliveryReplayModel{
+		DeliveryID:           delivery.ID,
+		PreviousStatus:       delivery.Status,
+		PreviousAttempts:     delivery.Attempts,
+		PreviousResponseCode: delivery.LastResponseCode,
+		PreviousError:        delivery.LastError,
+		Actor:                actor,
+	}); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+)
+
+// ResolveSuspenseItem takes an open item's cash out of the suspense account,
+// either posted as a payment of the given loan or refunded to the payer
+func ResolveSuspenseItem(ctx context.Context, param dtos.ResolveSuspenseItemParam) (*dtos.SuspenseItemResponse, error) {
+	if err := validateResolveSuspenseItem(param); err != nil {
+		return nil, err
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent resolving the same item twice with pessimistic lock
+	itemModel, err := clients.DBGetSuspenseItemByIDForUpdate(ctx, txn, param.SuspenseItemID)
+	if err != nil {
+		return nil, err
+	}
+	if itemModel.Status != constants.SuspenseItemStatus_Open {
+		return nil, fmt.Errorf("%w. suspense item is already resolved", constants.ErrInvalidValue)
+	}
+
+	itemModel.Status = constants.SuspenseItemStatus(param.Status)
+	if itemModel.Status == constants.SuspenseItemStatus_Applied {
+		if itemModel.PaymentID, err = makePayment(ctx, txn, dtos.MakePaymentParam{
+			UserID: param.UserID,
+			LoanID: param.LoanID,
+			Amount: itemModel.Amount.String(),
+		}); err != nil {
+			return nil, err
+		}
+		itemModel.UserID, itemModel.LoanID = param.UserID, param.LoanID
+	}
+
+	// the payment books the cash again when applied, so the suspense is released back to cash either way
+	journalEntry := newJournalEntry(constants.JournalEntryType_Suspense, itemModel.LoanID, itemModel.ID, "cash released from suspense").
+		transfer(constants.LedgerAccount_Suspense, constants.LedgerAccount_Cash, itemModel.Amount)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return nil, err
+	}
+
+	itemModel.ResolvedBy = param.Actor
+	itemModel.ResolvedReason = param.Reason
+	itemModel.ResolvedAt = time.Now().UnixMilli()
+	if err = clients.DBUpdateSuspenseItemResolutionByID(ctx, txn, itemModel); err != nil {
+		return nil, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return nil, err
+	}
+	response := newSuspenseItemResponse(*itemModel)
+	return &response, nil
+}
+
+func validateResolveSuspenseItem(param dtos.ResolveSuspenseItemParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+
+	switch constants.SuspenseItemStatus(param.Status) {
+	case constants.SuspenseItemStatus_Applied:
+		if param.UserID < 1 || param.LoanID < 1 {
+			return fmt.Errorf("%w. user_id and loan_id are required to apply the item", constants.ErrInvalidValue)
+		}
+	case constants.SuspenseItemStatus_Refunded:
+		if param.UserID != 0 || param.LoanID != 0 {
+			return fmt.Errorf("%w. user_id and loan_id are only set to apply the item", constants.ErrInvalidValue)
+		}
+	default:
+		return fmt.Errorf("%w. status should be applied or refunded", constants.ErrInvalidValue)
+	}
+	return nil
+}
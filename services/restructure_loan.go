@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// RestructureLoan cancels the loan's remaining billings and reschedules the outstanding principal with the new terms.
+// Overdue billings are kept unless the arrears are capitalised, in which case their interest, penalty and fee
+// are billed again by the new schedule under the same components
+func RestructureLoan(ctx context.Context, param dtos.RestructureLoanParam) (int64, error) {
+	if err := validateRestructureLoan(param); err != nil {
+		return 0, err
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, param.LoanID)
+	if err != nil {
+		return 0, err
+	}
+	if loanRequestModel.Status != constants.LoanStatus_InRepayment && loanRequestModel.Status != constants.LoanStatus_Defaulted {
+		return 0, fmt.Errorf("%w. only loan in repayment or defaulted can be restructured", constants.ErrInvalidValue)
+	}
+
+	billingModels, err := clients.DBGetBillingsByLoanIDForUpdate(ctx, txn, loanRequestModel.ID)
+	if err != nil {
+		return 0, err
+	}
+	lastRecurringIdx := 0
+	for _, billing := range billingModels {
+		if billing.RecurringIndex > lastRecurringIdx {
+			lastRecurringIdx = billing.RecurringIndex
+		}
+	}
+
+	openBillings, err := clients.DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx, txn, loanRequestModel.ID, math.MaxInt64)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		now = time.Now()
+
+		cancelledBillings          []dtos.BillingModel
+		cancelledBillingIDs        []int64
+		billingHistories           []dtos.BillingHistoryModel
+		outstandingPrincipalAmount = decimal.Zero
+		arrears                    = capitalisedArrears{
+			interestAmount: decimal.Zero,
+			penaltyAmount:  decimal.Zero,
+			feeAmount:      decimal.Zero,
+		}
+	)
+	for _, billing := range openBillings {
+		isOverdue := billing.DueTime < now.UnixMilli()
+		if isOverdue && !param.CapitaliseArrears {
+			continue
+		}
+
+		// the interest of the upcoming billings is not earned yet, it is recalculated by the new schedule
+		outstandingPrincipalAmount = outstandingPrincipalAmount.Add(billing.PrincipalAmount.Sub(billing.PrincipalPaidAmount))
+		if isOverdue {
+			arrears.interestAmount = arrears.interestAmount.Add(billing.InterestAmount.Sub(billing.InterestPaidAmount))
+			arrears.penaltyAmount = arrears.penaltyAmount.Add(billing.PenaltyAmount.Sub(billing.PenaltyPaidAmount))
+			arrears.feeAmount = arrears.feeAmount.Add(billing.FeeAmount.Sub(billing.FeePaidAmount))
+		}
+
+		cancelledBillings = append(cancelledBillings, billing)
+		cancelledBillingIDs = append(cancelledBillingIDs, billing.ID)
+		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
+			BillingID:          billing.BillingID,
+			DueTime:            billing.DueTime,
+			PaymentCompletedAt: billing.PaymentCompletedAt,
+			Status:             constants.PaymentStatus_Cancelled,
+			CreatedAt:          now.UnixMilli(),
+		})
+	}
+	if len(cancelledBillingIDs) == 0 || !outstandingPrincipalAmount.Add(arrears.getTotalAmount()).IsPositive() {
+		return 0, fmt.Errorf("%w. loan has no billing to restructure", constants.ErrInvalidValue)
+	}
+
+	restructureModel := newLoanRestructureModel(param, *loanRequestModel, lastRecurringIdx+1, len(cancelledBillingIDs))
+	restructureModel.OutstandingPrincipalAmount = outstandingPrincipalAmount
+	restructureModel.CapitalisedArrearsAmount = arrears.getTotalAmount()
+
+	newBillings, newBillingHistories, err := createRestructuredSchedule(*loanRequestModel, restructureModel, arrears, now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	restructureID, err := clients.DBInsertLoanRestructure(ctx, txn, &restructureModel)
+	if err != nil {
+		return 0, err
+	}
+	for i := range newBillings {
+		newBillings[i].RestructureID = restructureID
+	}
+
+	// the new billings carry the restructure, the cancelled ones are linked to it
+	restructureBillings := make([]dtos.LoanRestructureBillingModel, 0, len(cancelledBillings))
+	for _, billing := range cancelledBillings {
+		restructureBillings = append(restructureBillings, dtos.LoanRestructureBillingModel{
+			RestructureID: restructureID,
+			LoanID:        loanRequestModel.ID,
+			BillingID:     billing.BillingID,
+		})
+	}
+	if err = clients.DBBatchInsertLoanRestructureBillings(ctx, txn, restructureBillings); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBBulkUpdateBillingStatusByIDs(ctx, txn, cancelledBillingIDs, constants.PaymentStatus_Cancelled); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBBatchInsertBillings(ctx, txn, newBillings); err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBBatchInsertBillingHistories(ctx, txn, append(billingHistories, newBillingHistories...)); err != nil {
+		return 0, err
+	}
+
+	// the unpaid principal stays receivable, the capitalised arrears are reversed and booked again with the new billings
+	journalEntry := newJournalEntry(constants.JournalEntryType_Restructure, loanRequestModel.ID, restructureID, param.Reason).
+		cancelBillings(cancelledBillings).
+		scheduleBillings(newBillings)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return 0, err
+	}
+
+	if err = settleBillingAccruals(ctx, txn, cancelledBillings, now); err != nil {
+		return 0, err
+	}
+
+	loanRequestModel.TenureValue = restructureModel.FirstRecurringIndex + restructureModel.TenureValue - 1
+	loanRequestModel.TenureUnit = restructureModel.TenureUnit
+	loanRequestModel.AnnualInterestRate = restructureModel.AnnualInterestRate
+	loanRequestModel.AmortizationMethod = restructureModel.AmortizationMethod
+	if err = clients.DBUpdateLoanRequestTermsByID(ctx, txn, loanRequestModel); err != nil {
+		return 0, err
+	}
+
+	// the arrears are cleared once they are capitalised
+	if loanRequestModel.Status == constants.LoanStatus_Defaulted && param.CapitaliseArrears {
+		err = transitionLoanStatus(ctx, txn, loanRequestModel, constants.LoanStatus_InRepayment, param.Actor, param.Reason)
+	} else {
+		// not a status transition, only recorded for audit
+		err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
+			{
+				LoanID:              loanRequestModel.ID,
+				PrincipalPaidAmount: decimal.Zero,
+				InterestPaidAmount:  decimal.Zero,
+				Status:              loanRequestModel.Status,
+				Actor:               param.Actor,
+				Reason:              param.Reason,
+				CreatedAt:           now.UnixMilli(),
+			},
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return 0, err
+	}
+	return restructureID, nil
+}
+
+func newLoanRestructureModel(param dtos.RestructureLoanParam, loanModel dtos.LoanRequestModel, firstRecurringIdx, cancelledBillingCount int) dtos.LoanRestructureModel {
+	restructureModel := dtos.LoanRestructureModel{
+		LoanID:                     loanModel.ID,
+		PreviousTenureValue:        loanModel.TenureValue,
+		PreviousTenureUnit:         loanModel.TenureUnit,
+		PreviousAnnualInterestRate: loanModel.AnnualInterestRate,
+		PreviousAmortizationMethod: loanModel.AmortizationMethod,
+		TenureValue:                param.TenureValue,
+		TenureUnit:                 loanModel.TenureUnit,
+		AnnualInterestRate:         loanModel.AnnualInterestRate,
+		AmortizationMethod:         loanModel.AmortizationMethod,
+		FirstRecurringIndex:        firstRecurringIdx,
+		CancelledBillingCount:      cancelledBillingCount,
+		Actor:                      param.Actor,
+		Reason:                     param.Reason,
+	}
+
+	if param.TenureUnit != 0 {
+		restructureModel.TenureUnit = constants.TenureUnit(param.TenureUnit)
+	}
+	if param.AnnualInterestRate != "" {
+		restructureModel.AnnualInterestRate, _ = decimal.NewFromString(param.AnnualInterestRate)
+	}
+	if param.AmortizationMethod != 0 {
+		restructureModel.AmortizationMethod = constants.AmortizationMethod(param.AmortizationMethod)
+	}
+	return restructureModel
+}
+
+type capitalisedArrears struct {
+	interestAmount decimal.Decimal
+	penaltyAmount  decimal.Decimal
+	feeAmount      decimal.Decimal
+}
+
+func (a capitalisedArrears) getTotalAmount() decimal.Decimal {
+	return a.interestAmount.Add(a.penaltyAmount).Add(a.feeAmount)
+}
+
+// createRestructuredSchedule schedules the outstanding principal and the capitalised arrears from the restructuring time,
+// the recurring index continues from the previous schedule
+func createRestructuredSchedule(loanModel dtos.LoanRequestModel, restructureModel dtos.LoanRestructureModel, arrears capitalisedArrears, now int64) ([]dtos.BillingModel, []dtos.BillingHistoryModel, error) {
+	restructuredLoanModel := dtos.LoanRequestModel{
+		ID:                 loanModel.ID,
+		LoanAmount:         restructureModel.OutstandingPrincipalAmount.Add(restructureModel.CapitalisedArrearsAmount),
+		DisbursementTime:   now,
+		TenureValue:        restructureModel.TenureValue,
+		TenureUnit:         restructureModel.TenureUnit,
+		AnnualInterestRate: restructureModel.AnnualInterestRate,
+		AmortizationMethod: restructureModel.AmortizationMethod,
+	}
+
+	billingModels, billingHistories, err := createRepaymentSchedule(restructuredLoanModel, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = validateDailyEconomicBenefitCap(restructuredLoanModel, billingModels, decimal.Zero); err != nil {
+		return nil, nil, err
+	}
+
+	// the capitalised arrears are repaid first, interest then penalty then fee, and reported under their own component
+	// so the principal still sums up to the loan amount
+	for i := range billingModels {
+		billing := &billingModels[i]
+
+		interestAmount := decimal.Min(arrears.interestAmount, billing.PrincipalAmount)
+		arrears.interestAmount = arrears.interestAmount.Sub(interestAmount)
+		billing.PrincipalAmount = billing.PrincipalAmount.Sub(interestAmount)
+		billing.InterestAmount = billing.InterestAmount.Add(interestAmount)
+
+		penaltyAmount := decimal.Min(arrears.penaltyAmount, billing.PrincipalAmount)
+		arrears.penaltyAmount = arrears.penaltyAmount.Sub(penaltyAmount)
+		billing.PrincipalAmount = billing.PrincipalAmount.Sub(penaltyAmount)
+		billing.PenaltyAmount = billing.PenaltyAmount.Add(penaltyAmount)
+		billing.CapitalisedPenaltyAmount = penaltyAmount
+
+		feeAmount := decimal.Min(arrears.feeAmount, billing.PrincipalAmount)
+		arrears.feeAmount = arrears.feeAmount.Sub(feeAmount)
+		billing.PrincipalAmount = billing.PrincipalAmount.Sub(feeAmount)
+		billing.FeeAmount = billing.FeeAmount.Add(feeAmount)
+
+		billing.RecurringIndex += restructureModel.FirstRecurringIndex - 1
+	}
+	return billingModels, billingHistories, nil
+}
+
+func validateRestructureLoan(param dtos.RestructureLoanParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+
+	if param.TenureValue < 1 {
+		return fmt.Errorf("%w. tenure_value should be greater than 0", constants.ErrInvalidValue)
+	}
+
+	if param.TenureUnit != 0 && !constants.TenureUnit(param.TenureUnit).IsValid() {
+		return fmt.Errorf("%w. tenure_unit", constants.ErrInvalidValue)
+	}
+
+	if param.AnnualInterestRate != "" {
+		if err := validateNonNegativeDecimal("annual_interest_rate", param.AnnualInterestRate); err != nil {
+			return err
+		}
+	}
+
+	if param.AmortizationMethod != 0 && !constants.AmortizationMethod(param.AmortizationMethod).IsValid() {
+		return fmt.Errorf("%w. amortization_method", constants.ErrInvalidValue)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCreateRestructuredSchedule(t *testing.T) {
+	initTestConfig(t)
+
+	var (
+		loanModel        = dtos.LoanRequestModel{ID: 42}
+		restructureModel = dtos.LoanRestructureModel{
+			TenureValue:                4,
+			TenureUnit:                 constants.TenureUnit_Month,
+			AnnualInterestRate:         decimal.NewFromInt(12),
+			AmortizationMethod:         constants.AmortizationMethod_Flat,
+			OutstandingPrincipalAmount: decimal.NewFromInt(2_500_000),
+			CapitalisedArrearsAmount:   decimal.NewFromInt(1_500_000),
+			FirstRecurringIndex:        7,
+		}
+		// more than the first billing's principal, so the carving spans two billings
+		arrears = capitalisedArrears{
+			interestAmount: decimal.NewFromInt(800_000),
+			penaltyAmount:  decimal.NewFromInt(400_000),
+			feeAmount:      decimal.NewFromInt(300_000),
+		}
+	)
+
+	billingModels, billingHistories, err := createRestructuredSchedule(loanModel, restructureModel, arrears, time.Now().UnixMilli())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(billingModels) != restructureModel.TenureValue || len(billingHistories) != restructureModel.TenureValue {
+		t.Fatalf("got %d billings and %d histories, want %d", len(billingModels), len(billingHistories), restructureModel.TenureValue)
+	}
+
+	var (
+		principalAmount, interestAmount, penaltyAmount, feeAmount, totalAmount decimal.Decimal
+
+		// the flat interest of the restructured amount, 4,000,000 x 12% over the tenure
+		scheduledInterestAmount = decimal.NewFromInt(480_000)
+		restructuredAmount      = restructureModel.OutstandingPrincipalAmount.Add(restructureModel.CapitalisedArrearsAmount)
+	)
+	for i, billing := range billingModels {
+		if billing.RecurringIndex != restructureModel.FirstRecurringIndex+i {
+			t.Errorf("billing %d: got recurring index %d, want %d", i, billing.RecurringIndex, restructureModel.FirstRecurringIndex+i)
+		}
+		if billing.PrincipalAmount.IsNegative() {
+			t.Errorf("billing %d: got negative principal %s", i, billing.PrincipalAmount)
+		}
+		if componentAmount := billing.PrincipalAmount.Add(billing.InterestAmount).Add(billing.PenaltyAmount).Add(billing.FeeAmount); !componentAmount.Equal(billing.TotalAmount) {
+			t.Errorf("billing %d: got components summing up to %s, want the total %s", i, componentAmount, billing.TotalAmount)
+		}
+		if !billing.CapitalisedPenaltyAmount.Equal(billing.PenaltyAmount) {
+			t.Errorf("billing %d: got capitalised penalty %s, want its penalty %s", i, billing.CapitalisedPenaltyAmount, billing.PenaltyAmount)
+		}
+
+		principalAmount = principalAmount.Add(billing.PrincipalAmount)
+		interestAmount = interestAmount.Add(billing.InterestAmount)
+		penaltyAmount = penaltyAmount.Add(billing.PenaltyAmount)
+		feeAmount = feeAmount.Add(billing.FeeAmount)
+		totalAmount = totalAmount.Add(billing.TotalAmount)
+	}
+
+	// the principal sums up to the outstanding principal, each arrears component is billed under its own component
+	if !principalAmount.Equal(restructureModel.OutstandingPrincipalAmount) {
+		t.Errorf("got principal %s, want %s", principalAmount, restructureModel.OutstandingPrincipalAmount)
+	}
+	if want := scheduledInterestAmount.Add(arrears.interestAmount); !interestAmount.Equal(want) {
+		t.Errorf("got interest %s, want %s", interestAmount, want)
+	}
+	if !penaltyAmount.Equal(arrears.penaltyAmount) || !feeAmount.Equal(arrears.feeAmount) {
+		t.Errorf("got penalty %s and fee %s, want %s and %s", penaltyAmount, feeAmount, arrears.penaltyAmount, arrears.feeAmount)
+	}
+	if carvedAmount := principalAmount.Add(interestAmount.Sub(scheduledInterestAmount)).Add(penaltyAmount).Add(feeAmount); !carvedAmount.Equal(restructuredAmount) {
+		t.Errorf("got principal and arrears of %s, want the restructured amount %s", carvedAmount, restructuredAmount)
+	}
+	if want := restructuredAmount.Add(scheduledInterestAmount); !totalAmount.Equal(want) {
+		t.Errorf("got total %s, want %s", totalAmount, want)
+	}
+
+	// the arrears are repaid first: interest, then penalty, then fee
+	first, second := billingModels[0], billingModels[1]
+	if !first.PrincipalAmount.IsZero() || !first.PenaltyAmount.Equal(decimal.NewFromInt(200_000)) || !first.FeeAmount.IsZero() {
+		t.Errorf("got first billing principal %s, penalty %s and fee %s, want 0, 200000 and 0", first.PrincipalAmount, first.PenaltyAmount, first.FeeAmount)
+	}
+	if !second.PrincipalAmount.Equal(decimal.NewFromInt(500_000)) || !second.PenaltyAmount.Equal(decimal.NewFromInt(200_000)) || !second.FeeAmount.Equal(arrears.feeAmount) {
+		t.Errorf("got second billing principal %s, penalty %s and fee %s, want 500000, 200000 and 300000", second.PrincipalAmount, second.PenaltyAmount, second.FeeAmount)
+	}
+}
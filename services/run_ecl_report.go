@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunECLReport stages every active loan by its days past due as of the end of yesterday and its restructuring,
+// and provisions its expected credit loss from the PD and LGD of its product and stage: ECL = exposure x PD x LGD.
+// The exposure is the loan's balance when the run is taken, so only yesterday can be provisioned, a missed date is not
+// backfilled with today's loans and balances. The run is stored, rerunning it returns the provision as it was reported
+func RunECLReport(ctx context.Context) (*dtos.ECLReportResponse, error) {
+	asOfDate := utils.GetStartOfDay(time.Now()).AddDate(0, 0, -1)
+
+	runModel, err := clients.DBGetECLRunByAsOfDate(ctx, asOfDate.UnixMilli())
+	if err == nil {
+		return getStoredECLReport(ctx, runModel)
+	} else if err != constants.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var (
+		lastLoanID int64
+
+		asOfTime    = asOfDate.AddDate(0, 0, 1)
+		products    = make(map[int64]*loanProduct)
+		reportLoans = make([]dtos.ECLReportLoan, 0)
+	)
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(loanRequestModels) == 0 {
+			break
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			// disbursed today, after the reporting date
+			if loanRequestModel.DisbursementTime >= asOfTime.UnixMilli() {
+				continue
+			}
+
+			productCode := ""
+			if loanRequestModel.ProductID != 0 {
+				product, ok := products[loanRequestModel.ProductID]
+				if !ok {
+					if product, err = getLoanProductByID(ctx, loanRequestModel.ProductID); err != nil {
+						return nil, err
+					}
+					products[loanRequestModel.ProductID] = product
+				}
+				productCode = product.ProductCode
+			}
+
+			billingModels, err := clients.DBGetBillingsByLoanID(ctx, loanRequestModel.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			reportLoan := dtos.ECLReportLoan{
+				LoanID:         loanRequestModel.ID,
+				UserID:         loanRequestModel.UserID,
+				ProductCode:    productCode,
+				DaysPastDue:    getDaysPastDue(billingModels, asOfTime),
+				ExposureAmount: getECLExposureAmount(billingModels),
+			}
+			for _, billing := range billingModels {
+				if billing.RestructureID != 0 {
+					reportLoan.IsRestructured = true
+					break
+				}
+			}
+
+			stage := getECLStage(loanRequestModel, reportLoan.DaysPastDue, reportLoan.IsRestructured)
+			rate, err := getECLRate(productCode, stage)
+			if err != nil {
+				return nil, err
+			}
+			reportLoan.Stage = int8(stage)
+			reportLoan.PD = rate.PD
+			reportLoan.LGD = rate.LGD
+			reportLoan.ECLAmount = reportLoan.ExposureAmount.
+				Mul(rate.PD).Div(constants.Percent).
+				Mul(rate.LGD).Div(constants.Percent).
+				Round(constants.AmountDecimalPlaces)
+			reportLoans = append(reportLoans, reportLoan)
+		}
+	}
+
+	response := newECLReportResponse(asOfDate, reportLoans)
+	runModel = &dtos.ECLRunModel{
+		AsOfDate:            asOfDate.UnixMilli(),
+		LoanCount:           len(reportLoans),
+		TotalExposureAmount: response.TotalExposureAmount,
+		TotalECLAmount:      response.TotalECLAmount,
+	}
+	isStored, err := storeECLRun(ctx, runModel, reportLoans)
+	if err != nil {
+		return nil, err
+	}
+	if !isStored {
+		// a concurrent run of the same date is stored first, it is the reported one
+		if runModel, err = clients.DBGetECLRunByAsOfDate(ctx, asOfDate.UnixMilli()); err != nil {
+			return nil, err
+		}
+		return getStoredECLReport(ctx, runModel)
+	}
+
+	response.ECLRunID = runModel.ID
+	response.CreatedAt = runModel.CreatedAt
+	return response, nil
+}
+
+func storeECLRun(ctx context.Context, runModel *dtos.ECLRunModel, reportLoans []dtos.ECLReportLoan) (bool, error) {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	isInserted, err := clients.DBInsertECLRun(ctx, txn, runModel)
+	if err != nil || !isInserted {
+		return false, err
+	}
+
+	for start := 0; start < len(reportLoans); start += loanBatchSize {
+		end := start + loanBatchSize
+		if end > len(reportLoans) {
+			end = len(reportLoans)
+		}
+
+		runLoanModels := make([]dtos.ECLRunLoanModel, 0, end-start)
+		for _, reportLoan := range reportLoans[start:end] {
+			runLoanModels = append(runLoanModels, dtos.ECLRunLoanModel{
+				RunID:          runModel.ID,
+				LoanID:         reportLoan.LoanID,
+				UserID:         reportLoan.UserID,
+				ProductCode:    reportLoan.ProductCode,
+				DaysPastDue:    reportLoan.DaysPastDue,
+				IsRestructured: reportLoan.IsRestructured,
+				Stage:          reportLoan.Stage,
+				ExposureAmount: reportLoan.ExposureAmount,
+				PD:             reportLoan.PD,
+				LGD:            reportLoan.LGD,
+				ECLAmount:      reportLoan.ECLAmount,
+			})
+		}
+		if err = clients.DBBatchInsertECLRunLoans(ctx, txn, runLoanModels); err != nil {
+			return false, err
+		}
+	}
+
+	if err = clients.DBCommitTransaction(txn); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// getECLStage moves a loan to stage 2 once it is restructured or its days past due exceeds the stage 2 threshold,
+// and to stage 3 once it is defaulted or its days past due exceeds the stage 3 threshold
+func getECLStage(loanRequestModel dtos.LoanRequestModel, daysPastDue int, isRestructured bool) constants.ECLStage {
+	eclConfig := configs.Get().ECL
+	switch {
+	case loanRequestModel.Status == constants.LoanStatus_Defaulted || daysPastDue > eclConfig.Stage3DaysPastDue:
+		return constants.ECLStage_CreditImpaired
+	case isRestructured || daysPastDue > eclConfig.Stage2DaysPastDue:
+		return constants.ECLStage_UnderPerforming
+	}
+	return constants.ECLStage_Performing
+}
+
+// getECLRate falls back to the default rates when the product has none
+func getECLRate(productCode string, stage constants.ECLStage) (configs.ECLRate, error) {
+	eclConfig := configs.Get().ECL
+	rates, ok := eclConfig.Rates[productCode]
+	if !ok {
+		rates = eclConfig.Rates[configs.ECLDefaultRatesKey]
+	}
+
+	rate, ok := rates[int8(stage)]
+	if !ok {
+		return configs.ECLRate{}, fmt.Errorf("%w. ecl rate of product %q stage %d is not configured", constants.ErrInvalidValue, productCode, stage)
+	}
+	return rate, nil
+}
+
+// getECLExposureAmount is the loan's exposure at default: the unpaid principal and the accrued unpaid interest of the open billings
+func getECLExposureAmount(billingModels []dtos.BillingModel) decimal.Decimal {
+	exposureAmount := decimal.Zero
+	for _, billing := range billingModels {
+		if !billing.Status.IsOpen() {
+			continue
+		}
+		exposureAmount = exposureAmount.
+			Add(billing.PrincipalAmount.Sub(billing.PrincipalPaidAmount)).
+			Add(decimal.Max(decimal.Zero, billing.InterestAccruedAmount.Sub(billing.InterestPaidAmount)))
+	}
+	return exposureAmount
+}
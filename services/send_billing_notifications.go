@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// a notification stays pending while its send is in flight, past this its run is considered crashed and it is resent
+	staleNotificationDuration = 15 * time.Minute
+)
+
+type notificationKey struct {
+	billingID string
+	dueTime   int64
+	channel   constants.NotificationChannel
+}
+
+// SendBillingNotifications sends the reminders of the open billings due in 3 days, tomorrow and today, and the overdue notices
+// of the ones 1, 7 and 30 days past their due date, through every enabled channel the user has a contact for.
+// Each notice is sent once per billing due time and channel, a failed one or one left pending by a crashed run is resent
+// when the date is rerun
+func SendBillingNotifications(ctx context.Context, param dtos.SendBillingNotificationsParam) (*dtos.SendBillingNotificationsResponse, error) {
+	notificationConfig := configs.Get().Notification
+
+	today := utils.GetStartOfDay(time.Now().In(notificationConfig.Location))
+	date := today
+	if param.Date != "" {
+		var err error
+		if date, err = time.ParseInLocation(constants.DateLayout, param.Date, notificationConfig.Location); err != nil {
+			return nil, fmt.Errorf("%w. date should be in YYYY-MM-DD format", constants.ErrInvalidValue)
+		}
+		if date.After(today) {
+			return nil, fmt.Errorf("%w. a future date can not be notified", constants.ErrInvalidValue)
+		}
+	}
+
+	var response dtos.SendBillingNotificationsResponse
+	for _, notificationType := range constants.NotificationTypes {
+		dueDate := date.AddDate(0, 0, -notificationType.DueDayOffset())
+		if err := sendBillingNotifications(ctx, notificationType, dueDate, &response); err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+func sendBillingNotifications(ctx context.Context, notificationType constants.NotificationType, dueDate time.Time, response *dtos.SendBillingNotificationsResponse) error {
+	var lastBillingID int64
+	for {
+		billingModels, err := clients.DBGetOpenBillingsByDueTimeRange(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, dueDate.UnixMilli(), dueDate.AddDate(0, 0, 1).UnixMilli(), lastBillingID, loanBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(billingModels) == 0 {
+			return nil
+		}
+
+		billingIDs := make([]string, 0, len(billingModels))
+		for _, billing := range billingModels {
+			billingIDs = append(billingIDs, billing.BillingID)
+		}
+		notificationModels, err := clients.DBGetNotificationsByBillingIDs(ctx, billingIDs, notificationType)
+		if err != nil {
+			return err
+		}
+		notifications := make(map[notificationKey]dtos.NotificationModel, len(notificationModels))
+		for _, notification := range notificationModels {
+			notifications[notificationKey{notification.BillingID, notification.DueTime, notification.Channel}] = notification
+		}
+
+		for _, billing := range billingModels {
+			lastBillingID = billing.ID
+			if err = sendBillingNotification(ctx, notificationType, billing, notifications, response); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// billingNotification is a billing's notice rendered for one of the channels
+type billingNotification struct {
+	channel constants.NotificationChannel
+	sender  clients.NotificationSender
+	message dtos.Notification // the notification id is set once recorded
+}
+
+func sendBillingNotification(
+	ctx context.Context,
+	notificationType constants.NotificationType,
+	billing dtos.BillingNotificationModel,
+	notifications map[notificationKey]dtos.NotificationModel,
+	response *dtos.SendBillingNotificationsResponse,
+) error {
+	billingNotifications, err := newBillingNotifications(notificationType, billing)
+	if err != nil {
+		return err
+	}
+
+	staleBefore := time.Now().Add(-staleNotificationDuration).UnixMilli()
+	for _, billingNotification := range billingNotifications {
+		notification, ok := notifications[notificationKey{billing.BillingID, billing.DueTime, billingNotification.channel}]
+		if ok {
+			isStale := notification.Status == constants.NotificationStatus_Pending && notification.UpdatedAt < staleBefore
+			if notification.Status != constants.NotificationStatus_Failed && !isStale {
+				continue
+			}
+			if ok, err = clients.DBClaimNotificationForResendByID(ctx, nil, notification.ID, staleBefore); err != nil {
+				return err
+			}
+		} else {
+			notification = dtos.NotificationModel{
+				UserID:           billing.UserID,
+				LoanID:           billing.LoanID,
+				BillingID:        billing.BillingID,
+				DueTime:          billing.DueTime,
+				NotificationType: notificationType,
+				Channel:          billingNotification.channel,
+				Locale:           constants.Locale(billingNotification.message.Locale),
+				Recipient:        billingNotification.message.Recipient,
+			}
+			ok, err = clients.DBInsertNotification(ctx, nil, &notification)
+			if err != nil {
+				return err
+			}
+		}
+		// claimed by another run
+		if !ok {
+			continue
+		}
+
+		notification.Attempts++
+		message := billingNotification.message
+		message.NotificationID = notification.ID
+		if err = billingNotification.sender.Send(ctx, message); err != nil {
+			logrus.Errorf("failed to send %s %s notification of billing %s. %+v", billingNotification.channel, notificationType, billing.BillingID, err)
+			notification.Status = constants.NotificationStatus_Failed
+			notification.LastError = err.Error()
+			response.FailedCount++
+		} else {
+			notification.Status = constants.NotificationStatus_Sent
+			notification.LastError = ""
+			notification.SentAt = time.Now().UnixMilli()
+			response.SentCount++
+		}
+		if err = clients.DBUpdateNotificationResultByID(ctx, nil, &notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newBillingNotifications renders the billing's notice in the user's locale for every enabled channel the user has a contact for
+func newBillingNotifications(notificationType constants.NotificationType, billing dtos.BillingNotificationModel) ([]billingNotification, error) {
+	notificationConfig := configs.Get().Notification
+
+	locale := constants.Locale(billing.Locale)
+	if !locale.IsValid() {
+		locale = constants.Locale(notificationConfig.DefaultLocale)
+	}
+	if !locale.IsValid() {
+		locale = constants.Locale_ID
+	}
+
+	subject, body, err := renderNotification(locale, notificationType, notificationTemplateData{
+		Name:           billing.UserName,
+		LoanID:         billing.LoanID,
+		RecurringIndex: billing.RecurringIndex,
+		DueDate:        formatNotificationDate(locale, time.UnixMilli(billing.DueTime).In(notificationConfig.Location)),
+		Amount:         formatNotificationAmount(locale, billing.UnpaidAmount),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	billingNotifications := make([]billingNotification, 0, len(constants.NotificationChannels))
+	for _, channel := range constants.NotificationChannels {
+		sender, ok, err := clients.GetNotificationSender(channel)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		recipient := getNotificationRecipient(channel, billing)
+		if recipient == "" {
+			continue
+		}
+
+		billingNotifications = append(billingNotifications, billingNotification{
+			channel: channel,
+			sender:  sender,
+			message: dtos.Notification{
+				Channel:   string(channel),
+				Recipient: recipient,
+				Locale:    string(locale),
+				Subject:   subject,
+				Body:      body,
+			},
+		})
+	}
+	return billingNotifications, nil
+}
+
+func getNotificationRecipient(channel constants.NotificationChannel, billing dtos.BillingNotificationModel) string {
+	switch channel {
+	case constants.NotificationChannel_Email:
+		return billing.Email
+	case constants.NotificationChannel_SMS:
+		return billing.PhoneNumber
+	case constants.NotificationChannel_Push:
+		return billing.PushToken
+	}
+	return ""
+}
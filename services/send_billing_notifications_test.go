@@ -0,0 +1,109 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+var initTestConfigOnce sync.Once
+
+// initTestConfig loads the repository's configs/app.yaml, the tests run from the package directory
+func initTestConfig(t *testing.T) {
+	t.Helper()
+	initTestConfigOnce.Do(func() {
+		configs.InitFromFile("test", "../configs/app.yaml")
+	})
+}
+
+func TestNewBillingNotifications(t *testing.T) {
+	initTestConfig(t)
+
+	var (
+		location = configs.Get().Notification.Location
+		billing  = dtos.BillingNotificationModel{
+			BillingID:      "billing-1",
+			LoanID:         42,
+			RecurringIndex: 3,
+			DueTime:        time.Date(2026, 2, 5, 23, 59, 59, 0, location).UnixMilli(),
+			UnpaidAmount:   decimal.RequireFromString("221500"),
+			UserID:         7,
+			UserName:       "Budi",
+			Email:          "budi@example.com",
+			PhoneNumber:    "+6281234567890",
+			Locale:         "en",
+		}
+	)
+
+	billingNotifications, err := newBillingNotifications(constants.NotificationType_ReminderDue, billing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no push token, so no push notification
+	if len(billingNotifications) != 2 {
+		t.Fatalf("got %d notifications, want 2", len(billingNotifications))
+	}
+	for i, want := range []struct {
+		channel   constants.NotificationChannel
+		recipient string
+	}{
+		{channel: constants.NotificationChannel_Email, recipient: billing.Email},
+		{channel: constants.NotificationChannel_SMS, recipient: billing.PhoneNumber},
+	} {
+		billingNotification := billingNotifications[i]
+		if billingNotification.channel != want.channel || billingNotification.message.Channel != string(want.channel) {
+			t.Errorf("notification %d is sent through %s, want %s", i, billingNotification.channel, want.channel)
+		}
+		if billingNotification.message.Recipient != want.recipient {
+			t.Errorf("notification %d is sent to %s, want %s", i, billingNotification.message.Recipient, want.recipient)
+		}
+		if billingNotification.sender == nil {
+			t.Errorf("notification %d has no sender", i)
+		}
+		if billingNotification.message.Locale != "en" || billingNotification.message.Subject != "Your bill is due today" {
+			t.Errorf("notification %d is rendered as %s %q", i, billingNotification.message.Locale, billingNotification.message.Subject)
+		}
+		if want := "IDR 221,500 is due today, 5 February 2026."; !strings.Contains(billingNotification.message.Body, want) {
+			t.Errorf("notification %d body %q does not contain %q", i, billingNotification.message.Body, want)
+		}
+	}
+
+	// an unknown locale falls back to the configured default one
+	billing.Locale = "fr"
+	billing.Email = ""
+	billing.PushToken = "push-token"
+	billingNotifications, err = newBillingNotifications(constants.NotificationType_OverdueD7, billing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(billingNotifications) != 2 ||
+		billingNotifications[0].channel != constants.NotificationChannel_SMS ||
+		billingNotifications[1].channel != constants.NotificationChannel_Push {
+		t.Fatalf("got notifications %+v, want sms and push", billingNotifications)
+	}
+	for _, billingNotification := range billingNotifications {
+		if billingNotification.message.Locale != configs.Get().Notification.DefaultLocale {
+			t.Errorf("%s notification is rendered in %s, want %s", billingNotification.channel,
+				billingNotification.message.Locale, configs.Get().Notification.DefaultLocale)
+		}
+		if !strings.Contains(billingNotification.message.Body, "Rp221.500") {
+			t.Errorf("%s notification body %q is not in the default locale", billingNotification.channel, billingNotification.message.Body)
+		}
+	}
+
+	billing.PhoneNumber = ""
+	billing.PushToken = ""
+	if billingNotifications, err = newBillingNotifications(constants.NotificationType_OverdueD7, billing); err != nil {
+		t.Fatal(err)
+	}
+	if len(billingNotifications) != 0 {
+		t.Errorf("got %d notifications for a user without contacts, want 0", len(billingNotifications))
+	}
+}
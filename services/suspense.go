@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// holdInSuspense records cash that is received but can not be allocated to a loan, and books it to the suspense account
+// until it is applied to a loan or refunded by ResolveSuspenseItem
+func holdInSuspense(ctx context.Context, tx *sqlx.Tx, itemModel dtos.SuspenseItemModel) (int64, error) {
+	itemModel.Status = constants.SuspenseItemStatus_Open
+	itemID, err := clients.DBInsertSuspenseItem(ctx, tx, &itemModel)
+	if err != nil {
+		return 0, err
+	}
+
+	journalEntry := newJournalEntry(constants.JournalEntryType_Suspense, itemModel.LoanID, itemID, "cash held in suspense").
+		transfer(constants.LedgerAccount_Cash, constants.LedgerAccount_Suspense, itemModel.Amount)
+	if err = postJournalEntry(ctx, tx, journalEntry); err != nil {
+		return 0, err
+	}
+	return itemID, nil
+}
+
+func newSuspenseItemResponse(itemModel dtos.SuspenseItemModel) dtos.SuspenseItemResponse {
+	return dtos.SuspenseItemResponse{
+		SuspenseItemID: itemModel.ID,
+		Source:         itemModel.Source,
+		ReferenceID:    itemModel.ReferenceID,
+		UserID:         itemModel.UserID,
+		LoanID:         itemModel.LoanID,
+		Amount:         itemModel.Amount,
+		Reason:         itemModel.Reason,
+		Status:         int8(itemModel.Status),
+		PaymentID:      itemModel.PaymentID,
+		ResolvedBy:     itemModel.ResolvedBy,
+		ResolvedReason: itemModel.ResolvedReason,
+		ResolvedAt:     itemModel.ResolvedAt,
+		CreatedAt:      itemModel.CreatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SyncCollectionCases opens a collection case for every delinquent loan, moves the open cases across the days past due buckets,
+// and closes the ones whose loan is cured, i.e. has no overdue billing anymore
+func SyncCollectionCases(ctx context.Context) (*dtos.SyncCollectionCasesResponse, error) {
+	var (
+		lastLoanID int64
+		response   dtos.SyncCollectionCasesResponse
+	)
+
+	for {
+		loanRequestModels, err := clients.DBGetLoanRequestsByStatuses(ctx, []constants.LoanStatus{
+			constants.LoanStatus_InRepayment,
+			constants.LoanStatus_Defaulted,
+		}, lastLoanID, loanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(loanRequestModels) == 0 {
+			return &response, nil
+		}
+
+		for _, loanRequestModel := range loanRequestModels {
+			lastLoanID = loanRequestModel.ID
+			if err = syncLoanCollectionCase(ctx, loanRequestModel.ID, &response); err != nil {
+				logrus.Errorf("failed to sync collection case of loan %d. %+v", loanRequestModel.ID, err)
+			}
+		}
+	}
+}
+
+func syncLoanCollectionCase(ctx context.Context, loanID int64, response *dtos.SyncCollectionCasesResponse) error {
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent opening a second case with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, loanID)
+	if err != nil {
+		return err
+	}
+	if loanRequestModel.Status != constants.LoanStatus_InRepayment && loanRequestModel.Status != constants.LoanStatus_Defaulted {
+		return nil
+	}
+
+	overdueBillings, err := clients.DBGetOverdueBillings(ctx, loanID)
+	if err != nil {
+		return err
+	}
+	daysPastDue := getDaysPastDue(overdueBillings, time.Now())
+	bucket := getCollectionBucket(daysPastDue)
+
+	caseModel, err := clients.DBGetOpenCollectionCaseByLoanIDForUpdate(ctx, txn, loanID)
+	if err != nil && err != constants.ErrRecordNotFound {
+		return err
+	}
+
+	switch {
+	case caseModel == nil:
+		delinquent, err := isDelinquentLoan(ctx, *loanRequestModel, overdueBillings)
+		if err != nil {
+			return err
+		}
+		if !delinquent {
+			return nil
+		}
+
+		caseID, err := clients.DBInsertCollectionCase(ctx, txn, &dtos.CollectionCaseModel{
+			LoanID:      loanID,
+			UserID:      loanRequestModel.UserID,
+			Status:      constants.CollectionCaseStatus_Open,
+			Bucket:      bucket,
+			DaysPastDue: daysPastDue,
+			OpenedAt:    time.Now().UnixMilli(),
+		})
+		if err != nil {
+			return err
+		}
+		if err = clients.DBBatchInsertCollectionActivities(ctx, txn, []dtos.CollectionActivityModel{
+			{
+				CaseID:       caseID,
+				LoanID:       loanID,
+				ActivityType: constants.CollectionActivityType_Opened,
+				Actor:        constants.LoanActor_System,
+				Notes:        fmt.Sprintf("%d overdue billings, %d days past due", len(overdueBillings), daysPastDue),
+			},
+		}); err != nil {
+			return err
+		}
+		response.OpenedCount++
+
+	case len(overdueBillings) == 0:
+		if err = closeCollectionCase(ctx, txn, caseModel, constants.CollectionCaseCloseReason_Cured, constants.LoanActor_System); err != nil {
+			return err
+		}
+		response.ClosedCount++
+
+	case caseModel.Bucket != bucket || caseModel.DaysPastDue != daysPastDue:
+		if err = clients.DBUpdateCollectionCaseBucketByID(ctx, txn, caseModel.ID, bucket, daysPastDue); err != nil {
+			return err
+		}
+		if caseModel.Bucket != bucket {
+			if err = clients.DBBatchInsertCollectionActivities(ctx, txn, []dtos.CollectionActivityModel{
+				{
+					CaseID:       caseModel.ID,
+					LoanID:       loanID,
+					ActivityType: constants.CollectionActivityType_BucketChanged,
+					Actor:        constants.LoanActor_System,
+					Notes:        fmt.Sprintf("bucket %d to %d, %d days past due", caseModel.Bucket, bucket, daysPastDue),
+				},
+			}); err != nil {
+				return err
+			}
+			response.BucketChangedCount++
+		}
+
+	default:
+		return nil
+	}
+
+	return clients.DBCommitTransaction(txn)
+}
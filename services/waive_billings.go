@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// WaiveBillings forgives the unpaid amount of the loan's open billings, the paid part is kept
+func WaiveBillings(ctx context.Context, param dtos.WaiveBillingsParam) error {
+	if err := validateWaiveBillings(param); err != nil {
+		return err
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, param.LoanID)
+	if err != nil {
+		return err
+	}
+	if !loanRequestModel.Status.IsDisbursed() {
+		return fmt.Errorf("%w. loan request is not disbursed yet", constants.ErrInvalidValue)
+	}
+
+	billingModels, err := clients.DBGetBillingsByBillingIDsForUpdate(ctx, txn, loanRequestModel.ID, param.BillingIDs)
+	if err != nil {
+		return err
+	}
+	if len(billingModels) != len(param.BillingIDs) {
+		return fmt.Errorf("%w. billing_ids", constants.ErrInvalidValue)
+	}
+
+	var (
+		now = time.Now().UnixMilli()
+
+		ids              = make([]int64, 0, len(billingModels))
+		billingHistories = make([]dtos.BillingHistoryModel, 0, len(billingModels))
+	)
+	for _, billing := range billingModels {
+		if !billing.Status.IsOpen() {
+			return fmt.Errorf("%w. billing %s is not open", constants.ErrInvalidValue, billing.BillingID)
+		}
+
+		ids = append(ids, billing.ID)
+		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
+			BillingID:          billing.BillingID,
+			DueTime:            billing.DueTime,
+			PaymentCompletedAt: billing.PaymentCompletedAt,
+			Status:             constants.PaymentStatus_Waived,
+			CreatedAt:          now,
+		})
+	}
+
+	if err = clients.DBBulkUpdateBillingStatusByIDs(ctx, txn, ids, constants.PaymentStatus_Waived); err != nil {
+		return err
+	}
+
+	if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+		return err
+	}
+
+	journalEntry := newJournalEntry(constants.JournalEntryType_Waiver, loanRequestModel.ID, loanRequestModel.ID, param.Reason).
+		writeDownBillings(billingModels, constants.LedgerAccount_WaiverExpense)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return err
+	}
+
+	if err = settleBillingAccruals(ctx, txn, billingModels, time.UnixMilli(now)); err != nil {
+		return err
+	}
+
+	remainingBillings, err := clients.DBCountOpenBillingsByLoanID(ctx, txn, loanRequestModel.ID)
+	if err != nil {
+		return err
+	}
+	if remainingBillings == 0 {
+		err = completeLoanRequest(ctx, txn, loanRequestModel, param.Actor, param.Reason)
+	} else {
+		// not a status transition, only recorded for audit
+		err = clients.DBBatchInsertLoanRequestHistories(ctx, txn, []dtos.LoanRequestHistory{
+			{
+				LoanID:              loanRequestModel.ID,
+				PrincipalPaidAmount: decimal.Zero,
+				InterestPaidAmount:  decimal.Zero,
+				Status:              loanRequestModel.Status,
+				Actor:               param.Actor,
+				Reason:              param.Reason,
+				CreatedAt:           now,
+			},
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
+
+func validateWaiveBillings(param dtos.WaiveBillingsParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+	if len(param.BillingIDs) == 0 {
+		return fmt.Errorf("%w. billing_ids is required", constants.ErrInvalidValue)
+	}
+	return nil
+}
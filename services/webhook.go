@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	webhookHeaderEventID   = "X-Webhook-Event-Id"
+	webhookHeaderEventType = "X-Webhook-Event-Type"
+	webhookHeaderTimestamp = "X-Webhook-Timestamp"
+	webhookHeaderSignature = "X-Webhook-Signature"
+
+	webhookSignaturePrefix = "sha256="
+)
+
+func isWebhookSubscribed(subscription dtos.WebhookSubscriptionModel, eventType constants.EventType) bool {
+	for _, subscribedType := range strings.Split(subscription.EventTypes, ",") {
+		if constants.EventType(subscribedType) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueWebhookDeliveries records a delivery of the event for each of the subscriptions subscribed to it
+func enqueueWebhookDeliveries(ctx context.Context, tx *sqlx.Tx, subscriptions []dtos.WebhookSubscriptionModel, event dtos.Event) error {
+	var deliveryModels []dtos.WebhookDeliveryModel
+	for _, subscription := range subscriptions {
+		if !isWebhookSubscribed(subscription, constants.EventType(event.EventType)) {
+			continue
+		}
+
+		if len(deliveryModels) == 0 {
+			deliveryModels = make([]dtos.WebhookDeliveryModel, 0, len(subscriptions))
+		}
+		deliveryModels = append(deliveryModels, dtos.WebhookDeliveryModel{
+			SubscriptionID: subscription.ID,
+			EventID:        event.EventID,
+			EventType:      constants.EventType(event.EventType),
+			LoanID:         event.LoanID,
+		})
+	}
+	if len(deliveryModels) == 0 {
+		return nil
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	for i := range deliveryModels {
+		deliveryModels[i].Payload = string(content)
+	}
+	return clients.DBBatchInsertWebhookDeliveries(ctx, tx, deliveryModels)
+}
+
+// signWebhookPayload signs "<timestamp>.<payload>" so a captured request can not be replayed with another timestamp
+func signWebhookPayload(secret string, timestamp int64, payload string) string {
+	message := strconv.FormatInt(timestamp, 10) + "." + payload
+	return webhookSignaturePrefix + utils.SignHMACSHA256(secret, []byte(message))
+}
+
+// getWebhookBackoff doubles the wait after each failed attempt, up to the configured max
+func getWebhookBackoff(attempts int) time.Duration {
+	webhookConfig := configs.Get().Webhook
+
+	backoff := webhookConfig.InitialBackoff
+	for i := 1; i < attempts && backoff < webhookConfig.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookConfig.MaxBackoff {
+		backoff = webhookConfig.MaxBackoff
+	}
+	return backoff
+}
+
+// getWebhookDeliveryLockedUntil gives the claim of a run enough time to attempt the deliveries one after another
+func getWebhookDeliveryLockedUntil(now time.Time, count int) int64 {
+	return now.Add(time.Duration(count+1) * configs.Get().Webhook.Timeout).UnixMilli()
+}
+
+// attemptWebhookDelivery posts the delivery once and records the outcome on the model
+func attemptWebhookDelivery(ctx context.Context, subscription *dtos.WebhookSubscriptionModel, delivery *dtos.WebhookDeliveryModel) {
+	now := time.Now()
+
+	delivery.Attempts++
+	if subscription == nil || subscription.Status != constants.WebhookSubscriptionStatus_Active {
+		delivery.Status = constants.WebhookDeliveryStatus_DeadLettered
+		delivery.LastResponseCode = 0
+		delivery.LastError = "subscription is inactive"
+		return
+	}
+
+	timestamp := now.Unix()
+	statusCode, err := clients.PostWebhook(ctx, subscription.URL, map[string]string{
+		webhookHeaderEventID:   delivery.EventID,
+		webhookHeaderEventType: string(delivery.EventType),
+		webhookHeaderTimestamp: strconv.FormatInt(timestamp, 10),
+		webhookHeaderSignature: signWebhookPayload(subscription.Secret, timestamp, delivery.Payload),
+	}, []byte(delivery.Payload))
+
+	delivery.LastResponseCode = statusCode
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.Status = constants.WebhookDeliveryStatus_Delivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = now.UnixMilli()
+		return
+	}
+
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("unexpected response status %d", statusCode)
+	}
+	// a replayed delivery gets the retries again, its earlier attempts stay counted
+	retryAttempts := delivery.Attempts - delivery.ReplayedAttempts
+	if retryAttempts >= configs.Get().Webhook.MaxAttempts {
+		delivery.Status = constants.WebhookDeliveryStatus_DeadLettered
+		return
+	}
+	delivery.NextAttemptAt = now.Add(getWebhookBackoff(retryAttempts)).UnixMilli()
+}
+
+func newWebhookDeliveryResponse(delivery dtos.WebhookDeliveryModel) dtos.WebhookDeliveryResponse {
+	return dtos.WebhookDeliveryResponse{
+		DeliveryID:       delivery.ID,
+		SubscriptionID:   delivery.SubscriptionID,
+		EventID:          delivery.EventID,
+		EventType:        string(delivery.EventType),
+		LoanID:           delivery.LoanID,
+		Payload:          json.RawMessage(delivery.Payload),
+		Status:           int8(delivery.Status),
+		Attempts:         delivery.Attempts,
+		NextAttemptAt:    delivery.NextAttemptAt,
+		LastResponseCode: delivery.LastResponseCode,
+		LastError:        delivery.LastError,
+		DeliveredAt:      delivery.DeliveredAt,
+		ReplayCount:      delivery.ReplayCount,
+		CreatedAt:        delivery.CreatedAt,
+	}
+}
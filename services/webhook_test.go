@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"loan-payment/configs"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+	"loan-payment/utils"
+)
+
+const testWebhookSecret = "a-webhook-secret-of-at-least-32-characters"
+
+func TestSignWebhookPayload(t *testing.T) {
+	var (
+		timestamp int64 = 1_760_000_000
+		payload         = `{"event_id":"event-1"}`
+	)
+
+	signature := signWebhookPayload(testWebhookSecret, timestamp, payload)
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		t.Fatalf("got signature %s, want the %s prefix", signature, webhookSignaturePrefix)
+	}
+	if !utils.VerifyHMACSHA256(testWebhookSecret, []byte("1760000000."+payload), strings.TrimPrefix(signature, webhookSignaturePrefix)) {
+		t.Errorf("signature %s is not over \"<timestamp>.<payload>\"", signature)
+	}
+	// a captured payload replayed with another timestamp does not carry a valid signature
+	if signWebhookPayload(testWebhookSecret, timestamp+1, payload) == signature {
+		t.Error("the signature does not change with the timestamp")
+	}
+}
+
+func TestGetWebhookBackoff(t *testing.T) {
+	initTestConfig(t)
+
+	webhookConfig := configs.Get().Webhook
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: webhookConfig.InitialBackoff},
+		{attempts: 2, want: 2 * webhookConfig.InitialBackoff},
+		{attempts: 4, want: 8 * webhookConfig.InitialBackoff},
+		{attempts: 30, want: webhookConfig.MaxBackoff},
+		{attempts: 1000, want: webhookConfig.MaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := getWebhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("attempts %d: got backoff %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+
+	// the backoff doubles until the max, and never goes past it
+	previousBackoff := time.Duration(0)
+	for attempts := 1; attempts <= 20; attempts++ {
+		backoff := getWebhookBackoff(attempts)
+		if backoff < previousBackoff || backoff > webhookConfig.MaxBackoff {
+			t.Errorf("attempts %d: got backoff %v after %v, want it within %v", attempts, backoff, previousBackoff, webhookConfig.MaxBackoff)
+		}
+		previousBackoff = backoff
+	}
+}
+
+// newTestWebhookServer answers with the status code, once it verifies the signed headers
+func newTestWebhookServer(t *testing.T, statusCode int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhookHeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header %q", r.Header.Get(webhookHeaderTimestamp))
+		}
+		if signature := r.Header.Get(webhookHeaderSignature); signature != signWebhookPayload(testWebhookSecret, timestamp, string(body)) {
+			t.Errorf("got signature %s of the payload %s", signature, string(body))
+		}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAttemptWebhookDelivery(t *testing.T) {
+	initTestConfig(t)
+
+	var (
+		ctx         = context.Background()
+		maxAttempts = configs.Get().Webhook.MaxAttempts
+	)
+	newSubscription := func(url string) *dtos.WebhookSubscriptionModel {
+		return &dtos.WebhookSubscriptionModel{ID: 1, URL: url, Secret: testWebhookSecret, Status: constants.WebhookSubscriptionStatus_Active}
+	}
+	newDelivery := func(attempts, replayedAttempts int) *dtos.WebhookDeliveryModel {
+		return &dtos.WebhookDeliveryModel{
+			ID:               1,
+			SubscriptionID:   1,
+			EventID:          "event-1",
+			EventType:        constants.EventType_PaymentReceived,
+			Payload:          `{"event_id":"event-1"}`,
+			Status:           constants.WebhookDeliveryStatus_Pending,
+			Attempts:         attempts,
+			ReplayedAttempts: replayedAttempts,
+		}
+	}
+
+	var (
+		failingSubscription   = newSubscription(newTestWebhookServer(t, http.StatusInternalServerError).URL)
+		deliveredSubscription = newSubscription(newTestWebhookServer(t, http.StatusNoContent).URL)
+	)
+
+	delivery := newDelivery(0, 0)
+	attemptWebhookDelivery(ctx, deliveredSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_Delivered || delivery.Attempts != 1 || delivery.DeliveredAt == 0 {
+		t.Errorf("got delivery %+v, want it delivered on the first attempt", delivery)
+	}
+
+	// the attempts before the last one are retried after the backoff
+	delivery = newDelivery(maxAttempts-2, 0)
+	before := time.Now()
+	attemptWebhookDelivery(ctx, failingSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_Pending || delivery.LastResponseCode != http.StatusInternalServerError {
+		t.Errorf("got delivery %+v, want it pending to be retried", delivery)
+	}
+	if wantAt := before.Add(getWebhookBackoff(maxAttempts - 1)).UnixMilli(); delivery.NextAttemptAt < wantAt {
+		t.Errorf("got next attempt at %d, want it at %d or later", delivery.NextAttemptAt, wantAt)
+	}
+
+	// the last attempt failing dead letters the delivery
+	delivery = newDelivery(maxAttempts-1, 0)
+	attemptWebhookDelivery(ctx, failingSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_DeadLettered || delivery.Attempts != maxAttempts {
+		t.Errorf("got delivery %+v, want it dead lettered after %d attempts", delivery, maxAttempts)
+	}
+
+	// a replayed delivery failing again gets the full retry budget, its attempts keep counting
+	delivery.ReplayedAttempts = delivery.Attempts
+	delivery.Status = constants.WebhookDeliveryStatus_Pending
+	before = time.Now()
+	attemptWebhookDelivery(ctx, failingSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_Pending || delivery.Attempts != maxAttempts+1 {
+		t.Errorf("got replayed delivery %+v, want it pending after %d attempts", delivery, maxAttempts+1)
+	}
+	if wantAt := before.Add(getWebhookBackoff(1)).UnixMilli(); delivery.NextAttemptAt < wantAt || delivery.NextAttemptAt >= before.Add(getWebhookBackoff(2)).UnixMilli() {
+		t.Errorf("got next attempt at %d, want the first backoff from %d", delivery.NextAttemptAt, wantAt)
+	}
+
+	// the replay is dead lettered once it uses its retries up
+	delivery = newDelivery(2*maxAttempts-1, maxAttempts)
+	attemptWebhookDelivery(ctx, failingSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_DeadLettered {
+		t.Errorf("got replayed delivery %+v, want it dead lettered after its retries", delivery)
+	}
+
+	// an inactive subscription dead letters its deliveries without sending them
+	failingSubscription.Status = constants.WebhookSubscriptionStatus_Inactive
+	delivery = newDelivery(0, 0)
+	attemptWebhookDelivery(ctx, failingSubscription, delivery)
+	if delivery.Status != constants.WebhookDeliveryStatus_DeadLettered || delivery.LastResponseCode != 0 {
+		t.Errorf("got delivery %+v, want it dead lettered without a response", delivery)
+	}
+}
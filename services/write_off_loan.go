@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"loan-payment/clients"
+	"loan-payment/constants"
+	"loan-payment/dtos"
+
+	"github.com/shopspring/decimal"
+)
+
+// WriteOffLoan writes off the unpaid amount of a defaulted loan's open billings, with the accrued but unpaid interest only,
+// later payments of the loan are recorded as recoveries
+func WriteOffLoan(ctx context.Context, param dtos.WriteOffLoanParam) error {
+	if err := validateActor(param.Actor); err != nil {
+		return err
+	}
+	if param.Reason == "" {
+		return fmt.Errorf("%w. reason is required", constants.ErrInvalidValue)
+	}
+
+	txn, err := clients.DBBeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer clients.DBRollbackTransaction(txn)
+
+	// prevent update racing with pessimistic lock
+	loanRequestModel, err := clients.DBGetLoanRequestByIDForUpdate(ctx, txn, param.LoanID)
+	if err != nil {
+		return err
+	}
+	if loanRequestModel.Status != constants.LoanStatus_Defaulted {
+		return fmt.Errorf("%w. only defaulted loan can be written off", constants.ErrInvalidValue)
+	}
+
+	openBillings, err := clients.DBGetOpenBillingsWithDueTimeByLoanIdForUpdate(ctx, txn, loanRequestModel.ID, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now = time.Now().UnixMilli()
+
+		billingIDs       = make([]int64, 0, len(openBillings))
+		billingHistories = make([]dtos.BillingHistoryModel, 0, len(openBillings))
+		writeOffModel    = dtos.LoanWriteOffModel{
+			LoanID:          loanRequestModel.ID,
+			PrincipalAmount: decimal.Zero,
+			InterestAmount:  decimal.Zero,
+			PenaltyAmount:   decimal.Zero,
+			FeeAmount:       decimal.Zero,
+			Actor:           param.Actor,
+			Reason:          param.Reason,
+		}
+	)
+	for _, billing := range openBillings {
+		writeOffModel.PrincipalAmount = writeOffModel.PrincipalAmount.Add(billing.PrincipalAmount.Sub(billing.PrincipalPaidAmount))
+		// only the accrued interest is receivable, the unearned interest is reversed as the ledger does and not written off
+		writeOffModel.InterestAmount = writeOffModel.InterestAmount.Add(decimal.Max(decimal.Zero, billing.InterestAccruedAmount.Sub(billing.InterestPaidAmount)))
+		writeOffModel.PenaltyAmount = writeOffModel.PenaltyAmount.Add(billing.PenaltyAmount.Sub(billing.PenaltyPaidAmount))
+		writeOffModel.FeeAmount = writeOffModel.FeeAmount.Add(billing.FeeAmount.Sub(billing.FeePaidAmount))
+
+		billingIDs = append(billingIDs, billing.ID)
+		billingHistories = append(billingHistories, dtos.BillingHistoryModel{
+			BillingID:          billing.BillingID,
+			DueTime:            billing.DueTime,
+			PaymentCompletedAt: billing.PaymentCompletedAt,
+			Status:             constants.PaymentStatus_WrittenOff,
+			CreatedAt:          now,
+		})
+	}
+
+	writeOffID, err := clients.DBInsertLoanWriteOff(ctx, txn, &writeOffModel)
+	if err != nil {
+		return err
+	}
+
+	journalEntry := newJournalEntry(constants.JournalEntryType_WriteOff, loanRequestModel.ID, writeOffID, param.Reason).
+		writeDownBillings(openBillings, constants.LedgerAccount_WriteOffExpense)
+	if err = postJournalEntry(ctx, txn, journalEntry); err != nil {
+		return err
+	}
+
+	if err = settleBillingAccruals(ctx, txn, openBillings, time.UnixMilli(now)); err != nil {
+		return err
+	}
+
+	if len(billingIDs) > 0 {
+		if err = clients.DBBulkUpdateBillingStatusByIDs(ctx, txn, billingIDs, constants.PaymentStatus_WrittenOff); err != nil {
+			return err
+		}
+
+		if err = clients.DBBatchInsertBillingHistories(ctx, txn, billingHistories); err != nil {
+			return err
+		}
+	}
+
+	if err = transitionLoanStatus(ctx, txn, loanRequestModel, constants.LoanStatus_WrittenOff, param.Actor, param.Reason); err != nil {
+		return err
+	}
+	return clients.DBCommitTransaction(txn)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"loan-payment/constants"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	effectiveRateIterations = 200
+	effectiveRateLowerBound = -0.99
+	effectiveRateUpperBound = 100.0
+)
+
+type CashFlow struct {
+	Time   time.Time
+	Amount decimal.Decimal // negative for money going to the borrower
+}
+
+// GetPeriodInterestRate converts an annual interest rate (inflated by 10^2) into the rate of a single tenure period
+func GetPeriodInterestRate(annualInterestRate decimal.Decimal, tenureUnit constants.TenureUnit) decimal.Decimal {
+	periodsPerYear := tenureUnit.PeriodsPerYear()
+	if periodsPerYear == 0 {
+		return decimal.Zero
+	}
+	return annualInterestRate.Div(constants.Percent).Div(decimal.NewFromInt(periodsPerYear))
+}
+
+// CalculateEffectiveAnnualRate finds the annual rate (inflated by 10^2) that discounts the cash flows to zero,
+// each cash flow is discounted by its actual number of days from the first one over a 365 days year
+func CalculateEffectiveAnnualRate(cashFlows []CashFlow) (decimal.Decimal, error) {
+	if len(cashFlows) < 2 {
+		return decimal.Zero, fmt.Errorf("at least 2 cash flows are required to calculate the effective annual rate")
+	}
+
+	var (
+		startTime = cashFlows[0].Time
+		lowerRate = effectiveRateLowerBound
+		upperRate = effectiveRateUpperBound
+	)
+
+	netPresentValue := func(rate float64) float64 {
+		var total float64
+		for _, cashFlow := range cashFlows {
+			amount, _ := cashFlow.Amount.Float64()
+			years := cashFlow.Time.Sub(startTime).Hours() / 24 / 365
+			total += amount / math.Pow(1+rate, years)
+		}
+		return total
+	}
+
+	lowerValue, upperValue := netPresentValue(lowerRate), netPresentValue(upperRate)
+	if (lowerValue > 0) == (upperValue > 0) {
+		return decimal.Zero, fmt.Errorf("unable to find the effective annual rate of the cash flows")
+	}
+
+	// bisection, the net present value is monotonic as long as the borrower receives money before repaying it
+	for i := 0; i < effectiveRateIterations; i++ {
+		midRate := (lowerRate + upperRate) / 2
+		midValue := netPresentValue(midRate)
+		if (midValue > 0) == (lowerValue > 0) {
+			lowerRate, lowerValue = midRate, midValue
+		} else {
+			upperRate = midRate
+		}
+	}
+	return decimal.NewFromFloat((lowerRate + upperRate) / 2).Mul(constants.Percent).Round(constants.AmountDecimalPlaces), nil
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignHMACSHA256 returns the hex encoded HMAC-SHA256 of the message
+func SignHMACSHA256(secret string, message []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMACSHA256 compares the signature in constant time
+func VerifyHMACSHA256(secret string, message []byte, signature string) bool {
+	return hmac.Equal([]byte(SignHMACSHA256(secret, message)), []byte(signature))
+}
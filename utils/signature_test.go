@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestSignHMACSHA256(t *testing.T) {
+	// RFC 4231 test case 2
+	var (
+		secret  = "Jefe"
+		message = []byte("what do ya want for nothing?")
+		want    = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	)
+
+	signature := SignHMACSHA256(secret, message)
+	if signature != want {
+		t.Errorf("got signature %s, want %s", signature, want)
+	}
+	if !VerifyHMACSHA256(secret, message, signature) {
+		t.Error("the signature is not verified")
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		message   []byte
+		signature string
+	}{
+		{name: "another secret", secret: "Jeff", message: message, signature: signature},
+		{name: "another message", secret: secret, message: []byte("what do ya want for something?"), signature: signature},
+		{name: "truncated signature", secret: secret, message: message, signature: signature[:len(signature)-2]},
+		{name: "upper case signature", secret: secret, message: message, signature: "5BDCC146BF60754E6A042426089575C75A003F089D2739839DEC58B964EC3843"},
+	}
+	for _, tt := range tests {
+		if VerifyHMACSHA256(tt.secret, tt.message, tt.signature) {
+			t.Errorf("%s: the signature is verified", tt.name)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/gorhill/cronexpr"
@@ -9,26 +8,67 @@ import (
 	"loan-payment/constants"
 )
 
-func GetNextTenureSchedule(startTime time.Time, timeUnit constants.TenureUnit) *time.Time {
-	var (
-		expr     *cronexpr.Expression
-		nextTime time.Time
-	)
+// GetTenureSchedule returns the due time of the given period counted from the anchor, e.g. the end of the disbursement day
+func GetTenureSchedule(anchorTime time.Time, timeUnit constants.TenureUnit, periods int) *time.Time {
+	return AddTenurePeriods(anchorTime, anchorTime.Day(), timeUnit, periods)
+}
+
+// AddTenurePeriods moves the due time by the given periods. month based units land on the anchor day and a shorter month
+// clamps it to its last day, so a schedule anchored on the 31st keeps every month instead of skipping the short ones
+func AddTenurePeriods(startTime time.Time, anchorDay int, timeUnit constants.TenureUnit, periods int) *time.Time {
+	var nextTime time.Time
 
 	switch timeUnit {
 	case constants.TenureUnit_Day:
-		expr = cronexpr.MustParse("59 23 * * *")
+		nextTime = GetEndOfDay(startTime.AddDate(0, 0, periods))
 	case constants.TenureUnit_Week:
-		expr = cronexpr.MustParse(fmt.Sprintf("59 23 * * %d", startTime.Weekday()))
+		nextTime = GetEndOfDay(startTime.AddDate(0, 0, 7*periods))
+	case constants.TenureUnit_BiWeek:
+		nextTime = GetEndOfDay(startTime.AddDate(0, 0, 14*periods))
+	case constants.TenureUnit_SemiMonth:
+		// due on the 15th and the last day of every month whatever the anchor is
+		expr := cronexpr.MustParse("59 23 15,L * *")
+		nextTime = startTime
+		for period := 0; period < periods; period++ {
+			nextTime = expr.Next(nextTime)
+		}
 	case constants.TenureUnit_Month:
-		expr = cronexpr.MustParse(fmt.Sprintf("59 23 %d * *", startTime.Day()))
+		nextTime = addMonths(startTime, anchorDay, periods)
+	case constants.TenureUnit_Quarter:
+		nextTime = addMonths(startTime, anchorDay, 3*periods)
 	case constants.TenureUnit_Year:
-		expr = cronexpr.MustParse(fmt.Sprintf("59 23 %d %d *", startTime.Day(), startTime.Month()))
+		nextTime = addMonths(startTime, anchorDay, 12*periods)
+	default:
+		return nil
 	}
+	return &nextTime
+}
 
-	if expr != nil {
-		nextTime = expr.Next(startTime)
-		return &nextTime
+// addMonths lands on the anchor day of the month the given months later, or on its last day when the month is shorter
+func addMonths(startTime time.Time, anchorDay, months int) time.Time {
+	// the first day of a month never overflows into the next one
+	firstDay := time.Date(startTime.Year(), startTime.Month()+time.Month(months), 1, 0, 0, 0, 0, startTime.Location())
+	lastDay := firstDay.AddDate(0, 1, -1).Day()
+	if anchorDay > lastDay {
+		anchorDay = lastDay
 	}
-	return nil
+	return GetEndOfDay(time.Date(firstDay.Year(), firstDay.Month(), anchorDay, 0, 0, 0, 0, startTime.Location()))
+}
+
+// GetEndOfDay follows the due time of the generated tenure schedules
+func GetEndOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 0, 0, t.Location())
+}
+
+func GetStartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// GetDaysBetween counts the calendar days from start to end, negative when end is before start
+func GetDaysBetween(start, end time.Time) int {
+	end = end.In(start.Location())
+	// compared in UTC so a daylight saving shift does not cut a day short
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(endDate.Sub(startDate).Hours() / 24)
 }
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"loan-payment/constants"
+)
+
+func newTestEndOfDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 23, 59, 0, 0, time.UTC)
+}
+
+func TestGetTenureSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		anchor   time.Time
+		timeUnit constants.TenureUnit
+		periods  int
+		want     time.Time
+	}{
+		{name: "day", anchor: newTestEndOfDay(2026, 2, 28), timeUnit: constants.TenureUnit_Day, periods: 1, want: newTestEndOfDay(2026, 3, 1)},
+		{name: "week", anchor: newTestEndOfDay(2026, 1, 1), timeUnit: constants.TenureUnit_Week, periods: 2, want: newTestEndOfDay(2026, 1, 15)},
+		{name: "bi-week", anchor: newTestEndOfDay(2026, 1, 1), timeUnit: constants.TenureUnit_BiWeek, periods: 3, want: newTestEndOfDay(2026, 2, 12)},
+		{name: "semi-month to the 15th", anchor: newTestEndOfDay(2026, 2, 3), timeUnit: constants.TenureUnit_SemiMonth, periods: 1, want: newTestEndOfDay(2026, 2, 15)},
+		{name: "semi-month to the month end", anchor: newTestEndOfDay(2026, 2, 3), timeUnit: constants.TenureUnit_SemiMonth, periods: 2, want: newTestEndOfDay(2026, 2, 28)},
+		{name: "semi-month next month", anchor: newTestEndOfDay(2026, 2, 3), timeUnit: constants.TenureUnit_SemiMonth, periods: 3, want: newTestEndOfDay(2026, 3, 15)},
+
+		{name: "month mid-month", anchor: newTestEndOfDay(2026, 1, 15), timeUnit: constants.TenureUnit_Month, periods: 1, want: newTestEndOfDay(2026, 2, 15)},
+		{name: "month 31st into february", anchor: newTestEndOfDay(2026, 1, 31), timeUnit: constants.TenureUnit_Month, periods: 1, want: newTestEndOfDay(2026, 2, 28)},
+		{name: "month 31st into a leap february", anchor: newTestEndOfDay(2024, 1, 31), timeUnit: constants.TenureUnit_Month, periods: 1, want: newTestEndOfDay(2024, 2, 29)},
+		{name: "month 31st back to the 31st", anchor: newTestEndOfDay(2026, 1, 31), timeUnit: constants.TenureUnit_Month, periods: 2, want: newTestEndOfDay(2026, 3, 31)},
+		{name: "month 31st into a 30 day month", anchor: newTestEndOfDay(2026, 1, 31), timeUnit: constants.TenureUnit_Month, periods: 3, want: newTestEndOfDay(2026, 4, 30)},
+		{name: "month 29th into february", anchor: newTestEndOfDay(2026, 1, 29), timeUnit: constants.TenureUnit_Month, periods: 1, want: newTestEndOfDay(2026, 2, 28)},
+		{name: "month across the year end", anchor: newTestEndOfDay(2025, 12, 31), timeUnit: constants.TenureUnit_Month, periods: 2, want: newTestEndOfDay(2026, 2, 28)},
+
+		{name: "quarter 30th into february", anchor: newTestEndOfDay(2025, 11, 30), timeUnit: constants.TenureUnit_Quarter, periods: 1, want: newTestEndOfDay(2026, 2, 28)},
+		{name: "quarter 30th back to the 30th", anchor: newTestEndOfDay(2025, 11, 30), timeUnit: constants.TenureUnit_Quarter, periods: 2, want: newTestEndOfDay(2026, 5, 30)},
+		{name: "quarter 31st into a 30 day month", anchor: newTestEndOfDay(2026, 3, 31), timeUnit: constants.TenureUnit_Quarter, periods: 1, want: newTestEndOfDay(2026, 6, 30)},
+
+		{name: "year leap day", anchor: newTestEndOfDay(2024, 2, 29), timeUnit: constants.TenureUnit_Year, periods: 1, want: newTestEndOfDay(2025, 2, 28)},
+		{name: "year leap day to the next leap year", anchor: newTestEndOfDay(2024, 2, 29), timeUnit: constants.TenureUnit_Year, periods: 4, want: newTestEndOfDay(2028, 2, 29)},
+	}
+
+	for _, tt := range tests {
+		got := GetTenureSchedule(tt.anchor, tt.timeUnit, tt.periods)
+		if got == nil {
+			t.Errorf("%s: got no schedule", tt.name)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := GetTenureSchedule(newTestEndOfDay(2026, 1, 1), constants.TenureUnit(0), 1); got != nil {
+		t.Errorf("an unknown tenure unit got %v, want no schedule", got)
+	}
+}
+
+func TestAddTenurePeriods(t *testing.T) {
+	tests := []struct {
+		name      string
+		startTime time.Time
+		anchorDay int
+		timeUnit  constants.TenureUnit
+		periods   int
+		want      time.Time
+	}{
+		{name: "clamped month end back to the anchor", startTime: newTestEndOfDay(2026, 2, 28), anchorDay: 31, timeUnit: constants.TenureUnit_Month, periods: 1, want: newTestEndOfDay(2026, 3, 31)},
+		{name: "clamped quarter back to the anchor", startTime: newTestEndOfDay(2026, 2, 28), anchorDay: 30, timeUnit: constants.TenureUnit_Quarter, periods: 1, want: newTestEndOfDay(2026, 5, 30)},
+		{name: "clamped again", startTime: newTestEndOfDay(2026, 3, 31), anchorDay: 31, timeUnit: constants.TenureUnit_Month, periods: 11, want: newTestEndOfDay(2027, 2, 28)},
+		{name: "day ignores the anchor day", startTime: newTestEndOfDay(2026, 2, 28), anchorDay: 31, timeUnit: constants.TenureUnit_Day, periods: 2, want: newTestEndOfDay(2026, 3, 2)},
+	}
+
+	for _, tt := range tests {
+		got := AddTenurePeriods(tt.startTime, tt.anchorDay, tt.timeUnit, tt.periods)
+		if got == nil {
+			t.Errorf("%s: got no schedule", tt.name)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}